@@ -0,0 +1,157 @@
+// Copyright 2014 SteelSeries ApS.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// This package implements a basic LISP interpretor for embedding in a go program for scripting.
+// This file implements backtraces for errors raised during evaluation.
+
+package golisp
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// One link of the "Evaling ..." chain the evaluator wraps around an error.
+type EvalError struct {
+	Expr     *Data
+	Location *SourceLocation
+	Err      error
+}
+
+func (self *EvalError) Error() string {
+	if self.Location != nil {
+		return fmt.Sprintf("\n%s: Evaling %s. %s", self.Location, String(self.Expr), self.Err)
+	}
+	return fmt.Sprintf("\nEvaling %s. %s", String(self.Expr), self.Err)
+}
+
+func (self *EvalError) Unwrap() error {
+	return self.Err
+}
+
+func (self *EvalError) As(target interface{}) bool {
+	return backtraceAs(self, target)
+}
+
+func evalError(expr *Data, err error) error {
+	return &EvalError{Expr: expr, Location: SourceLocationOf(expr), Err: err}
+}
+
+// One link of the chain for each user function the error passed out of.
+type FunctionError struct {
+	Function *Function
+	Args     *Data
+	Err      error
+}
+
+func (self *FunctionError) Error() string {
+	return fmt.Sprintf("In '%s': %s", self.Function.Name, self.Err)
+}
+
+func (self *FunctionError) Unwrap() error {
+	return self.Err
+}
+
+func (self *FunctionError) As(target interface{}) bool {
+	return backtraceAs(self, target)
+}
+
+func functionError(f *Function, localEnv *SymbolTableFrame, err error) error {
+	return &FunctionError{Function: f, Args: f.argumentValues(localEnv), Err: err}
+}
+
+type BacktraceFrame struct {
+	Function string
+	Args     *Data
+	Expr     *Data
+	Location *SourceLocation
+}
+
+// The user function calls an error passed through, innermost first, along
+// with the expression that failed. Retrieve one from any error returned by
+// the evaluator with errors.As. Calls in tail position replace their caller,
+// so the caller does not appear.
+type Backtrace struct {
+	Frames   []*BacktraceFrame
+	Expr     *Data
+	Location *SourceLocation
+	Err      error
+}
+
+func (self *Backtrace) Error() string {
+	return self.Err.Error()
+}
+
+func (self *Backtrace) Unwrap() error {
+	return self.Err
+}
+
+func (self *Backtrace) String() string {
+	lines := make([]string, 0, len(self.Frames)+1)
+	lines = append(lines, self.Err.Error())
+	for i, frame := range self.Frames {
+		line := fmt.Sprintf("%d: (%s", i, frame.Function)
+		for a := frame.Args; NotNilP(a); a = Cdr(a) {
+			line += " " + String(Car(a))
+		}
+		line += ")"
+		if frame.Location != nil {
+			line += fmt.Sprintf(" at %s", frame.Location)
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// A list of frames, innermost first, each with function:, args:, expr: and
+// location: slots.
+func (self *Backtrace) ToLisp() *Data {
+	frames := make([]*Data, 0, len(self.Frames))
+	for _, frame := range self.Frames {
+		f := &FrameMap{Data: make(FrameMapData)}
+		f.Set("function:", StringWithValue(frame.Function))
+		f.Set("args:", frame.Args)
+		f.Set("expr:", frame.Expr)
+		if frame.Location != nil {
+			f.Set("location:", StringWithValue(frame.Location.String()))
+		} else {
+			f.Set("location:", nil)
+		}
+		frames = append(frames, FrameWithValue(f))
+	}
+	return ArrayToList(frames)
+}
+
+func BacktraceOf(err error) *Backtrace {
+	bt := &Backtrace{}
+	var call *EvalError
+	for ; err != nil; err = errors.Unwrap(err) {
+		switch link := err.(type) {
+		case *EvalError:
+			call = link
+			bt.Expr = link.Expr
+			if link.Location != nil {
+				bt.Location = link.Location
+			}
+		case *FunctionError:
+			frame := &BacktraceFrame{Function: link.Function.Name, Args: link.Args}
+			if call != nil {
+				frame.Expr, frame.Location = call.Expr, call.Location
+			}
+			bt.Frames = append([]*BacktraceFrame{frame}, bt.Frames...)
+			call = nil
+		}
+		bt.Err = err
+	}
+	return bt
+}
+
+func backtraceAs(err error, target interface{}) bool {
+	if bt, ok := target.(**Backtrace); ok {
+		*bt = BacktraceOf(err)
+		return true
+	}
+	return false
+}
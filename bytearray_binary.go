@@ -0,0 +1,338 @@
+// Copyright 2014 SteelSeries ApS.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// This package implements a basic LISP interpretor for embedding in a go program for scripting.
+// This file implements reading and writing binary numbers in bytearrays.
+
+package golisp
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"unicode"
+	"unsafe"
+)
+
+// A kind of number stored in a bytearray.
+type binaryField struct {
+	Name   string
+	Size   int
+	Signed bool
+	Float  bool
+}
+
+var binaryFields = []binaryField{
+	{Name: "u8", Size: 1},
+	{Name: "s8", Size: 1, Signed: true},
+	{Name: "u16", Size: 2},
+	{Name: "s16", Size: 2, Signed: true},
+	{Name: "u32", Size: 4},
+	{Name: "s32", Size: 4, Signed: true},
+	{Name: "u64", Size: 8},
+	{Name: "s64", Size: 8, Signed: true},
+	{Name: "f32", Size: 4, Float: true},
+	{Name: "f64", Size: 8, Float: true},
+}
+
+func (self binaryField) decode(b []byte, order binary.ByteOrder) *Data {
+	var bits uint64
+	switch self.Size {
+	case 1:
+		bits = uint64(b[0])
+	case 2:
+		bits = uint64(order.Uint16(b))
+	case 4:
+		bits = uint64(order.Uint32(b))
+	default:
+		bits = order.Uint64(b)
+	}
+
+	switch {
+	case self.Float && self.Size == 4:
+		return FloatWithValue(float64(math.Float32frombits(uint32(bits))))
+	case self.Float:
+		return FloatWithValue(math.Float64frombits(bits))
+	case self.Signed:
+		// Sign extend from the field's size.
+		shift := uint(64 - 8*self.Size)
+		return IntegerWithValue(int64(bits<<shift) >> shift)
+	case bits > math.MaxInt64:
+		return ExactIntegerWithValue(new(big.Int).SetUint64(bits))
+	default:
+		return IntegerWithValue(int64(bits))
+	}
+}
+
+// The range of values an integer field holds.
+func (self binaryField) bounds() (low *big.Int, high *big.Int) {
+	bits := uint(8 * self.Size)
+	if self.Signed {
+		low = new(big.Int).Neg(new(big.Int).Lsh(big.NewInt(1), bits-1))
+		high = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), bits-1), big.NewInt(1))
+	} else {
+		low = big.NewInt(0)
+		high = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), bits), big.NewInt(1))
+	}
+	return
+}
+
+func (self binaryField) encode(value *Data, b []byte, order binary.ByteOrder) error {
+	var bits uint64
+	if self.Float {
+		if !NumberP(value) {
+			return fmt.Errorf("%s value must be a number, but was %s", self.Name, String(value))
+		}
+		if self.Size == 4 {
+			bits = uint64(math.Float32bits(float32(FloatValue(value))))
+		} else {
+			bits = math.Float64bits(FloatValue(value))
+		}
+	} else {
+		if !ExactIntegerP(value) {
+			return fmt.Errorf("%s value must be an integer, but was %s", self.Name, String(value))
+		}
+		n := bigIntValue(value)
+		low, high := self.bounds()
+		if n.Cmp(low) < 0 || n.Cmp(high) > 0 {
+			return fmt.Errorf("%s value must be between %s and %s, but was %s", self.Name, low, high, n)
+		}
+		if n.Sign() < 0 {
+			bits = uint64(n.Int64())
+		} else {
+			bits = n.Uint64()
+		}
+	}
+
+	switch self.Size {
+	case 1:
+		b[0] = byte(bits)
+	case 2:
+		order.PutUint16(b, uint16(bits))
+	case 4:
+		order.PutUint32(b, uint32(bits))
+	default:
+		order.PutUint64(b, bits)
+	}
+	return nil
+}
+
+// The largest number of bytes a pack format can describe.
+const maxPackSize = 1 << 24
+
+// One item of a pack format: count numbers, count pad bytes or a byte string
+// of count bytes.
+type packItem struct {
+	Field   binaryField
+	Pad     bool
+	Bytes   bool
+	Boolean bool
+	Count   int
+}
+
+func (self packItem) size() int {
+	if self.Pad || self.Bytes {
+		return self.Count
+	}
+	return self.Count * self.Field.Size
+}
+
+// The number of values the item packs or unpacks.
+func (self packItem) values() int {
+	switch {
+	case self.Pad:
+		return 0
+	case self.Bytes:
+		return 1
+	default:
+		return self.Count
+	}
+}
+
+var packCodes = map[rune]binaryField{
+	'b': binaryFields[1],
+	'B': binaryFields[0],
+	'?': binaryFields[0],
+	'h': binaryFields[3],
+	'H': binaryFields[2],
+	'i': binaryFields[5],
+	'I': binaryFields[4],
+	'l': binaryFields[5],
+	'L': binaryFields[4],
+	'q': binaryFields[7],
+	'Q': binaryFields[6],
+	'f': binaryFields[8],
+	'd': binaryFields[9],
+}
+
+// Parses a pack format in the style of Python's struct module: an optional
+// byte order of < or = (little-endian, the default) or > or ! (big-endian)
+// followed by codes, each of which may have a repeat count. There is no
+// alignment padding, so @ for native order and alignment is rejected. The
+// format can describe at most maxPackSize bytes.
+func parsePackFormat(format string) (items []packItem, order binary.ByteOrder, err error) {
+	size := 0
+	chars := []rune(format)
+	order = binary.LittleEndian
+	if len(chars) > 0 {
+		switch chars[0] {
+		case '<', '=':
+			chars = chars[1:]
+		case '>', '!':
+			order = binary.BigEndian
+			chars = chars[1:]
+		case '@':
+			err = errors.New("native byte order and alignment ('@') are not supported")
+			return
+		}
+	}
+
+	for i := 0; i < len(chars); i++ {
+		if unicode.IsSpace(chars[i]) {
+			continue
+		}
+
+		count := 1
+		if unicode.IsDigit(chars[i]) {
+			start := i
+			for i < len(chars) && unicode.IsDigit(chars[i]) {
+				i++
+			}
+			count, err = strconv.Atoi(string(chars[start:i]))
+			if err != nil || count > maxPackSize {
+				err = fmt.Errorf("format count %s is larger than %d", string(chars[start:i]), maxPackSize)
+				return
+			}
+			if i == len(chars) {
+				err = errors.New("format ends with a count")
+				return
+			}
+		}
+
+		var item packItem
+		code := chars[i]
+		switch code {
+		case 'x':
+			item = packItem{Pad: true, Count: count}
+		case 's':
+			item = packItem{Bytes: true, Count: count}
+		default:
+			field, ok := packCodes[code]
+			if !ok {
+				err = fmt.Errorf("unknown format code '%c'", code)
+				return
+			}
+			item = packItem{Field: field, Boolean: code == '?', Count: count}
+		}
+
+		size += item.size()
+		if size > maxPackSize {
+			err = fmt.Errorf("format describes more than %d bytes", maxPackSize)
+			return
+		}
+		items = append(items, item)
+	}
+	return
+}
+
+func packSize(items []packItem) (size int) {
+	for _, item := range items {
+		size += item.size()
+	}
+	return
+}
+
+// Packs a list of values into bytes. Byte strings take a string or bytearray,
+// which is truncated or padded with zeros to the item's size.
+func Pack(format string, values *Data) (packed []byte, err error) {
+	items, order, err := parsePackFormat(format)
+	if err != nil {
+		return
+	}
+
+	packed = make([]byte, packSize(items))
+	offset := 0
+	for _, item := range items {
+		if item.Pad {
+			offset += item.Count
+			continue
+		}
+		for j := 0; j < item.values(); j++ {
+			if NilP(values) {
+				return nil, errors.New("fewer values than the format needs")
+			}
+			value := Car(values)
+			values = Cdr(values)
+
+			switch {
+			case item.Bytes:
+				if StringP(value) {
+					copy(packed[offset:offset+item.Count], StringValue(value))
+				} else if ObjectP(value) && ObjectType(value) == "[]byte" {
+					copy(packed[offset:offset+item.Count], *(*[]byte)(ObjectValue(value)))
+				} else {
+					return nil, fmt.Errorf("%ds needs a string or bytearray, but was given %s", item.Count, String(value))
+				}
+				offset += item.Count
+			case item.Boolean:
+				if BooleanValue(value) {
+					packed[offset] = 1
+				}
+				offset++
+			default:
+				err = item.Field.encode(value, packed[offset:offset+item.Field.Size], order)
+				if err != nil {
+					return
+				}
+				offset += item.Field.Size
+			}
+		}
+	}
+
+	if NotNilP(values) {
+		return nil, errors.New("more values than the format needs")
+	}
+	return
+}
+
+// Unpacks the values described by format from the bytes, returning them as a
+// list. Bytes after the format's size are ignored.
+func Unpack(format string, b []byte) (values *Data, err error) {
+	items, order, err := parsePackFormat(format)
+	if err != nil {
+		return
+	}
+
+	size := packSize(items)
+	if len(b) < size {
+		return nil, fmt.Errorf("the format needs %d bytes but was given %d", size, len(b))
+	}
+
+	unpacked := make([]*Data, 0, len(items))
+	offset := 0
+	for _, item := range items {
+		if item.Pad {
+			offset += item.Count
+			continue
+		}
+		for j := 0; j < item.values(); j++ {
+			switch {
+			case item.Bytes:
+				field := append([]byte{}, b[offset:offset+item.Count]...)
+				unpacked = append(unpacked, ObjectWithTypeAndValue("[]byte", unsafe.Pointer(&field)))
+				offset += item.Count
+			case item.Boolean:
+				unpacked = append(unpacked, BooleanWithValue(b[offset] != 0))
+				offset++
+			default:
+				unpacked = append(unpacked, item.Field.decode(b[offset:offset+item.Field.Size], order))
+				offset += item.Field.Size
+			}
+		}
+	}
+	return ArrayToList(unpacked), nil
+}
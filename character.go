@@ -0,0 +1,88 @@
+// Copyright 2014 SteelSeries ApS.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// This package implements a basic LISP interpretor for embedding in a go program for scripting.
+// This file implements the names used to read and write characters.
+
+package golisp
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Characters that are written by name. The first name listed for a
+// character is the one it is printed with.
+var characterNames = []struct {
+	Name string
+	Ch   rune
+}{
+	{"space", ' '},
+	{"newline", '\n'},
+	{"tab", '\t'},
+	{"return", '\r'},
+	{"nul", 0},
+	{"null", 0},
+	{"altmode", 27},
+	{"escape", 27},
+	{"backspace", 8},
+	{"delete", 127},
+	{"rubout", 127},
+	{"alarm", 7},
+	{"linefeed", '\n'},
+	{"page", 12},
+}
+
+// The character written as #\name, where name is a single character, one of
+// the character names, or x followed by a hex code point.
+func CharacterNamed(name string) (ch rune, err error) {
+	if utf8.RuneCountInString(name) == 1 {
+		ch, _ = utf8.DecodeRuneInString(name)
+		return
+	}
+
+	for _, entry := range characterNames {
+		if strings.EqualFold(entry.Name, name) {
+			return entry.Ch, nil
+		}
+	}
+
+	if name[0] == 'x' || name[0] == 'U' || name[0] == 'u' {
+		code, parseErr := strconv.ParseUint(strings.TrimPrefix(name[1:], "+"), 16, 32)
+		if parseErr == nil && utf8.ValidRune(rune(code)) {
+			return rune(code), nil
+		}
+	}
+
+	return 0, fmt.Errorf("Unknown character name: #\\%s", name)
+}
+
+// How ch is written so that it reads back as itself.
+func CharacterName(ch rune) string {
+	for _, entry := range characterNames {
+		if entry.Ch == ch {
+			return entry.Name
+		}
+	}
+
+	if unicode.IsGraphic(ch) {
+		return string(ch)
+	}
+
+	return fmt.Sprintf("x%x", ch)
+}
+
+// How ch is spelled out for people to read, as by ~:C: named characters are
+// capitalised.
+func CharacterDisplayName(ch rune) string {
+	for _, entry := range characterNames {
+		if entry.Ch == ch {
+			return strings.ToUpper(entry.Name[:1]) + entry.Name[1:]
+		}
+	}
+	return CharacterName(ch)
+}
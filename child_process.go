@@ -0,0 +1,163 @@
+// Copyright 2014 SteelSeries ApS.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// This package implements a basic LISP interpretor for embedding in a go program for scripting.
+// This file implements child processes started by exec and friends.
+
+package golisp
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"runtime"
+	"sync"
+	"time"
+	"unsafe"
+)
+
+type ChildProcessOptions struct {
+	Stdin   []byte
+	Env     []string
+	Dir     string
+	Timeout time.Duration
+	Capture bool
+	// Start the child in its own process group, so that killing it also
+	// kills the processes it started. Implied by Timeout.
+	KillGroup bool
+}
+
+// A running or finished child process. When Capture is set its output is
+// collected in Stdout and Stderr, which can only be read once Done is closed;
+// otherwise the output is discarded.
+type ChildProcess struct {
+	Cmd       *exec.Cmd
+	Capture   bool
+	KillGroup bool
+	Stdout    bytes.Buffer
+	Stderr    bytes.Buffer
+	Done      chan struct{}
+	WaitErr   error
+	TimedOut  bool
+	mutex     sync.Mutex
+}
+
+func StartChildProcess(name string, args []string, options ChildProcessOptions) (child *ChildProcess, err error) {
+	child = &ChildProcess{
+		Cmd:       exec.Command(name, args...),
+		Capture:   options.Capture,
+		KillGroup: options.KillGroup || options.Timeout > 0,
+		Done:      make(chan struct{}),
+	}
+	if options.Capture {
+		child.Cmd.Stdout = &child.Stdout
+		child.Cmd.Stderr = &child.Stderr
+	}
+	child.Cmd.Dir = options.Dir
+	if options.Stdin != nil {
+		child.Cmd.Stdin = bytes.NewReader(options.Stdin)
+	}
+	if options.Env != nil {
+		child.Cmd.Env = append(os.Environ(), options.Env...)
+	}
+	if child.KillGroup {
+		startProcessGroup(child.Cmd)
+	}
+
+	err = child.Cmd.Start()
+	if err != nil {
+		return nil, err
+	}
+
+	var timer *time.Timer
+	if options.Timeout > 0 {
+		timer = time.AfterFunc(options.Timeout, func() {
+			child.mutex.Lock()
+			child.TimedOut = true
+			child.mutex.Unlock()
+			child.Kill()
+		})
+	}
+
+	go func() {
+		child.WaitErr = child.Cmd.Wait()
+		if timer != nil {
+			timer.Stop()
+		}
+		close(child.Done)
+	}()
+	return
+}
+
+// Starts a command line with the system's shell.
+func StartShellCommand(command string, options ChildProcessOptions) (child *ChildProcess, err error) {
+	if runtime.GOOS == "windows" {
+		return StartChildProcess("cmd", []string{"/C", command}, options)
+	}
+	return StartChildProcess("/bin/sh", []string{"-c", command}, options)
+}
+
+// Waits for the process to finish, returning a frame with its exit-code:,
+// stdout:, stderr: and timed-out: slots. The exit code is -1 when the process
+// was killed or couldn't be waited for, and the output slots are nil unless
+// the output was captured.
+func (self *ChildProcess) Wait() *Data {
+	<-self.Done
+	f := FrameMap{}
+	f.Data = make(FrameMapData)
+	exitCode := -1
+	if self.Cmd.ProcessState != nil {
+		exitCode = self.Cmd.ProcessState.ExitCode()
+	}
+	f.Data["exit-code:"] = IntegerWithValue(int64(exitCode))
+	if self.Capture {
+		f.Data["stdout:"] = StringWithValue(self.Stdout.String())
+		f.Data["stderr:"] = StringWithValue(self.Stderr.String())
+	} else {
+		f.Data["stdout:"] = EmptyCons()
+		f.Data["stderr:"] = EmptyCons()
+	}
+	self.mutex.Lock()
+	f.Data["timed-out:"] = BooleanWithValue(self.TimedOut)
+	self.mutex.Unlock()
+	return FrameWithValue(&f)
+}
+
+// Kills the process if it is still running, along with any processes it
+// started when KillGroup is set.
+func (self *ChildProcess) Kill() error {
+	select {
+	case <-self.Done:
+		return nil
+	default:
+	}
+
+	var err error
+	if self.KillGroup {
+		err = killProcessGroup(self.Cmd)
+	} else {
+		err = self.Cmd.Process.Kill()
+	}
+	if err != nil {
+		select {
+		case <-self.Done:
+			// It finished on its own in the meantime.
+			return nil
+		default:
+		}
+	}
+	return err
+}
+
+func ChildProcessWithValue(child *ChildProcess) *Data {
+	return ObjectWithTypeAndValue("ChildProcess", unsafe.Pointer(child))
+}
+
+func ChildProcessP(d *Data) bool {
+	return ObjectP(d) && ObjectType(d) == "ChildProcess"
+}
+
+func ChildProcessValue(d *Data) *ChildProcess {
+	return (*ChildProcess)(ObjectValue(d))
+}
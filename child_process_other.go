@@ -0,0 +1,25 @@
+//go:build !windows
+// +build !windows
+
+// Copyright 2014 SteelSeries ApS.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// This package implements a basic LISP interpretor for embedding in a go program for scripting.
+// This file kills child processes along with the processes they started, so
+// that a killed shell command doesn't leave anything holding its output open.
+
+package golisp
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+func startProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
+
+func killProcessGroup(cmd *exec.Cmd) error {
+	return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+}
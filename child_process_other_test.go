@@ -0,0 +1,52 @@
+//go:build !windows
+// +build !windows
+
+// Copyright 2014 SteelSeries ApS.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// This package implements a basic LISP interpretor for embedding in a go program for scripting.
+// This file tests which child processes get their own process group.
+
+package golisp
+
+import (
+	"time"
+
+	. "gopkg.in/check.v1"
+)
+
+type ChildProcessSuite struct{}
+
+var _ = Suite(&ChildProcessSuite{})
+
+func startsProcessGroup(child *ChildProcess) bool {
+	<-child.Done
+	return child.Cmd.SysProcAttr != nil && child.Cmd.SysProcAttr.Setpgid
+}
+
+func (s *ChildProcessSuite) TestPlainChildSharesProcessGroup(c *C) {
+	child, err := StartShellCommand("true", ChildProcessOptions{})
+	c.Assert(err, IsNil)
+	c.Assert(startsProcessGroup(child), Equals, false)
+}
+
+func (s *ChildProcessSuite) TestKillableChildGetsProcessGroup(c *C) {
+	child, err := StartShellCommand("true", ChildProcessOptions{KillGroup: true})
+	c.Assert(err, IsNil)
+	c.Assert(startsProcessGroup(child), Equals, true)
+
+	child, err = StartShellCommand("true", ChildProcessOptions{Timeout: time.Minute})
+	c.Assert(err, IsNil)
+	c.Assert(startsProcessGroup(child), Equals, true)
+}
+
+func (s *ChildProcessSuite) TestExecWaitSharesProcessGroup(c *C) {
+	child, err := startCommand("exec-wait", InternalMakeList(StringWithValue("true")), true, false, Global)
+	c.Assert(err, IsNil)
+	c.Assert(startsProcessGroup(child), Equals, false)
+
+	child, err = startCommand("exec", InternalMakeList(StringWithValue("true")), false, true, Global)
+	c.Assert(err, IsNil)
+	c.Assert(startsProcessGroup(child), Equals, true)
+}
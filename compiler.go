@@ -0,0 +1,1091 @@
+// Copyright 2014 SteelSeries ApS.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// This package implements a basic LISP interpretor for embedding in a go program for scripting.
+// This file implements the compiler, which turns function bodies into trees of closures.
+
+package golisp
+
+import (
+	"fmt"
+	"sync/atomic"
+	"unsafe"
+)
+
+// Functions are compiled the first time they are called. When this is off,
+// or something is tracing or stepping through evaluation, they are
+// interpreted instead.
+var CompilationEnabled bool = true
+
+func compilationActive() bool {
+	return CompilationEnabled && !LispTrace && !DebugSingleStep && DebugCurrentFrame == nil
+}
+
+// A compiled expression. Code compiled in tail position may return a
+// TailCall that its caller has to finish.
+type compiledCode func(env *SymbolTableFrame) (*Data, error)
+
+// The names the compiler knows are bound in a frame, in slot order. Each
+// scope corresponds to exactly one frame at runtime, so a lexical variable
+// is addressed by how many frames up it is and its slot there.
+type compileScope struct {
+	names  []*Data
+	parent *compileScope
+}
+
+type compiledFunction struct {
+	scope *compileScope
+	body  compiledCode
+}
+
+type cachedBinding struct {
+	env     *SymbolTableFrame
+	binding *Binding
+	deleted int64
+}
+
+type compiledExpansion struct {
+	expansion *Data
+	code      compiledCode
+}
+
+var notCompilable = &compiledFunction{}
+
+func (self *compileScope) index(sym *Data) int {
+	name := StringValue(sym)
+	for i, n := range self.names {
+		if n == sym || StringValue(n) == name {
+			return i
+		}
+	}
+	return -1
+}
+
+func (self *compileScope) add(sym *Data) {
+	if self.index(sym) < 0 {
+		self.names = append(self.names, sym)
+	}
+}
+
+// Adds the names defined at the top level of body, as they will be bound in
+// this scope's frame.
+func (self *compileScope) addDefinitions(body *Data) {
+	for c := body; NotNilP(c); c = Cdr(c) {
+		form := Car(c)
+		if !PairP(form) || !SymbolP(Car(form)) {
+			continue
+		}
+		switch StringValue(Car(form)) {
+		case "define":
+			target := Cadr(form)
+			if PairP(target) {
+				target = Car(target)
+			}
+			if SymbolP(target) {
+				self.add(target)
+			}
+		case "define-record-type":
+			self.addRecordTypeDefinitions(form)
+		case "begin":
+			self.addDefinitions(Cdr(form))
+		}
+	}
+}
+
+// Adds the type, constructor, predicate, accessor and modifier names that a
+// define-record-type binds.
+func (self *compileScope) addRecordTypeDefinitions(form *Data) {
+	names := []*Data{Second(form), Third(form), Fourth(form)}
+	if PairP(Third(form)) {
+		names[1] = Car(Third(form))
+	}
+	for c := Cdr(Cdddr(form)); NotNilP(c); c = Cdr(c) {
+		if PairP(Car(c)) {
+			names = append(names, Cadr(Car(c)), Caddr(Car(c)))
+		}
+	}
+	for _, name := range names {
+		if SymbolP(name) {
+			self.add(name)
+		}
+	}
+}
+
+func (self *compileScope) lookup(sym *Data) (depth int, index int) {
+	for s := self; s != nil; s = s.parent {
+		if index = s.index(sym); index >= 0 {
+			return
+		}
+		depth++
+	}
+	return -1, -1
+}
+
+func (self *compileScope) depth() (depth int) {
+	for s := self; s != nil; s = s.parent {
+		depth++
+	}
+	return
+}
+
+// The binding in the given slot of the frame depth levels up, or nil if it
+// hasn't been made yet or the name could be shadowed at runtime, by a binding
+// made outside the compiler's view or by a frame slot.
+func lexicalBinding(env *SymbolTableFrame, depth int, index int) *Binding {
+	if depth > 0 && env.HasFrame() {
+		return nil
+	}
+	e := env
+	for i := 0; i < depth; i++ {
+		if e.extended {
+			return nil
+		}
+		e = e.Parent
+	}
+	return e.Locals[index]
+}
+
+// The environment compiled code was closed over, depth frames up, or nil if
+// a name could be shadowed before getting there.
+func enclosingEnv(env *SymbolTableFrame, depth int) *SymbolTableFrame {
+	if env.HasFrame() {
+		return nil
+	}
+	e := env
+	for i := 0; i < depth; i++ {
+		if e.extended {
+			return nil
+		}
+		e = e.Parent
+	}
+	return e
+}
+
+func markSlotFunction(value *Data, slotFunction int32) {
+	if FunctionP(value) {
+		atomic.StoreInt32(&FunctionValue(value).SlotFunction, slotFunction)
+	}
+}
+
+func wrapError(form *Data, err error) error {
+	if form == nil {
+		return err
+	}
+	return evalError(form, err)
+}
+
+// Runs code that is in tail position within form, which only takes
+// responsibility for its errors when form itself isn't in tail position.
+func finish(form *Data, tail bool, code compiledCode, env *SymbolTableFrame) (result *Data, err error) {
+	result, err = code(env)
+	if err != nil && !tail {
+		err = wrapError(form, err)
+	}
+	return
+}
+
+func compileFunction(params *Data, body *Data, parent *compileScope) *compiledFunction {
+	scope := &compileScope{parent: parent}
+	var p *Data
+	for p = params; NotNilP(p) && PairP(p); p = Cdr(p) {
+		if !SymbolP(Car(p)) || scope.index(Car(p)) >= 0 {
+			return notCompilable
+		}
+		scope.names = append(scope.names, Car(p))
+	}
+	if NotNilP(p) {
+		if !SymbolP(p) || scope.index(p) >= 0 {
+			return notCompilable
+		}
+		scope.names = append(scope.names, p)
+	}
+	scope.addDefinitions(body)
+	return &compiledFunction{scope: scope, body: compileSequence(body, scope, nil, true)}
+}
+
+func compileConstant(value *Data) compiledCode {
+	return func(env *SymbolTableFrame) (*Data, error) {
+		return value, nil
+	}
+}
+
+func compileExpr(d *Data, scope *compileScope, tail bool) compiledCode {
+	if d == nil {
+		return compileConstant(nil)
+	}
+	switch d.Type {
+	case SymbolType:
+		return compileReference(d, scope, false)
+	case ConsCellType:
+	default:
+		return compileConstant(d)
+	}
+
+	d = postProcessShortcuts(d)
+	if NilP(d) {
+		return func(env *SymbolTableFrame) (*Data, error) {
+			return EmptyCons(), nil
+		}
+	}
+
+	if head := Car(d); SymbolP(head) {
+		if depth, _ := scope.lookup(head); depth < 0 {
+			if binding, found := Global.FindBindingFor(head); found && PrimitiveP(binding.Val) && PrimitiveValue(binding.Val).Special {
+				return compileSpecialForm(PrimitiveValue(binding.Val), d, scope, tail)
+			}
+		}
+	}
+	return compileApplication(d, scope, tail)
+}
+
+func compileSequence(exprs *Data, scope *compileScope, form *Data, tail bool) compiledCode {
+	codes := make([]compiledCode, 0, Length(exprs))
+	for c := exprs; NotNilP(c); c = Cdr(c) {
+		codes = append(codes, compileExpr(Car(c), scope, tail && NilP(Cdr(c))))
+	}
+	if len(codes) == 0 {
+		return compileConstant(nil)
+	}
+	if len(codes) == 1 && form == nil {
+		return codes[0]
+	}
+
+	last := codes[len(codes)-1]
+	codes = codes[:len(codes)-1]
+	return func(env *SymbolTableFrame) (*Data, error) {
+		for _, code := range codes {
+			if _, err := code(env); err != nil {
+				return nil, wrapError(form, err)
+			}
+		}
+		return finish(form, tail, last, env)
+	}
+}
+
+func compileReference(sym *Data, scope *compileScope, needFunction bool) compiledCode {
+	if NakedP(sym) {
+		return compileConstant(sym)
+	}
+
+	depth, index := scope.lookup(sym)
+	if depth < 0 {
+		outer := scope.depth()
+		name := StringValue(sym)
+		var cache unsafe.Pointer
+		return func(env *SymbolTableFrame) (*Data, error) {
+			e := enclosingEnv(env, outer)
+			if e == nil {
+				return env.ValueOfWithFunctionSlotCheck(sym, needFunction), nil
+			}
+
+			// a binding made directly in the enclosing environment can't be
+			// shadowed, so it can be reused until bindings are removed
+			deleted := atomic.LoadInt64(&deletedBindings)
+			if c := (*cachedBinding)(atomic.LoadPointer(&cache)); c != nil && c.env == e && c.deleted == deleted {
+				markSlotFunction(c.binding.Val, 0)
+				return c.binding.Val, nil
+			}
+			binding, found := e.BindingNamed(name)
+			if found {
+				atomic.StorePointer(&cache, unsafe.Pointer(&cachedBinding{env: e, binding: binding, deleted: deleted}))
+			} else {
+				binding, found = e.FindBindingFor(sym)
+			}
+			if found {
+				markSlotFunction(binding.Val, 0)
+				return binding.Val, nil
+			}
+			return EmptyCons(), nil
+		}
+	}
+
+	slotFunction := int32(0)
+	if depth == 0 {
+		slotFunction = 1
+	}
+	return func(env *SymbolTableFrame) (*Data, error) {
+		if binding := lexicalBinding(env, depth, index); binding != nil {
+			markSlotFunction(binding.Val, slotFunction)
+			return binding.Val, nil
+		}
+		return env.ValueOfWithFunctionSlotCheck(sym, needFunction), nil
+	}
+}
+
+func compileArguments(args *Data, scope *compileScope) []compiledCode {
+	codes := make([]compiledCode, 0, Length(args))
+	for a := args; NotNilP(a); a = Cdr(a) {
+		codes = append(codes, compileExpr(Car(a), scope, false))
+	}
+	return codes
+}
+
+// Evaluates the arguments into a list, as primitives take them.
+func evaluateCompiledArgumentList(codes []compiledCode, env *SymbolTableFrame) (args *Data, err error) {
+	if len(codes) == 0 {
+		return EmptyCons(), nil
+	}
+	var last *Data
+	for _, code := range codes {
+		var value *Data
+		value, err = code(env)
+		if err != nil {
+			return
+		}
+		if value == nil {
+			value = EmptyCons()
+		}
+		cell := Cons(value, nil)
+		if last == nil {
+			args = cell
+		} else {
+			ConsValue(last).Cdr = cell
+		}
+		last = cell
+	}
+	return
+}
+
+func evaluateCompiledArguments(codes []compiledCode, env *SymbolTableFrame) (values []*Data, err error) {
+	values = make([]*Data, len(codes))
+	for i, code := range codes {
+		values[i], err = code(env)
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+func compileApplication(d *Data, scope *compileScope, tail bool) compiledCode {
+	var head compiledCode
+	if SymbolP(Car(d)) {
+		head = compileReference(Car(d), scope, true)
+	} else {
+		head = compileExpr(Car(d), scope, false)
+	}
+	args := compileArguments(Cdr(d), scope)
+	var expansion unsafe.Pointer
+
+	return func(env *SymbolTableFrame) (result *Data, err error) {
+		function, err := head(env)
+		if err != nil {
+			return
+		}
+		if NilP(function) {
+			return nil, nilFunctionError(d, env)
+		}
+
+		switch function.Type {
+		case FunctionType:
+			f := FunctionValue(function)
+			if !DebugSingleStep && DebugOnEntry.Has(f.Name) {
+				DebugRepl(env)
+			}
+			if err = f.checkArgumentCount(len(args)); err != nil {
+				return nil, evalError(d, err)
+			}
+			var values []*Data
+			if values, err = evaluateCompiledArguments(args, env); err != nil {
+				return nil, evalError(d, err)
+			}
+			var frame *FrameMap
+			if atomic.LoadInt32(&f.SlotFunction) == 1 && env.HasFrame() {
+				frame = env.Frame
+			}
+			if tail {
+				return &Data{Type: TailCallType, Value: unsafe.Pointer(&TailCall{Expr: d, Env: env, Function: f, Args: values, Frame: frame})}, nil
+			}
+			result, err = f.callWithArguments(values, env, frame)
+		case PrimitiveType:
+			p := PrimitiveValue(function)
+			if p.Special {
+				break
+			}
+			if err = p.checkApplication(len(args), env); err != nil {
+				return nil, evalError(d, err)
+			}
+			var values *Data
+			if values, err = evaluateCompiledArgumentList(args, env); err != nil {
+				return nil, evalError(d, err)
+			}
+			result, err = p.call(values, env)
+			if err == nil && TailCallP(result) && !tail {
+				result, err = Eval(TailCallValue(result).Expr, TailCallValue(result).Env)
+			}
+		case MacroType:
+			var expr *Data
+			if expr, err = MacroValue(function).ExpandAtCallSite(d, env); err != nil {
+				return nil, evalError(d, err)
+			}
+			c := (*compiledExpansion)(atomic.LoadPointer(&expansion))
+			if c == nil || c.expansion != expr {
+				c = &compiledExpansion{expansion: expr, code: compileExpr(expr, scope, tail)}
+				atomic.StorePointer(&expansion, unsafe.Pointer(c))
+			}
+			return finish(d, tail, c.code, env)
+		case ContinuationType:
+			var values *Data
+			if values, err = evaluateCompiledArgumentList(args, env); err != nil {
+				return nil, evalError(d, err)
+			}
+			result, err = ContinuationValue(function).ApplyWithoutEval(values, env)
+		default:
+			err = MakeCondition(InapplicableObjectCondition, fmt.Sprintf("%s when function or macro expected for %s.", TypeName(TypeOf(function)), String(function)), nil)
+		}
+
+		if err != nil {
+			return nil, evalError(d, err)
+		}
+		if PrimitiveP(function) && PrimitiveValue(function).Special {
+			// it wasn't a special form when this was compiled
+			if tail {
+				return TailCallWithExprAndEnv(d, env), nil
+			}
+			return evalHelper(d, env, false)
+		}
+		return
+	}
+}
+
+// Special forms the compiler doesn't know how to compile, or that are
+// malformed, are applied as the evaluator would, leaving the primitive to
+// report any problems.
+func compileSpecialForm(p *PrimitiveFunction, d *Data, scope *compileScope, tail bool) compiledCode {
+	var code compiledCode
+	if !p.IsRestricted && p.checkArgumentCount(Length(Cdr(d))) {
+		switch p.Name {
+		case "quote":
+			code = compileQuote(d)
+		case "if":
+			code = compileIf(d, scope, tail)
+		case "when":
+			code = compileWhen(d, scope, tail, true)
+		case "unless":
+			code = compileWhen(d, scope, tail, false)
+		case "cond":
+			code = compileCond(d, scope, tail)
+		case "case":
+			code = compileCase(d, scope, tail)
+		case "begin":
+			code = compileSequence(Cdr(d), scope, d, tail)
+		case "and":
+			code = compileAndOr(d, scope, tail, false)
+		case "or":
+			code = compileAndOr(d, scope, tail, true)
+		case "lambda":
+			code = compileLambda(d, scope)
+		case "named-lambda":
+			code = compileNamedLambda(d, scope)
+		case "define":
+			code = compileDefine(d, scope)
+		case "set!":
+			code = compileSet(d, scope)
+		case "let":
+			if SymbolP(Cadr(d)) {
+				code = compileNamedLet(d, scope, tail)
+			} else {
+				code = compileLet(d, scope, tail, false, false)
+			}
+		case "let*":
+			code = compileLet(d, scope, tail, true, false)
+		case "letrec":
+			code = compileLet(d, scope, tail, false, true)
+		case "do":
+			code = compileDo(d, scope, tail)
+		}
+	}
+	if code != nil {
+		return code
+	}
+
+	args := Cdr(d)
+	return func(env *SymbolTableFrame) (result *Data, err error) {
+		result, err = p.internalApply(args, env)
+		if err == nil && TailCallP(result) && !tail {
+			result, err = Eval(TailCallValue(result).Expr, TailCallValue(result).Env)
+		}
+		if err != nil {
+			return nil, evalError(d, err)
+		}
+		return
+	}
+}
+
+func compileQuote(d *Data) compiledCode {
+	if Cadr(d) == nil {
+		return func(env *SymbolTableFrame) (*Data, error) {
+			return EmptyCons(), nil
+		}
+	}
+	return compileConstant(Cadr(d))
+}
+
+func compileIf(d *Data, scope *compileScope, tail bool) compiledCode {
+	test := compileExpr(Second(d), scope, false)
+	consequent := compileExpr(Third(d), scope, tail)
+	alternative := compileExpr(Fourth(d), scope, tail)
+	return func(env *SymbolTableFrame) (*Data, error) {
+		c, err := test(env)
+		if err != nil {
+			return nil, evalError(d, err)
+		}
+		if BooleanValue(c) {
+			return finish(d, tail, consequent, env)
+		}
+		return finish(d, tail, alternative, env)
+	}
+}
+
+func compileWhen(d *Data, scope *compileScope, tail bool, when bool) compiledCode {
+	test := compileExpr(Second(d), scope, false)
+	body := compileSequence(Cddr(d), scope, d, tail)
+	return func(env *SymbolTableFrame) (*Data, error) {
+		c, err := test(env)
+		if err != nil {
+			return nil, evalError(d, err)
+		}
+		if BooleanValue(c) == when {
+			return body(env)
+		}
+		return nil, nil
+	}
+}
+
+type compiledClause struct {
+	test   compiledCode
+	data   *Data
+	isElse bool
+	body   compiledCode
+}
+
+func compileCond(d *Data, scope *compileScope, tail bool) compiledCode {
+	var clauses []compiledClause
+	for c := Cdr(d); NotNilP(c); c = Cdr(c) {
+		clause := Car(c)
+		if !PairP(clause) {
+			return nil
+		}
+		compiled := compiledClause{isElse: IsEqual(Car(clause), Intern("else")), body: compileSequence(Cdr(clause), scope, d, tail)}
+		if !compiled.isElse {
+			compiled.test = compileExpr(Car(clause), scope, false)
+		}
+		clauses = append(clauses, compiled)
+	}
+
+	return func(env *SymbolTableFrame) (*Data, error) {
+		for _, clause := range clauses {
+			if clause.isElse {
+				return clause.body(env)
+			}
+			c, err := clause.test(env)
+			if err != nil {
+				return nil, evalError(d, err)
+			}
+			if BooleanValue(c) {
+				return clause.body(env)
+			}
+		}
+		return nil, nil
+	}
+}
+
+func compileCase(d *Data, scope *compileScope, tail bool) compiledCode {
+	key := compileExpr(Second(d), scope, false)
+	var clauses []compiledClause
+	for c := Cddr(d); NotNilP(c); c = Cdr(c) {
+		clause := Car(c)
+		if !PairP(clause) {
+			return nil
+		}
+		compiled := compiledClause{isElse: IsEqual(Car(clause), Intern("else")), data: Car(clause), body: compileSequence(Cdr(clause), scope, d, tail)}
+		if !compiled.isElse && !ListP(compiled.data) {
+			return nil
+		}
+		clauses = append(clauses, compiled)
+	}
+
+	return func(env *SymbolTableFrame) (*Data, error) {
+		keyValue, err := key(env)
+		if err != nil {
+			return nil, evalError(d, err)
+		}
+		for _, clause := range clauses {
+			if clause.isElse {
+				return clause.body(env)
+			}
+			for v := clause.data; NotNilP(v); v = Cdr(v) {
+				if IsEqual(Car(v), keyValue) {
+					return clause.body(env)
+				}
+			}
+		}
+		return nil, nil
+	}
+}
+
+func compileAndOr(d *Data, scope *compileScope, tail bool, or bool) compiledCode {
+	codes := make([]compiledCode, 0, Length(Cdr(d)))
+	for c := Cdr(d); NotNilP(c); c = Cdr(c) {
+		codes = append(codes, compileExpr(Car(c), scope, tail && NilP(Cdr(c))))
+	}
+	if len(codes) == 0 {
+		return compileConstant(nil)
+	}
+
+	last := codes[len(codes)-1]
+	codes = codes[:len(codes)-1]
+	return func(env *SymbolTableFrame) (*Data, error) {
+		for _, code := range codes {
+			result, err := code(env)
+			if err != nil {
+				return nil, evalError(d, err)
+			}
+			if BooleanValue(result) == or {
+				return result, nil
+			}
+		}
+		return finish(d, tail, last, env)
+	}
+}
+
+func makeCompiledFunction(name string, params *Data, body *Data, code *compiledFunction, env *SymbolTableFrame) *Data {
+	f := MakeFunction(name, params, body, env)
+	f.code = unsafe.Pointer(code)
+	return FunctionWithValue(f)
+}
+
+func compileLambda(d *Data, scope *compileScope) compiledCode {
+	params := Cadr(d)
+	if !PairP(params) {
+		return nil
+	}
+	body := Cddr(d)
+	code := compileFunction(params, body, scope)
+	return func(env *SymbolTableFrame) (*Data, error) {
+		return makeCompiledFunction("unnamed", params, body, code, env), nil
+	}
+}
+
+func compileNamedLambda(d *Data, scope *compileScope) compiledCode {
+	if !PairP(Cadr(d)) || !SymbolP(Car(Cadr(d))) {
+		return nil
+	}
+	name := StringValue(Car(Cadr(d)))
+	params := Cdr(Cadr(d))
+	body := Cddr(d)
+	code := compileFunction(params, body, scope)
+	return func(env *SymbolTableFrame) (*Data, error) {
+		return makeCompiledFunction(name, params, body, code, env), nil
+	}
+}
+
+// Binds name in env as define does, using its slot when it has one.
+func bindDefinition(env *SymbolTableFrame, index int, name *Data, value *Data) (err error) {
+	if index < 0 {
+		_, err = env.BindLocallyTo(name, value)
+		return
+	}
+	env.Mutex.Lock()
+	if binding := env.Locals[index]; binding != nil {
+		binding.Val = value
+	} else {
+		env.Locals[index] = BindingWithSymbolAndValue(name, value)
+	}
+	env.Mutex.Unlock()
+	return
+}
+
+func compileDefine(d *Data, scope *compileScope) compiledCode {
+	target := Cadr(d)
+	if SymbolP(target) {
+		index := scope.index(target)
+		value := compileExpr(Caddr(d), scope, false)
+		return func(env *SymbolTableFrame) (*Data, error) {
+			v, err := value(env)
+			if err == nil {
+				err = bindDefinition(env, index, target, v)
+			}
+			if err != nil {
+				return nil, evalError(d, err)
+			}
+			return v, nil
+		}
+	}
+
+	if !PairP(target) || !SymbolP(Car(target)) {
+		return nil
+	}
+	name := Car(target)
+	index := scope.index(name)
+	params := Cdr(target)
+	body := Cddr(d)
+	code := compileFunction(params, body, scope)
+	return func(env *SymbolTableFrame) (*Data, error) {
+		if PrimitiveP(env.ValueOf(name)) {
+			return nil, evalError(d, ProcessError(fmt.Sprintf("Primitive function %s can not be redefined.", StringValue(name)), env))
+		}
+		f := makeCompiledFunction(StringValue(name), params, body, code, env)
+		if err := bindDefinition(env, index, name, f); err != nil {
+			return nil, evalError(d, err)
+		}
+		return f, nil
+	}
+}
+
+func compileSet(d *Data, scope *compileScope) compiledCode {
+	sym := Cadr(d)
+	if !SymbolP(sym) {
+		return nil
+	}
+	depth, index := scope.lookup(sym)
+	value := compileExpr(Caddr(d), scope, false)
+	return func(env *SymbolTableFrame) (result *Data, err error) {
+		v, err := value(env)
+		if err != nil {
+			return nil, evalError(d, err)
+		}
+		if depth >= 0 {
+			if binding := lexicalBinding(env, depth, index); binding != nil {
+				binding.Val = v
+				return v, nil
+			}
+		}
+		result, err = env.SetTo(sym, v)
+		if err != nil {
+			return nil, evalError(d, err)
+		}
+		return
+	}
+}
+
+// The names and initial value expressions of a let or do binding list, or
+// false if it is malformed.
+func bindingNamesAndValues(bindings *Data) (names []*Data, values []*Data, ok bool) {
+	for c := bindings; NotNilP(c); c = Cdr(c) {
+		binding := Car(c)
+		if !PairP(binding) || !SymbolP(Car(binding)) {
+			return nil, nil, false
+		}
+		names = append(names, Car(binding))
+		values = append(values, Cadr(binding))
+	}
+	return names, values, true
+}
+
+func compileLet(d *Data, scope *compileScope, tail bool, star bool, rec bool) compiledCode {
+	if !PairP(Cadr(d)) {
+		return nil
+	}
+	names, values, ok := bindingNamesAndValues(Cadr(d))
+	if !ok {
+		return nil
+	}
+
+	localScope := &compileScope{parent: scope}
+	for _, name := range names {
+		localScope.add(name)
+	}
+	localScope.addDefinitions(Cddr(d))
+
+	valueScope := scope
+	if star || rec {
+		valueScope = localScope
+	}
+	slots := make([]int, len(names))
+	codes := make([]compiledCode, len(names))
+	for i, name := range names {
+		slots[i] = localScope.index(name)
+		codes[i] = compileExpr(values[i], valueScope, false)
+	}
+	body := compileSequence(Cddr(d), localScope, d, tail)
+
+	return func(env *SymbolTableFrame) (*Data, error) {
+		localEnv := newSymbolTableFrameWithLocals(env, nil, "let", localScope.names)
+		localEnv.Previous = env
+		valueEnv := env
+		if star || rec {
+			valueEnv = localEnv
+		}
+		bindings := make([]Binding, len(names))
+		if rec {
+			for i, name := range names {
+				bindings[i].Sym = name
+				localEnv.Locals[slots[i]] = &bindings[i]
+			}
+		}
+		for i, name := range names {
+			v, err := codes[i](valueEnv)
+			if err != nil {
+				return nil, evalError(d, err)
+			}
+			if binding := localEnv.Locals[slots[i]]; binding != nil {
+				binding.Val = v
+			} else {
+				bindings[i] = Binding{Sym: name, Val: v}
+				localEnv.Locals[slots[i]] = &bindings[i]
+			}
+		}
+		return body(localEnv)
+	}
+}
+
+func compileNamedLet(d *Data, scope *compileScope, tail bool) compiledCode {
+	name := Cadr(d)
+	if !PairP(Caddr(d)) {
+		return nil
+	}
+	names, values, ok := bindingNamesAndValues(Caddr(d))
+	if !ok {
+		return nil
+	}
+
+	loopScope := &compileScope{names: []*Data{name}, parent: scope}
+	params := ArrayToList(names)
+	body := Cdddr(d)
+	code := compileFunction(params, body, loopScope)
+	codes := make([]compiledCode, len(values))
+	for i, value := range values {
+		codes[i] = compileExpr(value, scope, false)
+	}
+
+	return func(env *SymbolTableFrame) (result *Data, err error) {
+		localEnv := newSymbolTableFrameWithLocals(env, nil, StringValue(name), loopScope.names)
+		localEnv.Previous = env
+		loop := makeCompiledFunction(StringValue(name), params, body, code, localEnv)
+		localEnv.Locals[0] = BindingWithSymbolAndValue(name, loop)
+
+		args, err := evaluateCompiledArguments(codes, env)
+		if err != nil {
+			return nil, evalError(d, err)
+		}
+		if tail {
+			return &Data{Type: TailCallType, Value: unsafe.Pointer(&TailCall{Expr: d, Env: env, Function: FunctionValue(loop), Args: args})}, nil
+		}
+		result, err = FunctionValue(loop).callWithArguments(args, env, nil)
+		if err != nil {
+			return nil, evalError(d, err)
+		}
+		return
+	}
+}
+
+func compileDo(d *Data, scope *compileScope, tail bool) compiledCode {
+	testClause := Caddr(d)
+	if !PairP(Cadr(d)) || !PairP(testClause) {
+		return nil
+	}
+	names, values, ok := bindingNamesAndValues(Cadr(d))
+	if !ok {
+		return nil
+	}
+
+	localScope := &compileScope{parent: scope}
+	for _, name := range names {
+		localScope.add(name)
+	}
+	localScope.addDefinitions(Cdddr(d))
+
+	slots := make([]int, len(names))
+	inits := make([]compiledCode, len(names))
+	steps := make([]compiledCode, len(names))
+	i := 0
+	for c := Cadr(d); NotNilP(c); c = Cdr(c) {
+		slots[i] = localScope.index(names[i])
+		inits[i] = compileExpr(values[i], scope, false)
+		if step := Third(Car(c)); NotNilP(step) {
+			steps[i] = compileExpr(step, localScope, false)
+		}
+		i++
+	}
+	test := compileExpr(Car(testClause), localScope, false)
+	exit := compileSequence(Cdr(testClause), localScope, d, tail)
+	body := compileArguments(Cdddr(d), localScope)
+
+	return func(env *SymbolTableFrame) (result *Data, err error) {
+		localEnv := newSymbolTableFrameWithLocals(env, nil, "do", localScope.names)
+		localEnv.Previous = env
+		bindings := make([]Binding, len(names))
+		for i, name := range names {
+			v, err := inits[i](env)
+			if err != nil {
+				return nil, evalError(d, err)
+			}
+			if binding := localEnv.Locals[slots[i]]; binding != nil {
+				binding.Val = v
+			} else {
+				bindings[i] = Binding{Sym: name, Val: v}
+				localEnv.Locals[slots[i]] = &bindings[i]
+			}
+		}
+
+		next := make([]*Data, len(names))
+		for {
+			shouldExit, err := test(localEnv)
+			if err != nil {
+				return nil, evalError(d, err)
+			}
+
+			if BooleanValue(shouldExit) {
+				if NotNilP(Cdr(testClause)) {
+					return exit(localEnv)
+				}
+				return result, nil
+			}
+
+			for _, code := range body {
+				if result, err = code(localEnv); err != nil {
+					return nil, evalError(d, err)
+				}
+			}
+
+			for i, step := range steps {
+				if step != nil {
+					if next[i], err = step(localEnv); err != nil {
+						return nil, evalError(d, err)
+					}
+				} else {
+					next[i] = localEnv.ValueOf(names[i])
+				}
+			}
+			for i, name := range names {
+				if binding := localEnv.Locals[slots[i]]; binding != nil {
+					binding.Val = next[i]
+				} else if _, err = localEnv.BindLocallyTo(name, next[i]); err != nil {
+					return nil, evalError(d, err)
+				}
+			}
+		}
+	}
+}
+
+// Returns the compiled form of the function, compiling it the first time, or
+// nil if it should be interpreted.
+func (self *Function) compiled() *compiledFunction {
+	if !compilationActive() || self.Env == nil {
+		return nil
+	}
+	code := (*compiledFunction)(atomic.LoadPointer(&self.code))
+	if code == nil {
+		code = compileFunction(self.Params, self.Body, nil)
+		atomic.StorePointer(&self.code, unsafe.Pointer(code))
+	}
+	if code.body == nil {
+		return nil
+	}
+	return code
+}
+
+func (self *Function) evaluateArguments(args *Data, env *SymbolTableFrame) (values []*Data, err error) {
+	err = self.checkArgumentCount(Length(args))
+	if err != nil {
+		return
+	}
+	values = make([]*Data, 0, Length(args))
+	var value *Data
+	for a := args; NotNilP(a); a = Cdr(a) {
+		value, err = Eval(Car(a), env)
+		if err != nil {
+			return
+		}
+		values = append(values, value)
+	}
+	return
+}
+
+func (self *Function) makeCompiledEnv(code *compiledFunction, args []*Data, argEnv *SymbolTableFrame, frame *FrameMap) (localEnv *SymbolTableFrame, err error) {
+	err = self.checkArgumentCount(len(args))
+	if err != nil {
+		return
+	}
+
+	localEnv = newSymbolTableFrameWithLocals(self.Env, frame, self.Name, code.scope.names)
+	localEnv.Previous = argEnv
+	err = self.bindSelfAndProcess(localEnv, argEnv, frame)
+	if err != nil {
+		return
+	}
+
+	count := self.RequiredArgCount
+	if self.VarArgs {
+		count++
+	}
+	bindings := make([]Binding, count)
+	for i := 0; i < self.RequiredArgCount; i++ {
+		bindings[i] = Binding{Sym: code.scope.names[i], Val: args[i]}
+		localEnv.Locals[i] = &bindings[i]
+	}
+	if self.VarArgs {
+		i := self.RequiredArgCount
+		bindings[i] = Binding{Sym: code.scope.names[i], Val: ArrayToList(args[i:])}
+		localEnv.Locals[i] = &bindings[i]
+	}
+	return
+}
+
+// Runs the compiled body of the function, and those of any compiled functions
+// it calls in tail position. Returns the function whose body finished along
+// with its environment, as the result may be a tail expression for the
+// interpreter to evaluate on its behalf.
+func (self *Function) runCompiled(code *compiledFunction, args []*Data, argEnv *SymbolTableFrame, frame *FrameMap) (result *Data, f *Function, localEnv *SymbolTableFrame, err error) {
+	f = self
+	for {
+		localEnv, err = f.makeCompiledEnv(code, args, argEnv, frame)
+		if err != nil {
+			return
+		}
+
+		var guid int64
+		if ProfileEnabled {
+			guid = atomic.AddInt64(&ProfileGUID, 1) - 1
+			ProfileEnter("func", f.Name, guid)
+		}
+		result, err = code.body(localEnv)
+		if ProfileEnabled {
+			ProfileExit("func", f.Name, guid)
+		}
+		if err != nil {
+			err = functionError(f, localEnv, err)
+			return
+		}
+
+		if !TailCallP(result) || TailCallValue(result).Function == nil {
+			return
+		}
+		tailCall := TailCallValue(result)
+		next := tailCall.Function.compiled()
+		if next == nil {
+			result, err = tailCall.Function.interpretedApply(ArrayToList(tailCall.Args), argEnv, tailCall.Frame, false)
+		} else {
+			err = tailCall.Function.checkArgumentCount(len(tailCall.Args))
+		}
+		if err != nil {
+			err = functionError(f, localEnv, evalError(tailCall.Expr, err))
+			return
+		}
+		if next == nil {
+			return
+		}
+		f, code, args, frame = tailCall.Function, next, tailCall.Args, tailCall.Frame
+	}
+}
+
+func (self *Function) callCompiled(code *compiledFunction, args []*Data, argEnv *SymbolTableFrame, frame *FrameMap) (result *Data, err error) {
+	result, f, localEnv, err := self.runCompiled(code, args, argEnv, frame)
+	if err == nil && TailCallP(result) {
+		result, err = Eval(TailCallValue(result).Expr, TailCallValue(result).Env)
+		if err != nil {
+			err = functionError(f, localEnv, err)
+		}
+	}
+	return
+}
+
+// Calls the function with arguments that have already been evaluated.
+func (self *Function) callWithArguments(args []*Data, argEnv *SymbolTableFrame, frame *FrameMap) (result *Data, err error) {
+	if code := self.compiled(); code != nil {
+		return self.callCompiled(code, args, argEnv, frame)
+	}
+	return self.interpretedApply(ArrayToList(args), argEnv, frame, false)
+}
@@ -0,0 +1,106 @@
+// Copyright 2014 SteelSeries ApS.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// This package implements a basic LISP interpretor for embedding in a go program for scripting.
+// This file implements conditions: structured errors with a type, message, and irritants.
+
+package golisp
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Condition types, named as in MIT Scheme. Every type other than error
+// is a specialization of error.
+const (
+	ErrorCondition                   = "error"
+	SimpleErrorCondition             = "simple-error"
+	PrimitiveProcedureErrorCondition = "primitive-procedure-error"
+	WrongTypeArgumentCondition       = "wrong-type-argument"
+	BadRangeArgumentCondition        = "bad-range-argument"
+	WrongNumberOfArgumentsCondition  = "wrong-number-of-arguments"
+	UnboundVariableCondition         = "unbound-variable"
+	InapplicableObjectCondition      = "inapplicable-object"
+	DivideByZeroCondition            = "divide-by-zero"
+	SyntaxErrorCondition             = "syntax-error"
+)
+
+var ConditionTypes = []string{
+	ErrorCondition,
+	SimpleErrorCondition,
+	PrimitiveProcedureErrorCondition,
+	WrongTypeArgumentCondition,
+	BadRangeArgumentCondition,
+	WrongNumberOfArgumentsCondition,
+	UnboundVariableCondition,
+	InapplicableObjectCondition,
+	DivideByZeroCondition,
+	SyntaxErrorCondition,
+}
+
+// A Condition is a go error, so it travels up through the evaluator like any
+// other error and can be recovered from a wrapped error with errors.As.
+type Condition struct {
+	Type      string
+	Message   string
+	Irritants *Data
+}
+
+func MakeCondition(conditionType string, message string, irritants *Data) *Condition {
+	return &Condition{Type: conditionType, Message: message, Irritants: irritants}
+}
+
+func (self *Condition) Error() string {
+	return self.ReportString()
+}
+
+// The message followed by the irritants, separated by spaces. Irritant
+// strings are written with quotes so they can be told apart from the message.
+func (self *Condition) ReportString() string {
+	parts := []string{self.Message}
+	for c := self.Irritants; NotNilP(c); c = Cdr(c) {
+		if StringP(Car(c)) {
+			parts = append(parts, fmt.Sprintf("\"%s\"", StringValue(Car(c))))
+		} else {
+			parts = append(parts, String(Car(c)))
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+func (self *Condition) IsA(conditionType string) bool {
+	return self.Type == conditionType || conditionType == ErrorCondition
+}
+
+// Carries a non-condition object passed to raise up to the nearest handler.
+type RaisedObject struct {
+	Object *Data
+}
+
+func (self *RaisedObject) Error() string {
+	return fmt.Sprintf("The object %s, passed as the first argument to raise, is not the correct type.", String(self.Object))
+}
+
+// Returns the object a handler should receive for err: the object that was
+// raised, the condition that was signalled, or a primitive-procedure-error
+// condition wrapping any other go error.
+func ConditionObject(err error) *Data {
+	var raised *RaisedObject
+	if errors.As(err, &raised) {
+		return raised.Object
+	}
+	var condition *Condition
+	if errors.As(err, &condition) {
+		return ConditionWithValue(condition)
+	}
+	return ConditionWithValue(MakeCondition(PrimitiveProcedureErrorCondition, err.Error(), nil))
+}
+
+// Whether handlers should see err. Continuation invocations are control
+// transfers rather than errors and must pass through untouched.
+func IsHandleableError(err error) bool {
+	return err != nil && !IsContinuationInvocation(err)
+}
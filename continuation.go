@@ -0,0 +1,94 @@
+// Copyright 2014 SteelSeries ApS.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// This package implements a basic LISP interpretor for embedding in a go program for scripting.
+// This file implements escaping continuations.
+
+package golisp
+
+import (
+	"errors"
+	"sync/atomic"
+)
+
+// Continuations are escape-only: invoking one unwinds back to the call/cc
+// that created it by returning a ContinuationInvocation as an error.
+// Functions, special forms, and primitives that call back into lisp (map,
+// for-each, sort, ...) already pass errors up, so they need no special support.
+type Continuation struct {
+	Active int32
+}
+
+type ContinuationInvocation struct {
+	Continuation *Continuation
+	Value        *Data
+	Thunk        *Data
+}
+
+func (self *ContinuationInvocation) Error() string {
+	return "A continuation was invoked outside of its dynamic extent."
+}
+
+func IsContinuationInvocation(err error) bool {
+	var invocation *ContinuationInvocation
+	return errors.As(err, &invocation)
+}
+
+func (self *Continuation) invoke(args *Data, thunk *Data, env *SymbolTableFrame) (result *Data, err error) {
+	if atomic.LoadInt32(&self.Active) == 0 {
+		err = ProcessError("Only escaping continuations are supported, and this one has already been exited.", env)
+		return
+	}
+
+	var value *Data
+	if Length(args) == 1 {
+		value = Car(args)
+	} else {
+		value = args
+	}
+	return nil, &ContinuationInvocation{Continuation: self, Value: value, Thunk: thunk}
+}
+
+func (self *Continuation) Apply(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	argArray := make([]*Data, 0, Length(args))
+	var argValue *Data
+	for a := args; NotNilP(a); a = Cdr(a) {
+		argValue, err = Eval(Car(a), env)
+		if err != nil {
+			return
+		}
+		argArray = append(argArray, argValue)
+	}
+	return self.invoke(ArrayToList(argArray), nil, env)
+}
+
+func (self *Continuation) ApplyWithoutEval(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	return self.invoke(args, nil, env)
+}
+
+// Unwinds to the continuation and then calls thunk there, passing its result
+// to the continuation.
+func (self *Continuation) Within(thunk *Data, env *SymbolTableFrame) (result *Data, err error) {
+	return self.invoke(nil, thunk, env)
+}
+
+// Applies f to a fresh continuation, returning either f's result or the value
+// that the continuation was invoked with.
+func CallWithCurrentContinuation(f *Data, env *SymbolTableFrame) (result *Data, err error) {
+	k := &Continuation{Active: 1}
+	defer atomic.StoreInt32(&k.Active, 0)
+
+	result, err = ApplyWithoutEval(f, InternalMakeList(ContinuationWithValue(k)), env)
+	for err != nil {
+		var invocation *ContinuationInvocation
+		if !errors.As(err, &invocation) || invocation.Continuation != k {
+			return
+		}
+		if invocation.Thunk == nil {
+			return invocation.Value, nil
+		}
+		result, err = ApplyWithoutEval(invocation.Thunk, nil, env)
+	}
+	return
+}
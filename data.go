@@ -11,6 +11,7 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"math/big"
 	"os"
 	"sort"
 	"strings"
@@ -37,11 +38,40 @@ const (
 	FrameType
 	EnvironmentType
 	PortType
+	ContinuationType
+	ConditionType
+	HashTableType
+	VectorType
+	CharacterType
+	BignumType
+	RationalType
+	RecordType
+	RecordDescriptorType
+	TailCallType
 )
 
 type ConsCell struct {
-	Car *Data
-	Cdr *Data
+	Car  *Data
+	Cdr  *Data
+	info unsafe.Pointer // *consInfo
+}
+
+// What is known about a cell as code: where it was parsed from and, if it is
+// a macro use, its expansion. A cell's info is replaced, never modified, so
+// that code can be shared between processes.
+type consInfo struct {
+	Location  *SourceLocation
+	Macro     *Macro
+	Args      *Data
+	Expansion *Data
+}
+
+func (self *ConsCell) loadInfo() *consInfo {
+	return (*consInfo)(atomic.LoadPointer(&self.info))
+}
+
+func (self *ConsCell) storeInfo(info *consInfo) {
+	atomic.StorePointer(&self.info, unsafe.Pointer(info))
 }
 
 type BoxedObject struct {
@@ -54,6 +84,19 @@ type Data struct {
 	Value unsafe.Pointer
 }
 
+// A pending evaluation handed back by a special form so that the evaluator
+// can continue with it in place instead of recursing.
+type TailCall struct {
+	Expr *Data
+	Env  *SymbolTableFrame
+
+	// Set when compiled code calls a function in tail position, in which case
+	// Expr is the call.
+	Function *Function
+	Args     []*Data
+	Frame    *FrameMap
+}
+
 // Boolean constants
 
 type BooleanBox struct {
@@ -120,6 +163,26 @@ func TypeName(t uint8) string {
 		return "Environment"
 	case PortType:
 		return "Port"
+	case ContinuationType:
+		return "Continuation"
+	case ConditionType:
+		return "Condition"
+	case HashTableType:
+		return "Hash Table"
+	case VectorType:
+		return "Vector"
+	case CharacterType:
+		return "Character"
+	case BignumType:
+		return "Bignum"
+	case RationalType:
+		return "Rational"
+	case RecordType:
+		return "Record"
+	case RecordDescriptorType:
+		return "Record Type"
+	case TailCallType:
+		return "Tail Call"
 	default:
 		return "Unknown"
 	}
@@ -186,8 +249,16 @@ func FloatP(d *Data) bool {
 	return d != nil && TypeOf(d) == FloatType
 }
 
+func BignumP(d *Data) bool {
+	return d != nil && TypeOf(d) == BignumType
+}
+
+func RationalP(d *Data) bool {
+	return d != nil && TypeOf(d) == RationalType
+}
+
 func NumberP(d *Data) bool {
-	return IntegerP(d) || FloatP(d)
+	return IntegerP(d) || FloatP(d) || BignumP(d) || RationalP(d)
 }
 
 func ObjectP(d *Data) bool {
@@ -195,7 +266,7 @@ func ObjectP(d *Data) bool {
 }
 
 func FunctionOrPrimitiveP(d *Data) bool {
-	return d != nil && (TypeOf(d) == FunctionType || TypeOf(d) == PrimitiveType)
+	return d != nil && (TypeOf(d) == FunctionType || TypeOf(d) == PrimitiveType || TypeOf(d) == ContinuationType)
 }
 
 func FunctionP(d *Data) bool {
@@ -222,6 +293,38 @@ func PortP(d *Data) bool {
 	return d != nil && TypeOf(d) == PortType
 }
 
+func ContinuationP(d *Data) bool {
+	return d != nil && TypeOf(d) == ContinuationType
+}
+
+func ConditionP(d *Data) bool {
+	return d != nil && TypeOf(d) == ConditionType
+}
+
+func HashTableP(d *Data) bool {
+	return d != nil && TypeOf(d) == HashTableType
+}
+
+func VectorP(d *Data) bool {
+	return d != nil && TypeOf(d) == VectorType
+}
+
+func CharacterP(d *Data) bool {
+	return d != nil && TypeOf(d) == CharacterType
+}
+
+func RecordP(d *Data) bool {
+	return d != nil && TypeOf(d) == RecordType
+}
+
+func RecordDescriptorP(d *Data) bool {
+	return d != nil && TypeOf(d) == RecordDescriptorType
+}
+
+func TailCallP(d *Data) bool {
+	return d != nil && TypeOf(d) == TailCallType
+}
+
 func EmptyCons() *Data {
 	cell := ConsCell{Car: nil, Cdr: nil}
 	return &Data{Type: ConsCellType, Value: unsafe.Pointer(&cell)}
@@ -348,7 +451,7 @@ func IntegerWithValue(n int64) *Data {
 	return &Data{Type: IntegerType, Value: unsafe.Pointer(&n)}
 }
 
-func FloatWithValue(n float32) *Data {
+func FloatWithValue(n float64) *Data {
 	return &Data{Type: FloatType, Value: unsafe.Pointer(&n)}
 }
 
@@ -389,10 +492,18 @@ func FunctionWithNameParamsBodyAndParent(name string, params *Data, body *Data,
 	return &Data{Type: FunctionType, Value: unsafe.Pointer(MakeFunction(name, params, body, parentEnv))}
 }
 
+func FunctionWithValue(f *Function) *Data {
+	return &Data{Type: FunctionType, Value: unsafe.Pointer(f)}
+}
+
 func MacroWithNameParamsBodyAndParent(name string, params *Data, body *Data, parentEnv *SymbolTableFrame) *Data {
 	return &Data{Type: MacroType, Value: unsafe.Pointer(MakeMacro(name, params, body, parentEnv))}
 }
 
+func MacroWithValue(m *Macro) *Data {
+	return &Data{Type: MacroType, Value: unsafe.Pointer(m)}
+}
+
 func PrimitiveWithNameAndFunc(name string, f *PrimitiveFunction) *Data {
 	return &Data{Type: PrimitiveType, Value: unsafe.Pointer(f)}
 }
@@ -407,7 +518,51 @@ func EnvironmentWithValue(e *SymbolTableFrame) *Data {
 }
 
 func PortWithValue(e *os.File) *Data {
-	return &Data{Type: PortType, Value: unsafe.Pointer(e)}
+	return PortWithReadWriter(e.Name(), e)
+}
+
+func PortWithPort(p *Port) *Data {
+	return &Data{Type: PortType, Value: unsafe.Pointer(p)}
+}
+
+func ContinuationWithValue(k *Continuation) *Data {
+	return &Data{Type: ContinuationType, Value: unsafe.Pointer(k)}
+}
+
+func ConditionWithValue(c *Condition) *Data {
+	return &Data{Type: ConditionType, Value: unsafe.Pointer(c)}
+}
+
+func HashTableWithValue(t *HashTable) *Data {
+	return &Data{Type: HashTableType, Value: unsafe.Pointer(t)}
+}
+
+func VectorWithValue(v []*Data) *Data {
+	return &Data{Type: VectorType, Value: unsafe.Pointer(&v)}
+}
+
+func CharacterWithValue(ch rune) *Data {
+	return &Data{Type: CharacterType, Value: unsafe.Pointer(&ch)}
+}
+
+func BignumWithValue(n *big.Int) *Data {
+	return &Data{Type: BignumType, Value: unsafe.Pointer(n)}
+}
+
+func RationalWithValue(r *big.Rat) *Data {
+	return &Data{Type: RationalType, Value: unsafe.Pointer(r)}
+}
+
+func RecordWithValue(r *Record) *Data {
+	return &Data{Type: RecordType, Value: unsafe.Pointer(r)}
+}
+
+func RecordDescriptorWithValue(t *RecordDescriptor) *Data {
+	return &Data{Type: RecordDescriptorType, Value: unsafe.Pointer(t)}
+}
+
+func TailCallWithExprAndEnv(expr *Data, env *SymbolTableFrame) *Data {
+	return &Data{Type: TailCallType, Value: unsafe.Pointer(&TailCall{Expr: expr, Env: env})}
 }
 
 func ConsValue(d *Data) *ConsCell {
@@ -466,28 +621,60 @@ func IntegerValue(d *Data) int64 {
 	}
 
 	if FloatP(d) {
-		return int64(*((*float32)(d.Value)))
+		return int64(*((*float64)(d.Value)))
+	}
+
+	if BignumP(d) {
+		b := BignumValue(d)
+		if b.IsInt64() {
+			return b.Int64()
+		} else if b.Sign() < 0 {
+			return math.MinInt64
+		}
+		return math.MaxInt64
+	}
+
+	if RationalP(d) {
+		return IntegerValue(truncateExact(d))
 	}
 
 	return 0
 }
 
-func FloatValue(d *Data) float32 {
+func FloatValue(d *Data) float64 {
 	if d == nil {
 		return 0
 	}
 
 	if FloatP(d) {
-		return *((*float32)(d.Value))
+		return *((*float64)(d.Value))
 	}
 
 	if IntegerP(d) {
-		return float32(*((*int64)(d.Value)))
+		return float64(*((*int64)(d.Value)))
+	}
+
+	if BignumP(d) || RationalP(d) {
+		return exactToFloat64(d)
 	}
 
 	return 0
 }
 
+func BignumValue(d *Data) *big.Int {
+	if BignumP(d) {
+		return (*big.Int)(d.Value)
+	}
+	return nil
+}
+
+func RationalValue(d *Data) *big.Rat {
+	if RationalP(d) {
+		return (*big.Rat)(d.Value)
+	}
+	return nil
+}
+
 func StringValue(d *Data) string {
 	if d == nil {
 		return ""
@@ -608,13 +795,109 @@ func EnvironmentValue(d *Data) *SymbolTableFrame {
 	return nil
 }
 
-func PortValue(d *Data) *os.File {
+func PortValue(d *Data) *Port {
 	if d == nil {
 		return nil
 	}
 
 	if PortP(d) {
-		return (*os.File)(d.Value)
+		return (*Port)(d.Value)
+	}
+
+	return nil
+}
+
+func ContinuationValue(d *Data) *Continuation {
+	if d == nil {
+		return nil
+	}
+
+	if ContinuationP(d) {
+		return (*Continuation)(d.Value)
+	}
+
+	return nil
+}
+
+func ConditionValue(d *Data) *Condition {
+	if d == nil {
+		return nil
+	}
+
+	if ConditionP(d) {
+		return (*Condition)(d.Value)
+	}
+
+	return nil
+}
+
+func HashTableValue(d *Data) *HashTable {
+	if d == nil {
+		return nil
+	}
+
+	if HashTableP(d) {
+		return (*HashTable)(d.Value)
+	}
+
+	return nil
+}
+
+func VectorValue(d *Data) []*Data {
+	if d == nil {
+		return nil
+	}
+
+	if VectorP(d) {
+		return *((*[]*Data)(d.Value))
+	}
+
+	return nil
+}
+
+func CharacterValue(d *Data) rune {
+	if d == nil {
+		return 0
+	}
+
+	if CharacterP(d) {
+		return *((*rune)(d.Value))
+	}
+
+	return 0
+}
+
+func RecordValue(d *Data) *Record {
+	if d == nil {
+		return nil
+	}
+
+	if RecordP(d) {
+		return (*Record)(d.Value)
+	}
+
+	return nil
+}
+
+func RecordDescriptorValue(d *Data) *RecordDescriptor {
+	if d == nil {
+		return nil
+	}
+
+	if RecordDescriptorP(d) {
+		return (*RecordDescriptor)(d.Value)
+	}
+
+	return nil
+}
+
+func TailCallValue(d *Data) *TailCall {
+	if d == nil {
+		return nil
+	}
+
+	if TailCallP(d) {
+		return (*TailCall)(d.Value)
 	}
 
 	return nil
@@ -639,6 +922,10 @@ func Length(d *Data) int {
 		return len(dBytes)
 	}
 
+	if d.Type == VectorType {
+		return len(VectorValue(d))
+	}
+
 	if FrameP(d) {
 		frame := FrameValue(d)
 		frame.Mutex.RLock()
@@ -802,6 +1089,19 @@ func Copy(d *Data) *Data {
 			frame.Mutex.RUnlock()
 			return FrameWithValue(&m)
 		}
+	case HashTableType:
+		return HashTableWithValue(HashTableValue(d).Copy())
+	case VectorType:
+		{
+			elements := VectorValue(d)
+			copied := make([]*Data, len(elements))
+			for i, e := range elements {
+				copied[i] = Copy(e)
+			}
+			return VectorWithValue(copied)
+		}
+	case RecordType:
+		return RecordWithValue(RecordValue(d).Copy())
 	case BoxedObjectType:
 		{
 			if ObjectType(d) == "[]byte" {
@@ -891,6 +1191,28 @@ func IsEqual(d *Data, o *Data) bool {
 		return true
 	}
 
+	if HashTableP(d) {
+		return HashTableValue(d).IsEqual(HashTableValue(o))
+	}
+
+	if VectorP(d) {
+		dElements := VectorValue(d)
+		oElements := VectorValue(o)
+		if len(dElements) != len(oElements) {
+			return false
+		}
+		for i := range dElements {
+			if !IsEqual(dElements[i], oElements[i]) {
+				return false
+			}
+		}
+		return true
+	}
+
+	if RecordP(d) {
+		return RecordValue(d).IsEqual(RecordValue(o))
+	}
+
 	// special case for byte arrays
 	if ObjectP(d) && ObjectType(d) == "[]byte" && ObjectType(o) == "[]byte" {
 		dBytes := *(*[]byte)(ObjectValue(d))
@@ -923,6 +1245,16 @@ func IsEqual(d *Data, o *Data) bool {
 		return MacroValue(d) == MacroValue(o)
 	case PrimitiveType:
 		return PrimitiveValue(d) == PrimitiveValue(o)
+	case ContinuationType:
+		return ContinuationValue(d) == ContinuationValue(o)
+	case ConditionType:
+		return ConditionValue(d) == ConditionValue(o)
+	case CharacterType:
+		return CharacterValue(d) == CharacterValue(o)
+	case BignumType:
+		return BignumValue(d).Cmp(BignumValue(o)) == 0
+	case RationalType:
+		return RationalValue(d).Cmp(RationalValue(o)) == 0
 	case BoxedObjectType:
 		return (ObjectType(d) == ObjectType(o)) && (ObjectValue(d) == ObjectValue(o))
 	}
@@ -988,21 +1320,25 @@ func String(d *Data) string {
 		return fmt.Sprintf("(%s . %s)", String(Car(d)), String(Cdr(d)))
 	case IntegerType:
 		return fmt.Sprintf("%d", IntegerValue(d))
+	case BignumType:
+		return BignumValue(d).String()
+	case RationalType:
+		return RationalValue(d).String()
 	case FloatType:
 		{
 			v := FloatValue(d)
-			if math.IsInf(float64(v), 0) {
+			if math.IsInf(v, 0) {
 				sign := "+"
-				if math.Signbit(float64(v)) {
+				if math.Signbit(v) {
 					sign = "-"
 				}
 				return fmt.Sprintf("%sinf", sign)
 			}
-			if math.IsNaN(float64(v)) {
+			if math.IsNaN(v) {
 				return "nan"
 			}
 			raw := fmt.Sprintf("%g", FloatValue(d))
-			if strings.ContainsRune(raw, '.') {
+			if strings.ContainsAny(raw, ".e") {
 				return raw
 			}
 			return fmt.Sprintf("%s.0", raw)
@@ -1054,7 +1390,31 @@ func String(d *Data) string {
 	case EnvironmentType:
 		return fmt.Sprintf("<environment: %s>", EnvironmentValue(d).Name)
 	case PortType:
-		return fmt.Sprintf("<port: %s>", PortValue(d).Name())
+		return fmt.Sprintf("<port: %s>", PortValue(d).Name)
+	case ContinuationType:
+		return "<continuation>"
+	case ConditionType:
+		return fmt.Sprintf("<condition %s: %s>", ConditionValue(d).Type, ConditionValue(d).ReportString())
+	case HashTableType:
+		table := HashTableValue(d)
+		return fmt.Sprintf("<hash table (%s): %d entries>", table.KindName(), table.Size())
+	case VectorType:
+		{
+			elements := VectorValue(d)
+			contents := make([]string, 0, len(elements))
+			for _, e := range elements {
+				contents = append(contents, String(e))
+			}
+			return fmt.Sprintf("#(%s)", strings.Join(contents, " "))
+		}
+	case CharacterType:
+		return fmt.Sprintf("#\\%s", CharacterName(CharacterValue(d)))
+	case RecordType:
+		return RecordValue(d).String()
+	case RecordDescriptorType:
+		return fmt.Sprintf("<record type: %s>", RecordDescriptorValue(d).Name)
+	case TailCallType:
+		return fmt.Sprintf("<tail call: %s>", String(TailCallValue(d).Expr))
 	}
 
 	return ""
@@ -1063,6 +1423,8 @@ func String(d *Data) string {
 func PrintString(d *Data) string {
 	if StringP(d) {
 		return StringValue(d)
+	} else if CharacterP(d) {
+		return string(CharacterValue(d))
 	} else {
 		return String(d)
 	}
@@ -1125,60 +1487,166 @@ func logResult(result *Data, env *SymbolTableFrame) {
 	}
 }
 
-func evalHelper(d *Data, env *SymbolTableFrame, needFunction bool) (result *Data, err error) {
-	if IsInteractive && !DebugEvalInDebugRepl {
-		env.CurrentCode.PushFront(fmt.Sprintf("Eval %s", String(d)))
+// Adds the context that was dropped when evaluation moved into a tail
+// position: the function whose body was being evaluated and the expression
+// that started it all.
+func tailCallError(err error, originalExpr *Data, tailFunction *Function, tailFunctionEnv *SymbolTableFrame) error {
+	if tailFunction != nil {
+		err = functionError(tailFunction, tailFunctionEnv, err)
 	}
+	return evalError(originalExpr, err)
+}
 
-	logEval(d, env)
-
-	if DebugSingleStep {
-		DebugSingleStep = false
-		DebugRepl(env)
+func nilFunctionError(d *Data, env *SymbolTableFrame) error {
+	conditionType := InapplicableObjectCondition
+	if SymbolP(Car(d)) {
+		if _, found := env.FindBindingFor(Car(d)); !found {
+			conditionType = UnboundVariableCondition
+		}
 	}
+	return MakeCondition(conditionType, fmt.Sprintf("Nil when function or macro expected for %s.", String(Car(d))), nil)
+}
 
-	if DebugCurrentFrame != nil && env == DebugCurrentFrame.Previous {
-		DebugCurrentFrame = nil
-		DebugRepl(env)
-	}
+func evalHelper(d *Data, env *SymbolTableFrame, needFunction bool) (result *Data, err error) {
+	originalExpr, originalEnv := d, env
+	inTailPosition := false
+	var tailFunction *Function
+	var tailFunctionEnv *SymbolTableFrame
+	var tailGuid int64
+
+	for {
+		if IsInteractive && !DebugEvalInDebugRepl {
+			env.CurrentCode.PushFront(fmt.Sprintf("Eval %s", String(d)))
+		}
 
-	if d != nil {
-		switch d.Type {
-		case ConsCellType:
-			{
-				d = postProcessShortcuts(d)
+		logEval(d, env)
 
-				// catch empty cons cell
-				if NilP(d) {
-					return EmptyCons(), nil
-				}
+		if DebugSingleStep {
+			DebugSingleStep = false
+			DebugRepl(env)
+		}
+
+		if DebugCurrentFrame != nil && env == DebugCurrentFrame.Previous {
+			DebugCurrentFrame = nil
+			DebugRepl(env)
+		}
+
+		if d == nil || d.Type != ConsCellType {
+			break
+		}
+
+		d = postProcessShortcuts(d)
+
+		// catch empty cons cell
+		if NilP(d) {
+			if tailFunction != nil {
+				ProfileExit("func", tailFunction.Name, tailGuid)
+			}
+			return EmptyCons(), nil
+		}
+
+		var function *Data
+		function, err = evalHelper(Car(d), env, true)
+
+		if err == nil && NilP(function) {
+			err = nilFunctionError(d, env)
+		}
 
-				var function *Data
-				function, err = evalHelper(Car(d), env, true)
+		if err == nil && !DebugSingleStep && TypeOf(function) == FunctionType && DebugOnEntry.Has(FunctionValue(function).Name) {
+			DebugRepl(env)
+		}
 
-				if err != nil {
-					return
+		var tailExpr *Data
+		var tailEnv *SymbolTableFrame
+		var enteredFunction *Function
+		var enteredGuid int64
+		if err == nil {
+			args := Cdr(d)
+			switch function.Type {
+			case FunctionType:
+				enteredFunction = FunctionValue(function)
+				var frame *FrameMap
+				if atomic.LoadInt32(&enteredFunction.SlotFunction) == 1 && env.HasFrame() {
+					frame = env.Frame
 				}
-				if NilP(function) {
-					err = errors.New(fmt.Sprintf("Nil when function or macro expected for %s.", String(Car(d))))
-					return
+				if code := enteredFunction.compiled(); code != nil {
+					var values []*Data
+					values, err = enteredFunction.evaluateArguments(args, env)
+					if err == nil {
+						result, err = enteredFunction.callCompiled(code, values, env, frame)
+					}
+					enteredFunction = nil
+					break
 				}
-
-				if !DebugSingleStep && TypeOf(function) == FunctionType && DebugOnEntry.Has(FunctionValue(function).Name) {
-					DebugRepl(env)
+				tailEnv, err = enteredFunction.makeLocalEnv(args, env, frame, true)
+				if err == nil && inTailPosition {
+					// the caller's frame is being replaced, so don't keep it reachable
+					tailEnv.Previous = originalEnv
+				}
+				if err == nil {
+					enteredGuid = atomic.AddInt64(&ProfileGUID, 1) - 1
+					ProfileEnter("func", enteredFunction.Name, enteredGuid)
+					tailExpr, err = enteredFunction.evaluateAllButLast(tailEnv)
+					if err != nil {
+						ProfileExit("func", enteredFunction.Name, enteredGuid)
+						err = functionError(enteredFunction, tailEnv, err)
+					}
+				}
+			case MacroType:
+				tailExpr, err = MacroValue(function).ExpandAtCallSite(d, env)
+				tailEnv = env
+			case PrimitiveType:
+				result, err = PrimitiveValue(function).internalApply(args, env)
+				if err == nil && TailCallP(result) {
+					tailExpr, tailEnv = TailCallValue(result).Expr, TailCallValue(result).Env
 				}
+			case ContinuationType:
+				result, err = ContinuationValue(function).Apply(args, env)
+			default:
+				err = MakeCondition(InapplicableObjectCondition, fmt.Sprintf("%s when function or macro expected for %s.", TypeName(TypeOf(function)), String(function)), nil)
+			}
+			if err != nil {
+				err = evalError(d, err)
+			}
+		}
+
+		if err != nil {
+			if tailFunction != nil {
+				ProfileExit("func", tailFunction.Name, tailGuid)
+			}
+			if inTailPosition {
+				err = tailCallError(err, originalExpr, tailFunction, tailFunctionEnv)
+			}
+			return nil, err
+		}
 
-				args := Cdr(d)
+		if tailEnv == nil {
+			if DebugReturnValue != nil {
+				result = DebugReturnValue
+				DebugReturnValue = nil
+			}
+			break
+		}
 
-				result, err = Apply(function, args, env)
-				if err != nil {
-					err = fmt.Errorf("\nEvaling %s. %w", String(d), err)
-					return
-				} else if DebugReturnValue != nil {
-					result = DebugReturnValue
-					DebugReturnValue = nil
-				}
+		// The evaluation of d has been replaced by the evaluation of tailExpr
+		if IsInteractive && !DebugEvalInDebugRepl && env.CurrentCode.Len() > 0 {
+			env.CurrentCode.Remove(env.CurrentCode.Front())
+		}
+		if enteredFunction != nil {
+			if tailFunction != nil {
+				ProfileExit("func", tailFunction.Name, tailGuid)
 			}
+			tailFunction, tailFunctionEnv, tailGuid = enteredFunction, tailEnv, enteredGuid
+		}
+		d, env = tailExpr, tailEnv
+		result = nil
+		needFunction = false
+		inTailPosition = true
+	}
+
+	if d != nil {
+		switch d.Type {
+		case ConsCellType:
 		case SymbolType:
 			if NakedP(d) {
 				result = d
@@ -1189,6 +1657,9 @@ func evalHelper(d *Data, env *SymbolTableFrame, needFunction bool) (result *Data
 			result = d
 		}
 	}
+	if tailFunction != nil {
+		ProfileExit("func", tailFunction.Name, tailGuid)
+	}
 	logResult(result, env)
 	if IsInteractive && !DebugEvalInDebugRepl && env.CurrentCode.Len() > 0 {
 		env.CurrentCode.Remove(env.CurrentCode.Front())
@@ -1214,6 +1685,8 @@ func formatApply(function *Data, args *Data) string {
 		fname = MacroValue(function).Name
 	case PrimitiveType:
 		fname = PrimitiveValue(function).Name
+	case ContinuationType:
+		fname = "continuation"
 	default:
 		return fmt.Sprintf("%s when function or macro expected for %s.", TypeName(TypeOf(function)), String(function))
 	}
@@ -1222,7 +1695,7 @@ func formatApply(function *Data, args *Data) string {
 
 func Apply(function *Data, args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	if NilP(function) {
-		err = errors.New("Nil when function expected.")
+		err = MakeCondition(InapplicableObjectCondition, "Nil when function expected.", nil)
 		return
 	}
 	switch function.Type {
@@ -1236,8 +1709,10 @@ func Apply(function *Data, args *Data, env *SymbolTableFrame) (result *Data, err
 		result, err = MacroValue(function).Apply(args, env)
 	case PrimitiveType:
 		result, err = PrimitiveValue(function).Apply(args, env)
+	case ContinuationType:
+		result, err = ContinuationValue(function).Apply(args, env)
 	default:
-		err = errors.New(fmt.Sprintf("%s when function or macro expected for %s.", TypeName(TypeOf(function)), String(function)))
+		err = MakeCondition(InapplicableObjectCondition, fmt.Sprintf("%s when function or macro expected for %s.", TypeName(TypeOf(function)), String(function)), nil)
 		return
 	}
 
@@ -1246,7 +1721,7 @@ func Apply(function *Data, args *Data, env *SymbolTableFrame) (result *Data, err
 
 func ApplyWithoutEval(function *Data, args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	if function == nil {
-		err = errors.New("Nil when function or macro expected.")
+		err = MakeCondition(InapplicableObjectCondition, "Nil when function or macro expected.", nil)
 		return
 	}
 	switch function.Type {
@@ -1261,8 +1736,10 @@ func ApplyWithoutEval(function *Data, args *Data, env *SymbolTableFrame) (result
 		result, err = MacroValue(function).ApplyWithoutEval(args, env)
 	case PrimitiveType:
 		result, err = PrimitiveValue(function).ApplyWithoutEval(args, env)
+	case ContinuationType:
+		result, err = ContinuationValue(function).ApplyWithoutEval(args, env)
 	default:
-		err = errors.New(fmt.Sprintf("%s when function or macro expected for %s.", TypeName(TypeOf(function)), String(function)))
+		err = MakeCondition(InapplicableObjectCondition, fmt.Sprintf("%s when function or macro expected for %s.", TypeName(TypeOf(function)), String(function)), nil)
 		return
 	}
 
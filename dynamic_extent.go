@@ -0,0 +1,172 @@
+// Copyright 2014 SteelSeries ApS.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// This package implements a basic LISP interpretor for embedding in a go program for scripting.
+// This file implements state that lasts for the dynamic extent of a form.
+
+package golisp
+
+import (
+	"container/list"
+	"errors"
+	"sync/atomic"
+)
+
+// State a form establishes for the dynamic extent of its body. It lives on a
+// frame the body is evaluated or applied in, and is found by walking from a
+// frame to its caller, so it is local to the goroutine doing the evaluation.
+type dynamicExtent struct {
+	// The handler installed by with-exception-handler.
+	Handler *Data
+	// Set for forms such as guard and on-error that catch the errors their
+	// body signals once it has unwound.
+	Catches bool
+	// While a handler runs, the frame to look for the next handler from.
+	HandlersFrom *SymbolTableFrame
+	// The current ports set by forms such as with-output-to-string.
+	InputPort  *Data
+	OutputPort *Data
+	exited     int32
+}
+
+func (self *dynamicExtent) exit() {
+	atomic.StoreInt32(&self.exited, 1)
+}
+
+func (self *dynamicExtent) hasExited() bool {
+	return atomic.LoadInt32(&self.exited) == 1
+}
+
+// Makes a frame below env holding extent. It has no bindings of its own:
+// definitions made in it go to env.
+func newDynamicFrame(env *SymbolTableFrame, name string, extent *dynamicExtent) *SymbolTableFrame {
+	frame := &SymbolTableFrame{Name: name, Parent: env, Previous: env, CurrentCode: list.New(), dynamic: extent}
+	if env != nil {
+		frame.Frame = env.Frame
+		frame.IsRestricted = env.IsRestricted
+	}
+	return frame
+}
+
+// The frame whose evaluation led to this one.
+func (self *SymbolTableFrame) caller() *SymbolTableFrame {
+	if self.Previous != nil {
+		return self.Previous
+	}
+	return self.Parent
+}
+
+// The handler an error signalled in env goes to, along with the frame that
+// installed it, or nil if the error should just unwind.
+func currentHandler(env *SymbolTableFrame) (handler *Data, frame *SymbolTableFrame) {
+	for f := env; f != nil; {
+		if extent := f.dynamic; extent != nil && !extent.hasExited() {
+			if extent.Catches {
+				return nil, nil
+			}
+			if extent.Handler != nil {
+				return extent.Handler, f
+			}
+			if extent.HandlersFrom != nil {
+				f = extent.HandlersFrom
+				continue
+			}
+		}
+		f = f.caller()
+	}
+	return nil, nil
+}
+
+// The current input or output port in env: the one set by the innermost form
+// redirecting it, or the default.
+func currentPort(env *SymbolTableFrame, output bool) *Data {
+	for f := env; f != nil; f = f.caller() {
+		if extent := f.dynamic; extent != nil && !extent.hasExited() {
+			if output && extent.OutputPort != nil {
+				return extent.OutputPort
+			}
+			if !output && extent.InputPort != nil {
+				return extent.InputPort
+			}
+		}
+	}
+	currentPortMutex.RLock()
+	defer currentPortMutex.RUnlock()
+	if output {
+		return defaultOutputPort
+	}
+	return defaultInputPort
+}
+
+// Applies f with no arguments with port as the current output port, or input
+// port if not output, for the dynamic extent of the call.
+func withCurrentPort(port *Data, output bool, f *Data, env *SymbolTableFrame) (result *Data, err error) {
+	extent := &dynamicExtent{}
+	if output {
+		extent.OutputPort = port
+	} else {
+		extent.InputPort = port
+	}
+	defer extent.exit()
+	return Apply(f, nil, newDynamicFrame(env, "with-port", extent))
+}
+
+// Marks an error that has already been passed to the handlers it unwinds
+// through.
+type dispatchedError struct {
+	Err error
+}
+
+func (self *dispatchedError) Error() string {
+	return self.Err.Error()
+}
+
+func (self *dispatchedError) Unwrap() error {
+	return self.Err
+}
+
+func dispatched(err error) error {
+	var d *dispatchedError
+	if err == nil || errors.As(err, &d) {
+		return err
+	}
+	return &dispatchedError{Err: err}
+}
+
+func isDispatched(err error) bool {
+	var d *dispatchedError
+	return errors.As(err, &d)
+}
+
+// Passes err, signalled in env, to the current handler. Unless continuable,
+// a handler returning is itself an error. With no handler err is returned to
+// unwind as usual.
+func signalError(err error, continuable bool, env *SymbolTableFrame) (result *Data, resultErr error) {
+	handler, frame := currentHandler(env)
+	if handler == nil {
+		return nil, err
+	}
+	return invokeHandler(handler, frame.caller(), err, continuable, env)
+}
+
+// Calls handler with the object signalled by err in the dynamic extent of env,
+// except that handlers are looked for from handlersFrom on.
+func invokeHandler(handler *Data, handlersFrom *SymbolTableFrame, err error, continuable bool, env *SymbolTableFrame) (result *Data, resultErr error) {
+	extent := &dynamicExtent{HandlersFrom: handlersFrom}
+	handlerEnv := newDynamicFrame(env, "exception-handler", extent)
+	defer extent.exit()
+
+	obj := ConditionObject(err)
+	result, resultErr = ApplyWithoutEval(handler, InternalMakeList(obj), handlerEnv)
+	if resultErr != nil {
+		return nil, dispatched(resultErr)
+	}
+	if continuable {
+		return
+	}
+
+	secondary := MakeCondition(ErrorCondition, "The exception handler returned from a non-continuable raise of", InternalMakeList(obj))
+	_, resultErr = signalError(secondary, false, handlerEnv)
+	return nil, dispatched(resultErr)
+}
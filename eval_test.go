@@ -8,6 +8,7 @@
 package golisp
 
 import (
+	"errors"
 	. "gopkg.in/check.v1"
 )
 
@@ -26,3 +27,33 @@ func (s *EvalSuite) TestEvalWithNilFunction(c *C) {
 	c.Assert(err, NotNil)
 	c.Assert(result, IsNil)
 }
+
+func (s *EvalSuite) TestBacktrace(c *C) {
+	_, err := ParseAndEvalAll("(define (bt-inner x)\n  (car x 1))\n(define (bt-outer y)\n  (+ 1 (bt-inner (* y 2))))")
+	c.Assert(err, IsNil)
+	_, err = ParseAndEval("(bt-outer 21)")
+	c.Assert(err, NotNil)
+
+	var bt *Backtrace
+	c.Assert(errors.As(err, &bt), Equals, true)
+	c.Assert(len(bt.Frames), Equals, 2)
+	c.Assert(bt.Frames[0].Function, Equals, "bt-inner")
+	c.Assert(String(bt.Frames[0].Args), Equals, "(42)")
+	c.Assert(String(bt.Frames[0].Expr), Equals, "(bt-inner (* y 2))")
+	c.Assert(bt.Frames[0].Location.String(), Equals, "4:8")
+	c.Assert(bt.Frames[1].Function, Equals, "bt-outer")
+	c.Assert(String(bt.Frames[1].Args), Equals, "(21)")
+	c.Assert(bt.Location.String(), Equals, "2:3")
+
+	var condition *Condition
+	c.Assert(errors.As(bt, &condition), Equals, true)
+	c.Assert(condition.Type, Equals, WrongNumberOfArgumentsCondition)
+}
+
+func (s *EvalSuite) TestBacktraceWithRestArgs(c *C) {
+	_, err := ParseAndEval("((lambda (a . rest) (car a 1)) 1 2 3)")
+	var bt *Backtrace
+	c.Assert(errors.As(err, &bt), Equals, true)
+	c.Assert(len(bt.Frames), Equals, 1)
+	c.Assert(String(bt.Frames[0].Args), Equals, "(1 2 3)")
+}
@@ -0,0 +1,947 @@
+// Copyright 2014 SteelSeries ApS.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// This package implements a basic LISP interpretor for embedding in a go program for scripting.
+// This file implements the control strings of format.
+
+package golisp
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// The kinds of prefix parameter a directive can have.
+const (
+	formatParamOmitted = iota
+	formatParamNumber
+	formatParamChar
+	formatParamArg
+	formatParamCount
+)
+
+type formatParam struct {
+	Kind  int
+	Value int
+	Char  rune
+}
+
+// A directive of a control string, or a run of literal text when Directive is
+// 0. The bracketing directives ~[, ~{ and ~( hold what they enclose in
+// Clauses, split at ~; for ~[.
+type formatDirective struct {
+	Directive     rune
+	Text          string
+	Params        []formatParam
+	Colon         bool
+	At            bool
+	Index         int
+	Clauses       [][]*formatDirective
+	DefaultClause bool
+	CloseColon    bool
+}
+
+// The arguments a run of directives consumes. Iteration gives its body
+// arguments of its own.
+type formatArgs struct {
+	Items    []*Data
+	Pos      int
+	Furthest int
+}
+
+func (self *formatArgs) remaining() int {
+	return len(self.Items) - self.Pos
+}
+
+func (self *formatArgs) seek(pos int) {
+	self.Pos = pos
+	if pos > self.Furthest {
+		self.Furthest = pos
+	}
+}
+
+type formatter struct {
+	Out *bytes.Buffer
+	Env *SymbolTableFrame
+}
+
+func scanFormatDirectives(control []rune, env *SymbolTableFrame) (directives []*formatDirective, err error) {
+	directives = make([]*formatDirective, 0, 8)
+	start := 0
+	i := 0
+	for i < len(control) {
+		if control[i] != '~' {
+			i++
+			continue
+		}
+		if i > start {
+			directives = append(directives, &formatDirective{Text: string(control[start:i])})
+		}
+
+		d := &formatDirective{Index: i}
+		i++
+		for {
+			param := formatParam{}
+			switch {
+			case i < len(control) && (unicode.IsDigit(control[i]) || ((control[i] == '-' || control[i] == '+') && i+1 < len(control) && unicode.IsDigit(control[i+1]))):
+				numberStart := i
+				i++
+				for i < len(control) && unicode.IsDigit(control[i]) {
+					i++
+				}
+				n, parseErr := strconv.Atoi(string(control[numberStart:i]))
+				if parseErr != nil {
+					err = ProcessError(fmt.Sprintf("format encountered a bad numeric argument at index %d", numberStart), env)
+					return
+				}
+				param = formatParam{Kind: formatParamNumber, Value: n}
+			case i+1 < len(control) && control[i] == '\'':
+				param = formatParam{Kind: formatParamChar, Char: control[i+1]}
+				i += 2
+			case i < len(control) && (control[i] == 'V' || control[i] == 'v'):
+				param = formatParam{Kind: formatParamArg}
+				i++
+			case i < len(control) && control[i] == '#':
+				param = formatParam{Kind: formatParamCount}
+				i++
+			}
+			if i < len(control) && control[i] == ',' {
+				d.Params = append(d.Params, param)
+				i++
+				continue
+			}
+			if param.Kind != formatParamOmitted {
+				d.Params = append(d.Params, param)
+			}
+			break
+		}
+		for i < len(control) && (control[i] == ':' || control[i] == '@') {
+			if control[i] == ':' {
+				d.Colon = true
+			} else {
+				d.At = true
+			}
+			i++
+		}
+		if i >= len(control) {
+			err = ProcessError(fmt.Sprintf("format encountered an unfinished directive at index %d", d.Index), env)
+			return
+		}
+		d.Directive = unicode.ToUpper(control[i])
+		i++
+
+		if d.Directive == '\n' {
+			// A tilde before a newline ignores the newline and the whitespace
+			// after it: ~:<newline> keeps the whitespace, and
+			// ~@<newline> keeps the newline.
+			for !d.Colon && i < len(control) && unicode.IsSpace(control[i]) {
+				i++
+			}
+		}
+		directives = append(directives, d)
+		start = i
+	}
+	if i > start {
+		directives = append(directives, &formatDirective{Text: string(control[start:i])})
+	}
+	return
+}
+
+var formatClosers = map[rune]rune{'[': ']', '{': '}', '(': ')'}
+
+// Nests the directives enclosed by each bracketing directive inside it,
+// returning the directives up to the closer.
+func nestFormatDirectives(directives []*formatDirective, pos int, opener *formatDirective, env *SymbolTableFrame) (clauses [][]*formatDirective, next int, err error) {
+	closer := rune(0)
+	if opener != nil {
+		closer = formatClosers[opener.Directive]
+	}
+
+	clause := make([]*formatDirective, 0, len(directives)-pos)
+	for pos < len(directives) {
+		d := directives[pos]
+		pos++
+		if closer != 0 && d.Directive == closer {
+			opener.CloseColon = d.Colon
+			return append(clauses, clause), pos, nil
+		}
+		switch d.Directive {
+		case ';':
+			if closer != ']' {
+				err = ProcessError(fmt.Sprintf("format encountered ~; outside of ~[ at index %d", d.Index), env)
+				return
+			}
+			if d.Colon {
+				opener.DefaultClause = true
+			}
+			clauses = append(clauses, clause)
+			clause = make([]*formatDirective, 0, len(directives)-pos)
+			continue
+		case '[', '{', '(':
+			d.Clauses, pos, err = nestFormatDirectives(directives, pos, d, env)
+			if err != nil {
+				return
+			}
+		case ']', '}', ')':
+			err = ProcessError(fmt.Sprintf("format encountered an unmatched ~%c at index %d", d.Directive, d.Index), env)
+			return
+		}
+		clause = append(clause, d)
+	}
+
+	if opener != nil {
+		err = ProcessError(fmt.Sprintf("format encountered an unterminated ~%c at index %d", opener.Directive, opener.Index), env)
+		return
+	}
+	return append(clauses, clause), pos, nil
+}
+
+// Formats args as control directs, returning the text.
+func FormatToString(control string, args *Data, env *SymbolTableFrame) (text string, err error) {
+	directives, err := scanFormatDirectives([]rune(control), env)
+	if err != nil {
+		return
+	}
+	clauses, _, err := nestFormatDirectives(directives, 0, nil, env)
+	if err != nil {
+		return
+	}
+
+	f := &formatter{Out: &bytes.Buffer{}, Env: env}
+	arguments := &formatArgs{Items: ToArray(args)}
+	_, err = f.run(clauses[0], arguments)
+	if err != nil {
+		return
+	}
+
+	if arguments.Furthest < len(arguments.Items) {
+		err = ProcessError("number of replacements in the control string and number of arguments must be equal", env)
+		return
+	}
+	return f.Out.String(), nil
+}
+
+func (self *formatter) nextArg(args *formatArgs) (arg *Data, err error) {
+	if args.remaining() <= 0 {
+		err = ProcessError("number of replacements in the control string and number of arguments must be equal", self.Env)
+		return
+	}
+	arg = args.Items[args.Pos]
+	args.seek(args.Pos + 1)
+	return
+}
+
+// The values of the directive's parameters, with nil for those omitted.
+func (self *formatter) params(d *formatDirective, args *formatArgs) (values []*Data, err error) {
+	values = make([]*Data, len(d.Params))
+	for i, param := range d.Params {
+		switch param.Kind {
+		case formatParamNumber:
+			values[i] = IntegerWithValue(int64(param.Value))
+		case formatParamChar:
+			values[i] = CharacterWithValue(param.Char)
+		case formatParamCount:
+			values[i] = IntegerWithValue(int64(args.remaining()))
+		case formatParamArg:
+			var arg *Data
+			arg, err = self.nextArg(args)
+			if err != nil {
+				return
+			}
+			if !NilP(arg) && !IntegerP(arg) && !CharacterP(arg) {
+				err = ProcessError(fmt.Sprintf("format encountered a size argument mismatch at index %d", d.Index), self.Env)
+				return
+			}
+			if NotNilP(arg) {
+				values[i] = arg
+			}
+		}
+	}
+	return
+}
+
+func (self *formatter) intParam(d *formatDirective, values []*Data, i int, defaultValue int) (n int, err error) {
+	if i >= len(values) || values[i] == nil {
+		return defaultValue, nil
+	}
+	if !IntegerP(values[i]) {
+		err = ProcessError(fmt.Sprintf("format expected a numeric parameter for ~%c at index %d", d.Directive, d.Index), self.Env)
+		return
+	}
+	return int(IntegerValue(values[i])), nil
+}
+
+func (self *formatter) charParam(d *formatDirective, values []*Data, i int, defaultValue rune) (ch rune, err error) {
+	if i >= len(values) || values[i] == nil {
+		return defaultValue, nil
+	}
+	if !CharacterP(values[i]) {
+		err = ProcessError(fmt.Sprintf("format expected a character parameter for ~%c at index %d", d.Directive, d.Index), self.Env)
+		return
+	}
+	return CharacterValue(values[i]), nil
+}
+
+// The optional parameter at i, which is -1 when omitted.
+func (self *formatter) optionalIntParam(d *formatDirective, values []*Data, i int) (n int, err error) {
+	return self.intParam(d, values, i, -1)
+}
+
+// The column that output has reached on the current line.
+func (self *formatter) column() int {
+	text := self.Out.Bytes()
+	return utf8.RuneCount(text[bytes.LastIndexByte(text, '\n')+1:])
+}
+
+// Pads s with padChar to at least minCol columns, adding at least minPad and
+// then colInc at a time.
+func padFormatText(s string, minCol int, colInc int, minPad int, padChar rune, left bool) string {
+	length := utf8.RuneCountInString(s)
+	padding := minPad
+	if colInc < 1 {
+		colInc = 1
+	}
+	for length+padding < minCol {
+		padding += colInc
+	}
+	if padding <= 0 {
+		return s
+	}
+	pad := strings.Repeat(string(padChar), padding)
+	if left {
+		return pad + s
+	}
+	return s + pad
+}
+
+// Runs directives, returning escaped as true when ~^ ended the run early.
+func (self *formatter) run(directives []*formatDirective, args *formatArgs) (escaped bool, err error) {
+	for _, d := range directives {
+		if d.Directive == 0 {
+			self.Out.WriteString(d.Text)
+			continue
+		}
+
+		var values []*Data
+		values, err = self.params(d, args)
+		if err != nil {
+			return
+		}
+
+		switch d.Directive {
+		case 'A', 'S':
+			err = self.formatObject(d, values, args)
+		case 'D':
+			err = self.formatInteger(d, values, args, 10)
+		case 'B':
+			err = self.formatInteger(d, values, args, 2)
+		case 'O':
+			err = self.formatInteger(d, values, args, 8)
+		case 'X':
+			err = self.formatInteger(d, values, args, 16)
+		case 'F':
+			err = self.formatFixed(d, values, args)
+		case 'E':
+			err = self.formatExponential(d, values, args)
+		case '$':
+			err = self.formatMonetary(d, values, args)
+		case 'C':
+			err = self.formatCharacter(d, args)
+		case '%', '~':
+			var n int
+			n, err = self.intParam(d, values, 0, 1)
+			if err == nil && n > 0 {
+				text := "\n"
+				if d.Directive == '~' {
+					text = "~"
+				}
+				self.Out.WriteString(strings.Repeat(text, n))
+			}
+		case '&':
+			var n int
+			n, err = self.intParam(d, values, 0, 1)
+			if err == nil && n > 0 {
+				if self.column() == 0 {
+					n--
+				}
+				self.Out.WriteString(strings.Repeat("\n", n))
+			}
+		case '\n':
+			if d.At {
+				self.Out.WriteString("\n")
+			}
+		case 'T':
+			err = self.formatTabulate(d, values)
+		case '*':
+			err = self.formatGoto(d, values, args)
+		case '[':
+			escaped, err = self.formatConditional(d, values, args)
+		case '{':
+			err = self.formatIteration(d, values, args)
+		case '(':
+			escaped, err = self.formatCaseConversion(d, args)
+		case '^':
+			var n int
+			n, err = self.optionalIntParam(d, values, 0)
+			if err == nil && (n == 0 || (n < 0 && args.remaining() == 0)) {
+				return true, nil
+			}
+		default:
+			err = ProcessError(fmt.Sprintf("format encountered an unsupported substitution at index %d", d.Index), self.Env)
+		}
+		if err != nil || escaped {
+			return
+		}
+	}
+	return
+}
+
+// ~mincol,colinc,minpad,padcharA prints like display and ~S like write;
+// @ pads on the left.
+func (self *formatter) formatObject(d *formatDirective, values []*Data, args *formatArgs) (err error) {
+	arg, err := self.nextArg(args)
+	if err != nil {
+		return
+	}
+
+	text := String(arg)
+	if d.Directive == 'A' {
+		text = PrintString(arg)
+	}
+	return self.writePadded(d, values, text, d.At)
+}
+
+func (self *formatter) writePadded(d *formatDirective, values []*Data, text string, left bool) (err error) {
+	minCol, err := self.intParam(d, values, 0, 0)
+	if err != nil {
+		return
+	}
+	colInc, err := self.intParam(d, values, 1, 1)
+	if err != nil {
+		return
+	}
+	minPad, err := self.intParam(d, values, 2, 0)
+	if err != nil {
+		return
+	}
+	padChar, err := self.charParam(d, values, 3, ' ')
+	if err != nil {
+		return
+	}
+	self.Out.WriteString(padFormatText(text, minCol, colInc, minPad, padChar, left))
+	return
+}
+
+// ~mincol,padchar,commachar,commaintervalD and likewise ~B, ~O and ~X. @
+// always prints the sign and : separates groups of digits. Anything other
+// than an integer is printed as by ~A.
+func (self *formatter) formatInteger(d *formatDirective, values []*Data, args *formatArgs, base int) (err error) {
+	arg, err := self.nextArg(args)
+	if err != nil {
+		return
+	}
+	minCol, err := self.intParam(d, values, 0, 0)
+	if err != nil {
+		return
+	}
+	padChar, err := self.charParam(d, values, 1, ' ')
+	if err != nil {
+		return
+	}
+	commaChar, err := self.charParam(d, values, 2, ',')
+	if err != nil {
+		return
+	}
+	interval, err := self.intParam(d, values, 3, 3)
+	if err != nil {
+		return
+	}
+
+	if !ExactIntegerP(arg) {
+		self.Out.WriteString(padFormatText(PrintString(arg), minCol, 1, 0, padChar, false))
+		return
+	}
+
+	n := bigIntValue(arg)
+	digits := strings.ToUpper(new(big.Int).Abs(n).Text(base))
+	if d.Colon && interval > 0 {
+		groups := make([]string, 0, len(digits)/interval+1)
+		for len(digits) > interval {
+			groups = append([]string{digits[len(digits)-interval:]}, groups...)
+			digits = digits[:len(digits)-interval]
+		}
+		digits = strings.Join(append([]string{digits}, groups...), string(commaChar))
+	}
+
+	sign := ""
+	if n.Sign() < 0 {
+		sign = "-"
+	} else if d.At {
+		sign = "+"
+	}
+	self.Out.WriteString(padFormatText(sign+digits, minCol, 1, 0, padChar, true))
+	return
+}
+
+// The value of a number argument for the float directives, or false when it
+// isn't a number.
+func formatFloatArg(arg *Data) (f float64, ok bool) {
+	if !NumberP(arg) {
+		return 0, false
+	}
+	return FloatValue(arg), true
+}
+
+func formatSign(f float64, always bool) string {
+	if f < 0 || (f == 0 && math.Signbit(f)) {
+		return "-"
+	}
+	if always {
+		return "+"
+	}
+	return ""
+}
+
+// Writes a formatted number padded to width, or width overflow characters
+// if it doesn't fit and one was given.
+func (self *formatter) writeNumberField(text string, width int, overflowChar *Data, padChar rune) {
+	if width >= 0 && utf8.RuneCountInString(text) > width && overflowChar != nil {
+		self.Out.WriteString(strings.Repeat(string(CharacterValue(overflowChar)), width))
+		return
+	}
+	self.Out.WriteString(padFormatText(text, width, 1, 0, padChar, true))
+}
+
+func formatParamAt(values []*Data, i int) *Data {
+	if i < len(values) && CharacterP(values[i]) {
+		return values[i]
+	}
+	return nil
+}
+
+// ~w,d,k,overflowchar,padcharF prints a number in fixed point with d digits
+// after the point, scaled by 10^k. Without d, as many digits are printed as
+// fit in w.
+func (self *formatter) formatFixed(d *formatDirective, values []*Data, args *formatArgs) (err error) {
+	arg, err := self.nextArg(args)
+	if err != nil {
+		return
+	}
+	width, err := self.optionalIntParam(d, values, 0)
+	if err != nil {
+		return
+	}
+	digits, err := self.optionalIntParam(d, values, 1)
+	if err != nil {
+		return
+	}
+	scale, err := self.intParam(d, values, 2, 0)
+	if err != nil {
+		return
+	}
+	padChar, err := self.charParam(d, values, 4, ' ')
+	if err != nil {
+		return
+	}
+
+	f, ok := formatFloatArg(arg)
+	if !ok {
+		self.Out.WriteString(padFormatText(PrintString(arg), width, 1, 0, ' ', false))
+		return
+	}
+	if math.IsInf(f, 0) || math.IsNaN(f) {
+		self.Out.WriteString(padFormatText(String(FloatWithValue(f)), width, 1, 0, padChar, true))
+		return
+	}
+
+	f *= math.Pow10(scale)
+	sign := formatSign(f, d.At)
+	f = math.Abs(f)
+
+	var text string
+	if digits >= 0 {
+		text = strconv.FormatFloat(f, 'f', digits, 64)
+	} else {
+		text = strconv.FormatFloat(f, 'f', -1, 64)
+		if !strings.Contains(text, ".") {
+			text += ".0"
+		}
+		if width >= 0 && len(sign)+len(text) > width {
+			intDigits := len(strconv.FormatFloat(f, 'f', 0, 64))
+			fraction := width - len(sign) - intDigits - 1
+			if fraction < 0 {
+				fraction = 0
+			}
+			text = strconv.FormatFloat(f, 'f', fraction, 64)
+			if fraction == 0 {
+				text += "."
+			}
+		}
+	}
+	self.writeNumberField(sign+text, width, formatParamAt(values, 3), padChar)
+	return
+}
+
+// ~w,d,e,k,overflowchar,padchar,exptcharE prints a number in exponential
+// notation with d digits after the point and at least e digits of exponent.
+func (self *formatter) formatExponential(d *formatDirective, values []*Data, args *formatArgs) (err error) {
+	arg, err := self.nextArg(args)
+	if err != nil {
+		return
+	}
+	width, err := self.optionalIntParam(d, values, 0)
+	if err != nil {
+		return
+	}
+	digits, err := self.optionalIntParam(d, values, 1)
+	if err != nil {
+		return
+	}
+	expDigits, err := self.intParam(d, values, 2, 1)
+	if err != nil {
+		return
+	}
+	padChar, err := self.charParam(d, values, 5, ' ')
+	if err != nil {
+		return
+	}
+	exptChar, err := self.charParam(d, values, 6, 'e')
+	if err != nil {
+		return
+	}
+
+	f, ok := formatFloatArg(arg)
+	if !ok {
+		self.Out.WriteString(padFormatText(PrintString(arg), width, 1, 0, ' ', false))
+		return
+	}
+	if math.IsInf(f, 0) || math.IsNaN(f) {
+		self.Out.WriteString(padFormatText(String(FloatWithValue(f)), width, 1, 0, padChar, true))
+		return
+	}
+
+	sign := formatSign(f, d.At)
+	text := strconv.FormatFloat(math.Abs(f), 'e', digits, 64)
+	parts := strings.SplitN(text, "e", 2)
+	mantissa, exponent := parts[0], parts[1]
+	if !strings.Contains(mantissa, ".") {
+		mantissa += ".0"
+	}
+	expSign := exponent[:1]
+	expValue := strings.TrimLeft(exponent[1:], "0")
+	if len(expValue) < expDigits {
+		expValue = strings.Repeat("0", expDigits-len(expValue)) + expValue
+	}
+	text = fmt.Sprintf("%s%s%c%s%s", sign, mantissa, exptChar, expSign, expValue)
+	self.writeNumberField(text, width, formatParamAt(values, 4), padChar)
+	return
+}
+
+// ~d,n,w,padchar$ prints a number with d digits after the point, at least n
+// before it and padded to w. : puts the sign before the padding.
+func (self *formatter) formatMonetary(d *formatDirective, values []*Data, args *formatArgs) (err error) {
+	arg, err := self.nextArg(args)
+	if err != nil {
+		return
+	}
+	digits, err := self.intParam(d, values, 0, 2)
+	if err != nil {
+		return
+	}
+	intDigits, err := self.intParam(d, values, 1, 1)
+	if err != nil {
+		return
+	}
+	width, err := self.intParam(d, values, 2, 0)
+	if err != nil {
+		return
+	}
+	padChar, err := self.charParam(d, values, 3, ' ')
+	if err != nil {
+		return
+	}
+
+	f, ok := formatFloatArg(arg)
+	if !ok {
+		self.Out.WriteString(padFormatText(PrintString(arg), width, 1, 0, ' ', false))
+		return
+	}
+
+	sign := formatSign(f, d.At)
+	text := strconv.FormatFloat(math.Abs(f), 'f', digits, 64)
+	point := strings.IndexByte(text, '.')
+	if point < 0 {
+		point = len(text)
+	}
+	if point < intDigits {
+		text = strings.Repeat("0", intDigits-point) + text
+	}
+
+	if d.Colon {
+		self.Out.WriteString(sign + padFormatText(text, width-len(sign), 1, 0, padChar, true))
+	} else {
+		self.Out.WriteString(padFormatText(sign+text, width, 1, 0, padChar, true))
+	}
+	return
+}
+
+// ~C prints a character as is, ~:C by name and ~@C as it is read.
+func (self *formatter) formatCharacter(d *formatDirective, args *formatArgs) (err error) {
+	arg, err := self.nextArg(args)
+	if err != nil {
+		return
+	}
+	if !CharacterP(arg) {
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("format expected a character for ~C at index %d, but received %s", d.Index, String(arg)), self.Env)
+		return
+	}
+
+	switch {
+	case d.At:
+		self.Out.WriteString(String(arg))
+	case d.Colon:
+		self.Out.WriteString(CharacterDisplayName(CharacterValue(arg)))
+	default:
+		self.Out.WriteRune(CharacterValue(arg))
+	}
+	return
+}
+
+// ~colnum,colincT moves to column colnum, or on to the next multiple of
+// colinc past it. ~colrel,colinc@T moves colrel columns and then on to a
+// multiple of colinc.
+func (self *formatter) formatTabulate(d *formatDirective, values []*Data) (err error) {
+	column := self.column()
+	if d.At {
+		var relative, colInc int
+		relative, err = self.intParam(d, values, 0, 1)
+		if err != nil {
+			return
+		}
+		colInc, err = self.intParam(d, values, 1, 1)
+		if err != nil {
+			return
+		}
+		target := column + relative
+		if colInc > 1 && target%colInc != 0 {
+			target += colInc - target%colInc
+		}
+		self.Out.WriteString(strings.Repeat(" ", target-column))
+		return
+	}
+
+	colNum, err := self.intParam(d, values, 0, 1)
+	if err != nil {
+		return
+	}
+	colInc, err := self.intParam(d, values, 1, 1)
+	if err != nil {
+		return
+	}
+	target := colNum
+	if column >= colNum {
+		if colInc <= 0 {
+			return
+		}
+		target = colNum + ((column-colNum)/colInc+1)*colInc
+	}
+	self.Out.WriteString(strings.Repeat(" ", target-column))
+	return
+}
+
+// ~n* skips n arguments, ~n:* backs up n and ~n@* goes to argument n.
+func (self *formatter) formatGoto(d *formatDirective, values []*Data, args *formatArgs) (err error) {
+	defaultCount := 1
+	if d.At {
+		defaultCount = 0
+	}
+	n, err := self.intParam(d, values, 0, defaultCount)
+	if err != nil {
+		return
+	}
+
+	pos := args.Pos + n
+	if d.Colon {
+		pos = args.Pos - n
+	} else if d.At {
+		pos = n
+	}
+	if pos < 0 || pos > len(args.Items) {
+		err = ProcessError(fmt.Sprintf("format ~* at index %d moved outside of the arguments", d.Index), self.Env)
+		return
+	}
+	args.seek(pos)
+	return
+}
+
+// ~[...~;...~] chooses a clause by number, ~:[false~;true~] by truth and
+// ~@[...~] runs its clause only if the argument is true, leaving it to be
+// used.
+func (self *formatter) formatConditional(d *formatDirective, values []*Data, args *formatArgs) (escaped bool, err error) {
+	switch {
+	case d.Colon:
+		if len(d.Clauses) != 2 {
+			err = ProcessError(fmt.Sprintf("format ~:[ at index %d requires exactly two clauses", d.Index), self.Env)
+			return
+		}
+		var arg *Data
+		arg, err = self.nextArg(args)
+		if err != nil {
+			return
+		}
+		if BooleanValue(arg) {
+			return self.run(d.Clauses[1], args)
+		}
+		return self.run(d.Clauses[0], args)
+	case d.At:
+		if len(d.Clauses) != 1 {
+			err = ProcessError(fmt.Sprintf("format ~@[ at index %d requires exactly one clause", d.Index), self.Env)
+			return
+		}
+		var arg *Data
+		arg, err = self.nextArg(args)
+		if err != nil {
+			return
+		}
+		if BooleanValue(arg) {
+			args.Pos--
+			return self.run(d.Clauses[0], args)
+		}
+		return
+	}
+
+	index, err := self.optionalIntParam(d, values, 0)
+	if err != nil {
+		return
+	}
+	if index < 0 {
+		var arg *Data
+		arg, err = self.nextArg(args)
+		if err != nil {
+			return
+		}
+		if !IntegerP(arg) {
+			err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("format expected an integer for ~[ at index %d, but received %s", d.Index, String(arg)), self.Env)
+			return
+		}
+		index = int(IntegerValue(arg))
+	}
+
+	last := len(d.Clauses) - 1
+	switch {
+	case index >= 0 && index < last, index == last && !d.DefaultClause:
+		return self.run(d.Clauses[index], args)
+	case d.DefaultClause:
+		return self.run(d.Clauses[last], args)
+	}
+	return
+}
+
+// ~{...~} runs its body over the elements of a list, ~:{ over a list of
+// argument lists, ~@{ over the remaining arguments and ~:@{ over the
+// remaining arguments as argument lists. ~n{ stops after n times and ~:}
+// runs the body at least once.
+func (self *formatter) formatIteration(d *formatDirective, values []*Data, args *formatArgs) (err error) {
+	body := d.Clauses[0]
+	limit, err := self.optionalIntParam(d, values, 0)
+	if err != nil {
+		return
+	}
+
+	var items *formatArgs
+	if d.At {
+		items = args
+	} else {
+		var arg *Data
+		arg, err = self.nextArg(args)
+		if err != nil {
+			return
+		}
+		if !ListP(arg) {
+			err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("format expected a list for ~{ at index %d, but received %s", d.Index, String(arg)), self.Env)
+			return
+		}
+		items = &formatArgs{Items: ToArray(arg)}
+	}
+
+	for count := 0; limit < 0 || count < limit; count++ {
+		if items.remaining() == 0 && !(d.CloseColon && count == 0) {
+			return
+		}
+
+		pos := items.Pos
+		bodyArgs := items
+		if d.Colon {
+			var sublist *Data
+			sublist, err = self.nextArg(items)
+			if err != nil {
+				return
+			}
+			if !ListP(sublist) {
+				err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("format expected a list of lists for ~:{ at index %d, but received %s", d.Index, String(sublist)), self.Env)
+				return
+			}
+			bodyArgs = &formatArgs{Items: ToArray(sublist)}
+		}
+
+		var escaped bool
+		escaped, err = self.run(body, bodyArgs)
+		if err != nil || (escaped && !d.Colon) {
+			return
+		}
+		// A body that uses no arguments would otherwise repeat forever
+		if items.Pos == pos && limit < 0 {
+			return
+		}
+	}
+	return
+}
+
+// ~(...~) lowercases the text of its body, ~:( capitalizes each word, ~@(
+// capitalizes the first word and ~:@( uppercases it all.
+func (self *formatter) formatCaseConversion(d *formatDirective, args *formatArgs) (escaped bool, err error) {
+	start := self.Out.Len()
+	escaped, err = self.run(d.Clauses[0], args)
+	if err != nil {
+		return
+	}
+
+	text := string(self.Out.Bytes()[start:])
+	self.Out.Truncate(start)
+	switch {
+	case d.Colon && d.At:
+		text = strings.ToUpper(text)
+	case d.Colon:
+		text = capitalizeWords(text, true)
+	case d.At:
+		text = capitalizeWords(text, false)
+	default:
+		text = strings.ToLower(text)
+	}
+	self.Out.WriteString(text)
+	return
+}
+
+// Lowercases text, capitalizing the first letter of its first word or, if
+// every is true, of every word.
+func capitalizeWords(text string, every bool) string {
+	runes := []rune(strings.ToLower(text))
+	inWord := false
+	capitalized := false
+	for i, ch := range runes {
+		isWordChar := unicode.IsLetter(ch) || unicode.IsDigit(ch)
+		if isWordChar && !inWord && (every || !capitalized) {
+			runes[i] = unicode.ToUpper(ch)
+			capitalized = true
+		}
+		inWord = isWordChar
+	}
+	return string(runes)
+}
@@ -8,7 +8,6 @@
 package golisp
 
 import (
-	"errors"
 	"fmt"
 	"sync/atomic"
 	"unsafe"
@@ -24,6 +23,7 @@ type Function struct {
 	DebugOnEntry     bool
 	SlotFunction     int32
 	ParentProcess    *Process
+	code             unsafe.Pointer
 }
 
 func computeRequiredArgumentCount(args *Data) (requiredArgumentCount int, varArgs bool) {
@@ -49,20 +49,31 @@ func (self *Function) String() string {
 	return fmt.Sprintf("<func: %s>", self.Name)
 }
 
-func (self *Function) makeLocalBindings(args *Data, argEnv *SymbolTableFrame, localEnv *SymbolTableFrame, eval bool) (err error) {
+func (self *Function) checkArgumentCount(argCount int) error {
 	if self.VarArgs {
-		if Length(args) < self.RequiredArgCount {
-			return errors.New(fmt.Sprintf("%s expected at least %d parameters, received %d.", self.Name, self.RequiredArgCount, Length(args)))
+		if argCount < self.RequiredArgCount {
+			return MakeCondition(WrongNumberOfArgumentsCondition, fmt.Sprintf("%s expected at least %d parameters, received %d.", self.Name, self.RequiredArgCount, argCount), nil)
 		}
 	} else {
-		if Length(args) != self.RequiredArgCount {
-			return errors.New(fmt.Sprintf("%s expected %d parameters, received %d.", self.Name, self.RequiredArgCount, Length(args)))
+		if argCount != self.RequiredArgCount {
+			return MakeCondition(WrongNumberOfArgumentsCondition, fmt.Sprintf("%s expected %d parameters, received %d.", self.Name, self.RequiredArgCount, argCount), nil)
 		}
 	}
+	return nil
+}
+
+func (self *Function) makeLocalBindings(args *Data, argEnv *SymbolTableFrame, localEnv *SymbolTableFrame, eval bool) (err error) {
+	err = self.checkArgumentCount(Length(args))
+	if err != nil {
+		return
+	}
 
 	var argValue *Data
 	var accumulatingParam *Data = nil
 	accumulatedArgs := make([]*Data, 0)
+	if SymbolP(self.Params) {
+		accumulatingParam = self.Params
+	}
 	for p, a := self.Params, args; NotNilP(a); a = Cdr(a) {
 		if eval {
 			argValue, err = Eval(Car(a), argEnv)
@@ -97,9 +108,19 @@ func (self *Function) makeLocalBindings(args *Data, argEnv *SymbolTableFrame, lo
 	return nil
 }
 
-func (self *Function) internalApply(args *Data, argEnv *SymbolTableFrame, frame *FrameMap, eval bool) (result *Data, err error) {
-	localEnv := NewSymbolTableFrameBelowWithFrame(self.Env, frame, self.Name)
+func (self *Function) makeLocalEnv(args *Data, argEnv *SymbolTableFrame, frame *FrameMap, eval bool) (localEnv *SymbolTableFrame, err error) {
+	localEnv = NewSymbolTableFrameBelowWithFrame(self.Env, frame, self.Name)
 	localEnv.Previous = argEnv
+	err = self.bindSelfAndProcess(localEnv, argEnv, frame)
+	if err != nil {
+		return
+	}
+
+	err = self.makeLocalBindings(args, argEnv, localEnv, eval)
+	return
+}
+
+func (self *Function) bindSelfAndProcess(localEnv *SymbolTableFrame, argEnv *SymbolTableFrame, frame *FrameMap) (err error) {
 	selfSym := Intern("self")
 	if frame != nil {
 		_, err = localEnv.BindLocallyTo(selfSym, FrameWithValue(frame))
@@ -120,12 +141,58 @@ func (self *Function) internalApply(args *Data, argEnv *SymbolTableFrame, frame
 	if self.ParentProcess != nil {
 		procObj := ObjectWithTypeAndValue("Process", unsafe.Pointer(self.ParentProcess))
 		_, err = localEnv.BindLocallyTo(parentProcSym, procObj)
+	}
+	return
+}
+
+// Evaluates all but the last expression of the body, returning the last one
+// so that the evaluator can treat it as a tail call.
+func (self *Function) evaluateAllButLast(localEnv *SymbolTableFrame) (last *Data, err error) {
+	s := self.Body
+	for ; NotNilP(Cdr(s)); s = Cdr(s) {
+		_, err = Eval(Car(s), localEnv)
 		if err != nil {
 			return
 		}
 	}
+	return Car(s), nil
+}
 
-	err = self.makeLocalBindings(args, argEnv, localEnv, eval)
+// The values bound to the parameters, with any rest parameter spread out.
+func (self *Function) argumentValues(localEnv *SymbolTableFrame) *Data {
+	values := make([]*Data, 0, self.RequiredArgCount)
+	var p *Data
+	for p = self.Params; PairP(p) && NotNilP(p); p = Cdr(p) {
+		if binding, found := localEnv.findBindingInLocalFrameFor(Car(p)); found {
+			values = append(values, binding.Val)
+		}
+	}
+	if SymbolP(p) {
+		if binding, found := localEnv.findBindingInLocalFrameFor(p); found {
+			return ArrayToListWithTail(values, binding.Val)
+		}
+	}
+	return ArrayToList(values)
+}
+
+func (self *Function) internalApply(args *Data, argEnv *SymbolTableFrame, frame *FrameMap, eval bool) (result *Data, err error) {
+	if code := self.compiled(); code != nil {
+		var values []*Data
+		if eval {
+			values, err = self.evaluateArguments(args, argEnv)
+			if err != nil {
+				return
+			}
+		} else {
+			values = ToArray(args)
+		}
+		return self.callCompiled(code, values, argEnv, frame)
+	}
+	return self.interpretedApply(args, argEnv, frame, eval)
+}
+
+func (self *Function) interpretedApply(args *Data, argEnv *SymbolTableFrame, frame *FrameMap, eval bool) (result *Data, err error) {
+	localEnv, err := self.makeLocalEnv(args, argEnv, frame, eval)
 	if err != nil {
 		return
 	}
@@ -137,7 +204,7 @@ func (self *Function) internalApply(args *Data, argEnv *SymbolTableFrame, frame
 	for s := self.Body; NotNilP(s); s = Cdr(s) {
 		result, err = Eval(Car(s), localEnv)
 		if err != nil {
-			result, err = nil, errors.New(fmt.Sprintf("In '%s': %s", self.Name, err))
+			result, err = nil, functionError(self, localEnv, err)
 			break
 		}
 	}
@@ -177,7 +244,7 @@ func (self *Function) ApplyOveriddingEnvironment(args *Data, argEnv *SymbolTable
 	for s := self.Body; NotNilP(s); s = Cdr(s) {
 		result, err = Eval(Car(s), localEnv)
 		if err != nil {
-			result, err = nil, errors.New(fmt.Sprintf("In '%s': %s", self.Name, err))
+			result, err = nil, functionError(self, localEnv, err)
 			break
 		}
 	}
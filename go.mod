@@ -6,5 +6,6 @@ require (
 	github.com/SteelSeries/bufrr v0.0.0-20161129220322-72103137aa3c
 	github.com/SteelSeries/set.v0 v0.0.0-20141210084824-27c40922c40b
 	github.com/niemeyer/pretty v0.0.0-20200227124842-a10e7caefd8e // indirect
+	golang.org/x/text v0.3.8
 	gopkg.in/check.v1 v1.0.0-20200902074654-038fdea0a05b
 )
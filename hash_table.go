@@ -0,0 +1,307 @@
+// Copyright 2014 SteelSeries ApS.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// This package implements a basic LISP interpretor for embedding in a go program for scripting.
+// This file implements the hash table data type.
+
+package golisp
+
+import (
+	"hash/fnv"
+	"math"
+	"sync"
+)
+
+// How a hash table compares keys.
+const (
+	EqualHashTable = iota
+	EqvHashTable
+	StringHashTable
+)
+
+type hashTableEntry struct {
+	Key   *Data
+	Value *Data
+}
+
+// Entries are bucketed by the hash of their key; keys within a bucket are
+// told apart with the table's comparison.
+type HashTable struct {
+	Kind    int
+	Buckets map[uint64][]*hashTableEntry
+	Count   int
+	Mutex   sync.RWMutex
+}
+
+func MakeHashTable(kind int, size int) *HashTable {
+	return &HashTable{Kind: kind, Buckets: make(map[uint64][]*hashTableEntry, size)}
+}
+
+func (self *HashTable) KindName() string {
+	switch self.Kind {
+	case EqvHashTable:
+		return "eqv?"
+	case StringHashTable:
+		return "string=?"
+	default:
+		return "equal?"
+	}
+}
+
+// Whether key can be used in the table. String tables only take strings.
+func (self *HashTable) AcceptsKey(key *Data) bool {
+	return self.Kind != StringHashTable || StringP(key)
+}
+
+func (self *HashTable) keysMatch(a *Data, b *Data) bool {
+	switch self.Kind {
+	case EqvHashTable:
+		return isEqv(a, b)
+	case StringHashTable:
+		return StringValue(a) == StringValue(b)
+	default:
+		return IsEqual(a, b)
+	}
+}
+
+func (self *HashTable) hash(key *Data) uint64 {
+	switch self.Kind {
+	case EqvHashTable:
+		return eqvHash(key)
+	case StringHashTable:
+		return stringHash(StringValue(key))
+	default:
+		return equalHash(key)
+	}
+}
+
+func (self *HashTable) find(key *Data) (entry *hashTableEntry, h uint64) {
+	h = self.hash(key)
+	for _, e := range self.Buckets[h] {
+		if self.keysMatch(e.Key, key) {
+			return e, h
+		}
+	}
+	return nil, h
+}
+
+func (self *HashTable) Get(key *Data) (value *Data, found bool) {
+	self.Mutex.RLock()
+	defer self.Mutex.RUnlock()
+	entry, _ := self.find(key)
+	if entry == nil {
+		return nil, false
+	}
+	return entry.Value, true
+}
+
+func (self *HashTable) Set(key *Data, value *Data) {
+	self.Mutex.Lock()
+	defer self.Mutex.Unlock()
+	entry, h := self.find(key)
+	if entry != nil {
+		entry.Value = value
+		return
+	}
+	self.Buckets[h] = append(self.Buckets[h], &hashTableEntry{Key: key, Value: value})
+	self.Count++
+}
+
+func (self *HashTable) Delete(key *Data) bool {
+	self.Mutex.Lock()
+	defer self.Mutex.Unlock()
+	h := self.hash(key)
+	bucket := self.Buckets[h]
+	for i, e := range bucket {
+		if self.keysMatch(e.Key, key) {
+			if len(bucket) == 1 {
+				delete(self.Buckets, h)
+			} else {
+				self.Buckets[h] = append(bucket[:i:i], bucket[i+1:]...)
+			}
+			self.Count--
+			return true
+		}
+	}
+	return false
+}
+
+func (self *HashTable) Clear() {
+	self.Mutex.Lock()
+	self.Buckets = make(map[uint64][]*hashTableEntry)
+	self.Count = 0
+	self.Mutex.Unlock()
+}
+
+func (self *HashTable) Size() int {
+	self.Mutex.RLock()
+	defer self.Mutex.RUnlock()
+	return self.Count
+}
+
+// A snapshot of the entries, so that they can be visited without holding the
+// lock while lisp code runs.
+func (self *HashTable) Entries() []hashTableEntry {
+	self.Mutex.RLock()
+	defer self.Mutex.RUnlock()
+	entries := make([]hashTableEntry, 0, self.Count)
+	for _, bucket := range self.Buckets {
+		for _, e := range bucket {
+			entries = append(entries, *e)
+		}
+	}
+	return entries
+}
+
+// Keys are shared with the original, since for eqv? tables their identity
+// matters; values are copied.
+func (self *HashTable) Copy() *HashTable {
+	self.Mutex.RLock()
+	defer self.Mutex.RUnlock()
+	table := MakeHashTable(self.Kind, self.Count)
+	for h, bucket := range self.Buckets {
+		copied := make([]*hashTableEntry, len(bucket))
+		for i, e := range bucket {
+			copied[i] = &hashTableEntry{Key: e.Key, Value: Copy(e.Value)}
+		}
+		table.Buckets[h] = copied
+	}
+	table.Count = self.Count
+	return table
+}
+
+func (self *HashTable) IsEqual(other *HashTable) bool {
+	if self == other {
+		return true
+	}
+	if self.Kind != other.Kind || self.Size() != other.Size() {
+		return false
+	}
+	for _, e := range self.Entries() {
+		value, found := other.Get(e.Key)
+		if !found || !IsEqual(e.Value, value) {
+			return false
+		}
+	}
+	return true
+}
+
+// Numbers, booleans, symbols and characters are eqv? when they have the same value;
+// anything else only when it is the same object.
+func isEqv(a *Data, b *Data) bool {
+	if a == b {
+		return true
+	}
+	if NilP(a) || NilP(b) {
+		return NilP(a) && NilP(b)
+	}
+	if TypeOf(a) != TypeOf(b) {
+		return false
+	}
+	switch TypeOf(a) {
+	case IntegerType, BignumType, RationalType, FloatType, BooleanType, SymbolType, CharacterType:
+		return IsEqual(a, b)
+	}
+	return a.Value == b.Value
+}
+
+func eqvHash(d *Data) uint64 {
+	if NilP(d) {
+		return 0
+	}
+	switch TypeOf(d) {
+	case IntegerType, BignumType, RationalType, FloatType, BooleanType, SymbolType, CharacterType:
+		return equalHash(d)
+	}
+	return uint64(uintptr(d.Value))
+}
+
+func stringHash(s string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(s))
+	return h.Sum64()
+}
+
+func mixHash(h uint64) uint64 {
+	h ^= h >> 33
+	h *= 0xff51afd7ed558ccd
+	h ^= h >> 33
+	return h
+}
+
+// A hash that agrees with IsEqual: data that are equal hash the same. The
+// elements of lists and the slots of frames are combined without regard to
+// order, since alists compare equal to their rearrangements.
+func equalHash(d *Data) uint64 {
+	if NilP(d) {
+		return 0
+	}
+
+	switch TypeOf(d) {
+	case ConsCellType, AlistType, AlistCellType:
+		var h uint64 = 1
+		c := d
+		for ; NotNilP(c) && (PairP(c) || AlistP(c) || DottedPairP(c)); c = Cdr(c) {
+			h += mixHash(equalHash(Car(c)))
+		}
+		if NotNilP(c) {
+			h += mixHash(equalHash(c) + 1)
+		}
+		return h
+	case IntegerType:
+		return uint64(IntegerValue(d))
+	case BignumType:
+		return stringHash(BignumValue(d).String())
+	case RationalType:
+		return stringHash(RationalValue(d).String())
+	case FloatType:
+		f := FloatValue(d)
+		if f == 0 {
+			return 0
+		}
+		return math.Float64bits(f)
+	case BooleanType:
+		if BooleanValue(d) {
+			return 1
+		}
+		return 2
+	case StringType, SymbolType:
+		return stringHash(StringValue(d))
+	case CharacterType:
+		return uint64(CharacterValue(d))
+	case FrameType:
+		frame := FrameValue(d)
+		frame.Mutex.RLock()
+		defer frame.Mutex.RUnlock()
+		var h uint64 = 2
+		for k, v := range frame.Data {
+			h += mixHash(stringHash(k) ^ equalHash(v))
+		}
+		return h
+	case VectorType:
+		var h uint64 = 3
+		for _, e := range VectorValue(d) {
+			h = h*31 + equalHash(e)
+		}
+		return h
+	case HashTableType:
+		return uint64(HashTableValue(d).Kind) + uint64(HashTableValue(d).Size())
+	case RecordType:
+		record := RecordValue(d)
+		h := stringHash(record.Descriptor.Name)
+		for _, field := range record.Fields {
+			h = h*31 + equalHash(field)
+		}
+		return h
+	case BoxedObjectType:
+		if ObjectType(d) == "[]byte" {
+			h := fnv.New64a()
+			h.Write(*(*[]byte)(ObjectValue(d)))
+			return h.Sum64()
+		}
+		return uint64(uintptr(ObjectValue(d)))
+	}
+
+	return uint64(uintptr(d.Value))
+}
@@ -0,0 +1,66 @@
+// Copyright 2014 SteelSeries ApS.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// This package implements a basic LISP interpretor for embedding in a go program for scripting.
+// This file tests hash tables.
+
+package golisp
+
+import (
+	. "gopkg.in/check.v1"
+)
+
+type HashTableSuite struct{}
+
+var _ = Suite(&HashTableSuite{})
+
+func (s *HashTableSuite) TestEqualKeys(c *C) {
+	table := MakeHashTable(EqualHashTable, 0)
+	table.Set(InternalMakeList(IntegerWithValue(1), StringWithValue("a")), IntegerWithValue(10))
+	value, found := table.Get(InternalMakeList(IntegerWithValue(1), StringWithValue("a")))
+	c.Assert(found, Equals, true)
+	c.Assert(IntegerValue(value), Equals, int64(10))
+	_, found = table.Get(InternalMakeList(StringWithValue("a"), IntegerWithValue(1)))
+	c.Assert(found, Equals, false)
+	c.Assert(table.Size(), Equals, 1)
+}
+
+func (s *HashTableSuite) TestEqvKeys(c *C) {
+	table := MakeHashTable(EqvHashTable, 0)
+	key := StringWithValue("a")
+	table.Set(key, IntegerWithValue(1))
+	table.Set(IntegerWithValue(5), IntegerWithValue(2))
+	_, found := table.Get(key)
+	c.Assert(found, Equals, true)
+	_, found = table.Get(StringWithValue("a"))
+	c.Assert(found, Equals, false)
+	_, found = table.Get(IntegerWithValue(5))
+	c.Assert(found, Equals, true)
+}
+
+func (s *HashTableSuite) TestDelete(c *C) {
+	table := MakeHashTable(StringHashTable, 0)
+	table.Set(StringWithValue("a"), IntegerWithValue(1))
+	c.Assert(table.Delete(StringWithValue("a")), Equals, true)
+	c.Assert(table.Delete(StringWithValue("a")), Equals, false)
+	c.Assert(table.Size(), Equals, 0)
+}
+
+func (s *HashTableSuite) TestString(c *C) {
+	table := MakeHashTable(EqualHashTable, 0)
+	table.Set(Intern("a"), IntegerWithValue(1))
+	c.Assert(String(HashTableWithValue(table)), Equals, "<hash table (equal?): 1 entries>")
+}
+
+func (s *HashTableSuite) TestCopyAndEquality(c *C) {
+	table := MakeHashTable(EqualHashTable, 0)
+	table.Set(Intern("a"), InternalMakeList(IntegerWithValue(1)))
+	original := HashTableWithValue(table)
+	copied := Copy(original)
+	c.Assert(copied == original, Equals, false)
+	c.Assert(IsEqual(original, copied), Equals, true)
+	HashTableValue(copied).Set(Intern("b"), IntegerWithValue(2))
+	c.Assert(IsEqual(original, copied), Equals, false)
+	c.Assert(table.Size(), Equals, 1)
+}
@@ -12,6 +12,7 @@ import (
 	"fmt"
 	"math"
 	"reflect"
+	"strconv"
 	"strings"
 )
 
@@ -161,10 +162,14 @@ func jsonToLispWithFramesReflect(rv reflect.Value) *Data {
 		return IntegerWithValue(int64(rv.Uint()))
 	case reflect.Float32, reflect.Float64:
 		floatValue := rv.Float()
+		if rv.Kind() == reflect.Float32 {
+			// Widen to the float64 nearest the float32's shortest decimal form, so 1.1 stays 1.1
+			floatValue, _ = strconv.ParseFloat(strconv.FormatFloat(floatValue, 'g', -1, 32), 64)
+		}
 		if math.Trunc(floatValue) == floatValue {
 			return IntegerWithValue(int64(floatValue))
 		} else {
-			return FloatWithValue(float32(floatValue))
+			return FloatWithValue(floatValue)
 		}
 	case reflect.String:
 		return StringWithValue(rv.String())
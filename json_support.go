@@ -40,7 +40,7 @@ func JsonToLisp(json interface{}) (result *Data) {
 		if math.Trunc(numValue) == numValue {
 			return IntegerWithValue(int64(numValue))
 		} else {
-			return FloatWithValue(float32(numValue))
+			return FloatWithValue(numValue)
 		}
 	}
 
@@ -76,6 +76,10 @@ func LispToJson(d *Data) (result interface{}) {
 		return IntegerValue(d)
 	}
 
+	if FloatP(d) {
+		return FloatValue(d)
+	}
+
 	if StringP(d) || SymbolP(d) {
 		return StringValue(d)
 	}
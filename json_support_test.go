@@ -68,6 +68,11 @@ func (s *JsonLispSuite) TestJsonToLispMixedWithFrames(c *C) {
 	c.Assert(IsEqual(sexpr, expected), Equals, true)
 }
 
+func (s *JsonLispSuite) TestJsonToLispFloatPrecision(c *C) {
+	sexpr := JsonStringToLisp(`[3.141592653589793]`)
+	c.Assert(FloatValue(Car(sexpr)), Equals, 3.141592653589793)
+}
+
 func (s *JsonLispSuite) TestJsonToLispIllegal(c *C) {
 	c.Assert(func() { JsonStringToLisp("hello") }, PanicMatches, `Badly formed json: 'hello'`)
 }
@@ -84,6 +89,12 @@ func (s *JsonLispSuite) TestLispToJsonArray(c *C) {
 	c.Assert(data, Equals, `[1,2,"hi"]`)
 }
 
+func (s *JsonLispSuite) TestLispToJsonFloat(c *C) {
+	alist := Acons(StringWithValue("pi"), FloatWithValue(3.141592653589793), nil)
+	data := LispToJsonString(alist)
+	c.Assert(data, Equals, `{"pi":3.141592653589793}`)
+}
+
 func (s *JsonLispSuite) TestLispToJsonMixed(c *C) {
 	alist := Acons(StringWithValue("map"), Acons(StringWithValue("f1"), InternalMakeList(IntegerWithValue(47), IntegerWithValue(75)), Acons(StringWithValue("f2"), IntegerWithValue(185), nil)), Acons(StringWithValue("f3"), IntegerWithValue(85), nil))
 	data := LispToJsonString(alist)
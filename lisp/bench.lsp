@@ -131,7 +131,7 @@
          (results (map (lambda (ignored)
                          (time (run-bench name count run)))
                        (interval loop-count))))
-     (list name count loop-count (min results) (max results) (/ (apply + results) loop-count))))
+     (list name count loop-count (min results) (max results) (quotient (apply + results) loop-count))))
 
 (define (fatal-error . args)
     (for-each (lambda (x) (format #t "~A " x)) args)
@@ -202,3 +202,11 @@
                   (format #t "~VA | ~VA | ~VA | ~VA | ~VA | ~VA~%" name-width name outer-width outer-count inner-width inner-count min-width min max-width max avg-width avg)))
               results)
     (format #t "~V~+~V~+~V~+~V~+~V~+~V~~%" (+ 1 name-width) (+ 2 outer-width) (+ 2 inner-width) (+ 2 min-width) (+ 2 max-width) (+ 1 avg-width))))
+
+(define (compare)
+  (let ((was-compiling (compile-functions)))
+    (compile-functions #f)
+    (run "interpreted functions")
+    (compile-functions #t)
+    (run "compiled functions")
+    (compile-functions was-compiling)))
@@ -97,7 +97,7 @@
                 (let ((name (car d))
                       (count (cadr d))
                       (time (caddr d)))
-                  (format #t "~VA | ~V@A | ~V@A | ~VA~%" name-width name count-width count total-width time avg-width (/ time count))))
+                  (format #t "~VA | ~V@A | ~V@A | ~VA~%" name-width name count-width count total-width time avg-width (quotient time count))))
               call-data)
     (format #t "~V~+~V~+~V~+~V~~%" (+ 1 name-width) (+ 2 count-width) (+ 2 total-width) (+ 1 avg-width))))
 
@@ -63,9 +63,9 @@
               (set! it-name ,label)
               (on-error (begin ,@body)
                         (lambda (err)
-                          (let* ((err-parts (string-split err "\n"))
-                                 (last-line (car (last-pair err-parts))))
-                            (log-error (cadr (string-split last-line ". ")))))))))
+                          (log-error (if (condition? err)
+                                         (condition/report-string err)
+                                         err)))))))
 
 (defmacro (assert-true sexpr)
   `(let ((actual ,sexpr)
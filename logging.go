@@ -23,6 +23,11 @@ func init() {
 
 func LogPrintf(format string, a ...interface{}) {
 	fmt.Printf(format, a...)
+	logToLoggers(format, a...)
+}
+
+// Writes to the added loggers only.
+func logToLoggers(format string, a ...interface{}) {
 	for _, logger := range loggers {
 		logger.Printf(format, a...)
 	}
@@ -8,7 +8,6 @@
 package golisp
 
 import (
-	"errors"
 	"fmt"
 )
 
@@ -19,6 +18,7 @@ type Macro struct {
 	RequiredArgCount int
 	Body             *Data
 	Env              *SymbolTableFrame
+	Rules            *SyntaxRules
 }
 
 func MakeMacro(name string, params *Data, body *Data, parentEnv *SymbolTableFrame) *Macro {
@@ -33,17 +33,20 @@ func (self *Macro) String() string {
 func (self *Macro) makeLocalBindings(args *Data, argEnv *SymbolTableFrame, localEnv *SymbolTableFrame, eval bool) (err error) {
 	if self.VarArgs {
 		if Length(args) < self.RequiredArgCount {
-			return errors.New(fmt.Sprintf("%s expected at least %d parameters, received %d.", self.Name, self.RequiredArgCount, Length(args)))
+			return MakeCondition(WrongNumberOfArgumentsCondition, fmt.Sprintf("%s expected at least %d parameters, received %d.", self.Name, self.RequiredArgCount, Length(args)), nil)
 		}
 	} else {
 		if Length(args) != self.RequiredArgCount {
-			return errors.New(fmt.Sprintf("%s expected %d parameters, received %d.", self.Name, self.RequiredArgCount, Length(args)))
+			return MakeCondition(WrongNumberOfArgumentsCondition, fmt.Sprintf("%s expected %d parameters, received %d.", self.Name, self.RequiredArgCount, Length(args)), nil)
 		}
 	}
 
 	var argValue *Data
 	var accumulatingParam *Data = nil
 	accumulatedArgs := make([]*Data, 0)
+	if SymbolP(self.Params) {
+		accumulatingParam = self.Params
+	}
 	for p, a := self.Params, args; NotNilP(a); a = Cdr(a) {
 		if eval {
 			argValue, err = Eval(Car(a), argEnv)
@@ -78,7 +81,15 @@ func (self *Macro) makeLocalBindings(args *Data, argEnv *SymbolTableFrame, local
 	return nil
 }
 
+func MakeSyntaxRulesMacro(name string, rules *SyntaxRules, parentEnv *SymbolTableFrame) *Macro {
+	return &Macro{Name: name, Rules: rules, Env: parentEnv}
+}
+
 func (self *Macro) Expand(args *Data, argEnv *SymbolTableFrame) (result *Data, err error) {
+	if self.Rules != nil {
+		return self.Rules.Expand(self.Name, args, argEnv)
+	}
+
 	localEnv := NewSymbolTableFrameBelow(self.Env, self.Name)
 	err = self.makeLocalBindings(args, argEnv, localEnv, false)
 	if err != nil {
@@ -104,3 +115,147 @@ func (self *Macro) Apply(args *Data, argEnv *SymbolTableFrame) (result *Data, er
 func (self *Macro) ApplyWithoutEval(args *Data, argEnv *SymbolTableFrame) (result *Data, err error) {
 	return self.internalApply(args, argEnv, false)
 }
+
+// Expands the macro use d, reusing the expansion made the last time d was
+// evaluated as long as the macro and argument list are unchanged. Like a
+// compiler would, this assumes a macro's expansion depends only on its
+// arguments.
+func (self *Macro) ExpandAtCallSite(d *Data, argEnv *SymbolTableFrame) (result *Data, err error) {
+	cell := (*ConsCell)(d.Value)
+	args := cell.Cdr
+	info := cell.loadInfo()
+	if info != nil && info.Macro == self && info.Args == args {
+		return info.Expansion, nil
+	}
+
+	result, err = self.Expand(args, argEnv)
+	if err != nil {
+		return
+	}
+
+	expanded := &consInfo{Macro: self, Args: args, Expansion: result}
+	if info != nil {
+		expanded.Location = info.Location
+	}
+	cell.storeInfo(expanded)
+	return
+}
+
+// The macro named by the head of form, if there is one.
+func macroForm(form *Data, env *SymbolTableFrame) *Macro {
+	if !PairP(form) || NilP(form) || !SymbolP(Car(form)) {
+		return nil
+	}
+	return MacroValue(env.ValueOf(Car(form)))
+}
+
+func MacroExpand1(form *Data, env *SymbolTableFrame) (result *Data, err error) {
+	macro := macroForm(form, env)
+	if macro == nil {
+		return form, nil
+	}
+	return macro.Expand(Cdr(form), env)
+}
+
+// Expands form until its head is no longer a macro.
+func MacroExpand(form *Data, env *SymbolTableFrame) (result *Data, err error) {
+	result = form
+	for macro := macroForm(result, env); macro != nil; macro = macroForm(result, env) {
+		result, err = macro.Expand(Cdr(result), env)
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+func macroExpandEach(forms []*Data, env *SymbolTableFrame) (err error) {
+	for i, f := range forms {
+		forms[i], err = MacroExpandAll(f, env)
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+func macroExpandQuasiquoted(form *Data, env *SymbolTableFrame) (result *Data, err error) {
+	if !PairP(form) || NilP(form) {
+		return form, nil
+	}
+	if SymbolP(Car(form)) && (StringValue(Car(form)) == "unquote" || StringValue(Car(form)) == "unquote-splicing") {
+		var expanded *Data
+		expanded, err = MacroExpandAll(Cadr(form), env)
+		if err != nil {
+			return
+		}
+		return InternalMakeList(Car(form), expanded), nil
+	}
+
+	elements, tail := listParts(form)
+	for i, e := range elements {
+		elements[i], err = macroExpandQuasiquoted(e, env)
+		if err != nil {
+			return
+		}
+	}
+	return ArrayToListWithTail(elements, tail), nil
+}
+
+// Expands every macro use in form. Quoted data and the parameter and binding
+// names of the core binding forms are left alone. Bodies of let-syntax and
+// letrec-syntax are not expanded since their macros aren't bound yet.
+func MacroExpandAll(form *Data, env *SymbolTableFrame) (result *Data, err error) {
+	form, err = MacroExpand(form, env)
+	if err != nil || !PairP(form) || NilP(form) {
+		return form, err
+	}
+
+	elements, tail := listParts(form)
+	keep := 0
+	if SymbolP(elements[0]) {
+		keep = 1
+		switch StringValue(elements[0]) {
+		case "quote", "syntax-rules", "define-syntax", "let-syntax", "letrec-syntax":
+			return form, nil
+		case "quasiquote":
+			result, err = macroExpandQuasiquoted(form, env)
+			SetSourceLocation(result, SourceLocationOf(form))
+			return
+		case "lambda", "named-lambda", "define", "defmacro":
+			keep = 2
+		case "let", "let*", "letrec", "letrec*", "do":
+			if len(elements) < 2 {
+				break
+			}
+			keep = 2
+			bindingIndex := 1
+			if SymbolP(elements[1]) && len(elements) > 2 {
+				keep, bindingIndex = 3, 2
+			}
+			bindings, bindingTail := listParts(elements[bindingIndex])
+			for i, b := range bindings {
+				if PairP(b) && NotNilP(b) {
+					parts, partsTail := listParts(b)
+					err = macroExpandEach(parts[1:], env)
+					if err != nil {
+						return
+					}
+					bindings[i] = ArrayToListWithTail(parts, partsTail)
+				}
+			}
+			elements[bindingIndex] = ArrayToListWithTail(bindings, bindingTail)
+		}
+	}
+
+	if keep > len(elements) {
+		keep = len(elements)
+	}
+	err = macroExpandEach(elements[keep:], env)
+	if err != nil {
+		return
+	}
+	result = ArrayToListWithTail(elements, tail)
+	SetSourceLocation(result, SourceLocationOf(form))
+	return
+}
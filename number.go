@@ -0,0 +1,334 @@
+// Copyright 2014 SteelSeries ApS.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// This package implements a basic LISP interpretor for embedding in a go program for scripting.
+// This file implements the numeric tower: fixnums, bignums, exact rationals and floats.
+
+package golisp
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Exact integers are fixnums (IntegerType) whenever they fit in an int64 and
+// bignums only when they don't, and a rational with a denominator of 1 is an
+// integer. Every exact result is normalized this way, so equal numbers always
+// have the same representation.
+
+func ExactIntegerWithValue(n *big.Int) *Data {
+	if n.IsInt64() {
+		return IntegerWithValue(n.Int64())
+	}
+	return BignumWithValue(n)
+}
+
+func ExactRationalWithValue(r *big.Rat) *Data {
+	if r.IsInt() {
+		return ExactIntegerWithValue(new(big.Int).Set(r.Num()))
+	}
+	return RationalWithValue(r)
+}
+
+func ExactIntegerP(d *Data) bool {
+	return IntegerP(d) || BignumP(d)
+}
+
+func ExactP(d *Data) bool {
+	return ExactIntegerP(d) || RationalP(d)
+}
+
+// The value of an exact integer as a big.Int, which must not be modified.
+func bigIntValue(d *Data) *big.Int {
+	if BignumP(d) {
+		return BignumValue(d)
+	}
+	return big.NewInt(IntegerValue(d))
+}
+
+// The value of an exact number as a big.Rat, which must not be modified.
+func bigRatValue(d *Data) *big.Rat {
+	if RationalP(d) {
+		return RationalValue(d)
+	}
+	return new(big.Rat).SetInt(bigIntValue(d))
+}
+
+func exactToFloat64(d *Data) float64 {
+	if IntegerP(d) {
+		return float64(IntegerValue(d))
+	}
+	f, _ := bigRatValue(d).Float64()
+	return f
+}
+
+// The exact number equal to a float, or an error for infinities and NaN.
+func floatToExact(f float64) (*Data, error) {
+	if math.IsInf(f, 0) || math.IsNaN(f) {
+		return nil, fmt.Errorf("%g has no exact representation", f)
+	}
+	return ExactRationalWithValue(new(big.Rat).SetFloat64(f)), nil
+}
+
+// The integer part of an exact number, rounded toward zero.
+func truncateExact(d *Data) *Data {
+	if !RationalP(d) {
+		return d
+	}
+	r := RationalValue(d)
+	return ExactIntegerWithValue(new(big.Int).Quo(r.Num(), r.Denom()))
+}
+
+func floorExact(d *Data) *Data {
+	if !RationalP(d) {
+		return d
+	}
+	r := RationalValue(d)
+	// Div rounds toward negative infinity for a positive divisor, and the
+	// denominator is always positive.
+	return ExactIntegerWithValue(new(big.Int).Div(r.Num(), r.Denom()))
+}
+
+func ceilingExact(d *Data) *Data {
+	if !RationalP(d) {
+		return d
+	}
+	return ExactIntegerWithValue(new(big.Int).Add(bigIntValue(floorExact(d)), big.NewInt(1)))
+}
+
+func numberSign(d *Data) int {
+	switch TypeOf(d) {
+	case IntegerType:
+		return int(intSgn(IntegerValue(d)))
+	case BignumType:
+		return BignumValue(d).Sign()
+	case RationalType:
+		return RationalValue(d).Sign()
+	default:
+		return int(sgn(FloatValue(d)))
+	}
+}
+
+func negateNumber(d *Data) *Data {
+	switch TypeOf(d) {
+	case IntegerType:
+		if IntegerValue(d) != math.MinInt64 {
+			return IntegerWithValue(-IntegerValue(d))
+		}
+		return ExactIntegerWithValue(new(big.Int).Neg(bigIntValue(d)))
+	case BignumType:
+		return ExactIntegerWithValue(new(big.Int).Neg(BignumValue(d)))
+	case RationalType:
+		return ExactRationalWithValue(new(big.Rat).Neg(RationalValue(d)))
+	default:
+		return FloatWithValue(-FloatValue(d))
+	}
+}
+
+func isZeroNumber(d *Data) bool {
+	if FloatP(d) {
+		return FloatValue(d) == 0
+	}
+	return numberSign(d) == 0
+}
+
+const (
+	addOperation = iota
+	subtractOperation
+	multiplyOperation
+	divideOperation
+)
+
+// Combines two numbers. The result is a float if either of them is and
+// exact otherwise, becoming a bignum rather than overflowing. Exact division
+// yields a rational when it isn't even. The caller checks for division by
+// zero.
+func combineNumbers(operation int, a *Data, b *Data) *Data {
+	if FloatP(a) || FloatP(b) {
+		x, y := FloatValue(a), FloatValue(b)
+		switch operation {
+		case addOperation:
+			return FloatWithValue(x + y)
+		case subtractOperation:
+			return FloatWithValue(x - y)
+		case multiplyOperation:
+			return FloatWithValue(x * y)
+		default:
+			return FloatWithValue(x / y)
+		}
+	}
+
+	if IntegerP(a) && IntegerP(b) {
+		if result := combineFixnums(operation, IntegerValue(a), IntegerValue(b)); result != nil {
+			return result
+		}
+	}
+
+	if RationalP(a) || RationalP(b) || operation == divideOperation {
+		x, y := bigRatValue(a), bigRatValue(b)
+		r := new(big.Rat)
+		switch operation {
+		case addOperation:
+			r.Add(x, y)
+		case subtractOperation:
+			r.Sub(x, y)
+		case multiplyOperation:
+			r.Mul(x, y)
+		default:
+			r.Quo(x, y)
+		}
+		return ExactRationalWithValue(r)
+	}
+
+	x, y := bigIntValue(a), bigIntValue(b)
+	n := new(big.Int)
+	switch operation {
+	case addOperation:
+		n.Add(x, y)
+	case subtractOperation:
+		n.Sub(x, y)
+	default:
+		n.Mul(x, y)
+	}
+	return ExactIntegerWithValue(n)
+}
+
+// The result of combining two fixnums, or nil when it doesn't fit in one.
+func combineFixnums(operation int, x int64, y int64) *Data {
+	switch operation {
+	case addOperation:
+		sum := x + y
+		if (x^sum)&(y^sum) >= 0 {
+			return IntegerWithValue(sum)
+		}
+	case subtractOperation:
+		difference := x - y
+		if (x^y)&(x^difference) >= 0 {
+			return IntegerWithValue(difference)
+		}
+	case multiplyOperation:
+		if x == 0 || y == 0 {
+			return IntegerWithValue(0)
+		}
+		product := x * y
+		if product/y == x && !(x == -1 && y == math.MinInt64) && !(y == -1 && x == math.MinInt64) {
+			return IntegerWithValue(product)
+		}
+	case divideOperation:
+		if x%y == 0 && !(x == math.MinInt64 && y == -1) {
+			return IntegerWithValue(x / y)
+		}
+	}
+	return nil
+}
+
+// Integer division, truncating toward zero. Floats are divided as they are.
+func quotientNumbers(a *Data, b *Data) *Data {
+	if FloatP(a) || FloatP(b) {
+		return combineNumbers(divideOperation, a, b)
+	}
+	if IntegerP(a) && IntegerP(b) && !(IntegerValue(a) == math.MinInt64 && IntegerValue(b) == -1) {
+		return IntegerWithValue(IntegerValue(a) / IntegerValue(b))
+	}
+	return truncateExact(combineNumbers(divideOperation, a, b))
+}
+
+// The remainder of truncating division of two exact integers.
+func remainderNumbers(a *Data, b *Data) *Data {
+	if IntegerP(a) && IntegerP(b) {
+		if IntegerValue(b) == -1 {
+			return IntegerWithValue(0)
+		}
+		return IntegerWithValue(IntegerValue(a) % IntegerValue(b))
+	}
+	return ExactIntegerWithValue(new(big.Int).Rem(bigIntValue(a), bigIntValue(b)))
+}
+
+// Compares two numbers, returning -1, 0 or 1. ordered is false when either is
+// NaN.
+func compareNumbers(a *Data, b *Data) (comparison int, ordered bool) {
+	if FloatP(a) || FloatP(b) {
+		x, y := FloatValue(a), FloatValue(b)
+		switch {
+		case x < y:
+			return -1, true
+		case x > y:
+			return 1, true
+		case x == y:
+			return 0, true
+		default:
+			return 0, false
+		}
+	}
+	if IntegerP(a) && IntegerP(b) {
+		x, y := IntegerValue(a), IntegerValue(b)
+		switch {
+		case x < y:
+			return -1, true
+		case x > y:
+			return 1, true
+		default:
+			return 0, true
+		}
+	}
+	if ExactIntegerP(a) && ExactIntegerP(b) {
+		return bigIntValue(a).Cmp(bigIntValue(b)), true
+	}
+	return bigRatValue(a).Cmp(bigRatValue(b)), true
+}
+
+// Raises an exact number to an exact integer power, which may be negative.
+func exactPower(base *Data, exponent *Data) (result *Data, err error) {
+	e := bigIntValue(exponent)
+	if numberSign(base) == 0 && e.Sign() < 0 {
+		return nil, errors.New("Division by zero")
+	}
+	r := bigRatValue(base)
+	num := new(big.Int).Exp(r.Num(), new(big.Int).Abs(e), nil)
+	den := new(big.Int).Exp(r.Denom(), new(big.Int).Abs(e), nil)
+	if e.Sign() < 0 {
+		num, den = den, num
+	}
+	return ExactRationalWithValue(new(big.Rat).SetFrac(num, den)), nil
+}
+
+// The text of an exact number in the given base.
+func exactNumberText(d *Data, base int) string {
+	if RationalP(d) {
+		r := RationalValue(d)
+		return fmt.Sprintf("%s/%s", r.Num().Text(base), r.Denom().Text(base))
+	}
+	if IntegerP(d) {
+		return strconv.FormatInt(IntegerValue(d), base)
+	}
+	return BignumValue(d).Text(base)
+}
+
+// Parses an exact integer or a rational written as numerator/denominator.
+func ParseExactNumber(str string, base int) (n *Data, err error) {
+	if i, parseErr := strconv.ParseInt(str, base, 64); parseErr == nil {
+		return IntegerWithValue(i), nil
+	}
+
+	parts := strings.Split(str, "/")
+	if len(parts) > 2 {
+		return nil, fmt.Errorf("Invalid number: %s", str)
+	}
+	num, ok := new(big.Int).SetString(parts[0], base)
+	if !ok {
+		return nil, fmt.Errorf("Invalid number: %s", str)
+	}
+	if len(parts) == 1 {
+		return ExactIntegerWithValue(num), nil
+	}
+	den, ok := new(big.Int).SetString(parts[1], base)
+	if !ok || den.Sign() <= 0 || strings.HasPrefix(parts[1], "+") {
+		return nil, fmt.Errorf("Invalid number: %s", str)
+	}
+	return ExactRationalWithValue(new(big.Rat).SetFrac(num, den)), nil
+}
@@ -8,6 +8,9 @@
 package golisp
 
 import (
+	"math"
+	"math/big"
+
 	. "gopkg.in/check.v1"
 )
 
@@ -29,6 +32,12 @@ func (s *IntegerAtomSuite) TestIntegerValueOfNil(c *C) {
 	c.Assert(IntegerValue(nil), Equals, int64(0))
 }
 
+func (s *IntegerAtomSuite) TestIntegerValueOfBignumSaturates(c *C) {
+	huge := new(big.Int).Lsh(big.NewInt(1), 70)
+	c.Assert(IntegerValue(ExactIntegerWithValue(huge)), Equals, int64(math.MaxInt64))
+	c.Assert(IntegerValue(ExactIntegerWithValue(huge.Neg(huge))), Equals, int64(math.MinInt64))
+}
+
 func (s *IntegerAtomSuite) TestString(c *C) {
 	c.Assert(String(s.n), Equals, "5")
 }
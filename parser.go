@@ -12,48 +12,37 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"strconv"
+	"strings"
 	"unsafe"
+
+	"github.com/SteelSeries/bufrr"
 )
 
 var EofObject *Data = Intern("__EOF__")
 
 func makeInteger(str string) (n *Data, err error) {
-	var i int64
-	_, err = fmt.Sscanf(str, "%d", &i)
-	if err != nil {
-		return
-	}
-	n = IntegerWithValue(i)
-	return
+	return ParseExactNumber(str, 10)
 }
 
 func makeBinaryInteger(str string) (n *Data, err error) {
-	var i int64
-	_, err = fmt.Sscanf(str, "%b", &i)
-	if err != nil {
-		return
-	}
-	n = IntegerWithValue(i)
-	return
+	return ParseExactNumber(str, 2)
 }
 
 func makeHexInteger(str string) (n *Data, err error) {
-	var i int64
-	_, err = fmt.Sscanf(str, "%x", &i)
-	if err != nil {
-		return
-	}
-	n = IntegerWithValue(i)
-	return
+	return ParseExactNumber(str, 16)
+}
+
+func makeRational(str string) (n *Data, err error) {
+	return ParseExactNumber(str, 10)
 }
 
 func makeFloat(str string) (n *Data, err error) {
-	var i float32
-	_, err = fmt.Sscanf(str, "%f", &i)
+	f, err := strconv.ParseFloat(str, 64)
 	if err != nil {
 		return
 	}
-	n = FloatWithValue(i)
+	n = FloatWithValue(f)
 	return
 }
 
@@ -62,6 +51,14 @@ func makeString(str string) (s *Data, err error) {
 	return
 }
 
+func makeCharacter(str string) (c *Data, err error) {
+	ch, err := CharacterNamed(str)
+	if err != nil {
+		return
+	}
+	return CharacterWithValue(ch), nil
+}
+
 func makeSymbol(str string) (s *Data, err error) {
 	s = Intern(str)
 	return
@@ -166,6 +163,29 @@ func parseBytearray(s *Tokenizer) (sexpr *Data, eof bool, err error) {
 	return
 }
 
+func parseVector(s *Tokenizer) (sexpr *Data, eof bool, err error) {
+	tok, _ := s.NextToken()
+
+	var element *Data
+	elements := make([]*Data, 0, 10)
+	for tok != RPAREN {
+		element, eof, err = parseExpression(s)
+		if eof {
+			err = errors.New("Unexpected EOF (expected closing parenthesis)")
+			return
+		}
+		if err != nil {
+			return
+		}
+		elements = append(elements, element)
+		tok, _ = s.NextToken()
+	}
+
+	s.ConsumeToken()
+	sexpr = VectorWithValue(elements)
+	return
+}
+
 func parseFrame(s *Tokenizer) (sexpr *Data, eof bool, err error) {
 	tok, _ := s.NextToken()
 	if tok == RBRACKET {
@@ -199,6 +219,7 @@ func parseFrame(s *Tokenizer) (sexpr *Data, eof bool, err error) {
 func parseExpression(s *Tokenizer) (sexpr *Data, eof bool, err error) {
 	for {
 		tok, lit := s.NextToken()
+		loc := s.Location()
 		switch tok {
 		case EOF:
 			eof = true
@@ -223,6 +244,10 @@ func parseExpression(s *Tokenizer) (sexpr *Data, eof bool, err error) {
 			s.ConsumeToken()
 			sexpr, err = makeFloat(lit)
 			return
+		case RATIONAL:
+			s.ConsumeToken()
+			sexpr, err = makeRational(lit)
+			return
 		case STRING:
 			s.ConsumeToken()
 			sexpr, err = makeString(lit)
@@ -230,14 +255,25 @@ func parseExpression(s *Tokenizer) (sexpr *Data, eof bool, err error) {
 		case LPAREN:
 			s.ConsumeToken()
 			sexpr, eof, err = parseConsCell(s)
+			SetSourceLocation(sexpr, loc)
 			return
 		case LBRACKET:
 			s.ConsumeToken()
 			sexpr, eof, err = parseBytearray(s)
+			SetSourceLocation(sexpr, loc)
 			return
 		case LBRACE:
 			s.ConsumeToken()
 			sexpr, eof, err = parseFrame(s)
+			SetSourceLocation(sexpr, loc)
+			return
+		case CHARACTER:
+			s.ConsumeToken()
+			sexpr, err = makeCharacter(lit)
+			return
+		case HASHLPAREN:
+			s.ConsumeToken()
+			sexpr, eof, err = parseVector(s)
 			return
 		case SYMBOL:
 			s.ConsumeToken()
@@ -256,6 +292,7 @@ func parseExpression(s *Tokenizer) (sexpr *Data, eof bool, err error) {
 			sexpr, eof, err = parseExpression(s)
 			if sexpr != nil {
 				sexpr = Cons(Intern("quote"), Cons(sexpr, nil))
+				SetSourceLocation(sexpr, loc)
 			}
 			return
 		case BACKQUOTE:
@@ -263,6 +300,7 @@ func parseExpression(s *Tokenizer) (sexpr *Data, eof bool, err error) {
 			sexpr, eof, err = parseExpression(s)
 			if sexpr != nil {
 				sexpr = Cons(Intern("quasiquote"), Cons(sexpr, nil))
+				SetSourceLocation(sexpr, loc)
 			}
 			return
 		case COMMA:
@@ -270,6 +308,7 @@ func parseExpression(s *Tokenizer) (sexpr *Data, eof bool, err error) {
 			sexpr, eof, err = parseExpression(s)
 			if sexpr != nil {
 				sexpr = Cons(Intern("unquote"), Cons(sexpr, nil))
+				SetSourceLocation(sexpr, loc)
 			}
 			return
 		case COMMAAT:
@@ -277,6 +316,7 @@ func parseExpression(s *Tokenizer) (sexpr *Data, eof bool, err error) {
 			sexpr, eof, err = parseExpression(s)
 			if sexpr != nil {
 				sexpr = Cons(Intern("unquote-splicing"), Cons(sexpr, nil))
+				SetSourceLocation(sexpr, loc)
 			}
 			return
 		case ILLEGAL:
@@ -332,22 +372,31 @@ func ParseAndEval(src string) (result *Data, err error) {
 	return ParseAndEvalInEnvironment(src, Global)
 }
 
+// Errors are reported against the innermost form in the file that was being
+// evaluated, e.g. "config.lsp:42:7: ...", followed by the evaluation chain.
 func ProcessFileInEnvironment(filename string, env *SymbolTableFrame) (result *Data, err error) {
 	src, err := ReadFile(filename)
 	if err != nil {
 		return
 	}
-	result, err = ParseAndEvalAllInEnvironment(src, env)
+	s := NewTokenizerFromReader(bufrr.NewReader(strings.NewReader(src)), filename)
+	result, err = parseAndEvalAll(s, env)
 	return
 }
 
 func ParseAndEvalAllInEnvironment(src string, env *SymbolTableFrame) (result *Data, err error) {
-	s := NewTokenizerFromString(src)
+	return parseAndEvalAll(NewTokenizerFromString(src), env)
+}
+
+func parseAndEvalAll(s *Tokenizer, env *SymbolTableFrame) (result *Data, err error) {
 	var sexpr *Data
 	var eof bool
 	for {
 		sexpr, eof, err = parseExpression(s)
 		if err != nil {
+			if s.File != "" {
+				err = locatedError(s.Location(), err)
+			}
 			return
 		}
 		if eof {
@@ -358,6 +407,15 @@ func ParseAndEvalAllInEnvironment(src string, env *SymbolTableFrame) (result *Da
 		}
 		result, err = Eval(sexpr, env)
 		if err != nil {
+			if s.File != "" {
+				loc := InnermostSourceLocation(err)
+				if loc == nil {
+					loc = SourceLocationOf(sexpr)
+				}
+				if loc != nil {
+					err = locatedError(loc, err)
+				}
+			}
 			return
 		}
 	}
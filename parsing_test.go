@@ -8,7 +8,12 @@
 package golisp
 
 import (
+	"errors"
+	"fmt"
 	. "gopkg.in/check.v1"
+	"io/ioutil"
+	"os"
+	"strings"
 	"testing"
 )
 
@@ -52,6 +57,41 @@ func (s *ParsingSuite) TestHexInteger(c *C) {
 	c.Assert(IntegerValue(sexpr), Equals, int64(165))
 }
 
+func (s *ParsingSuite) TestBignum(c *C) {
+	sexpr, err := Parse("123456789012345678901234567890")
+	c.Assert(err, IsNil)
+	c.Assert(sexpr, NotNil)
+	c.Assert(int(TypeOf(sexpr)), Equals, BignumType)
+	c.Assert(String(sexpr), Equals, "123456789012345678901234567890")
+}
+
+func (s *ParsingSuite) TestLargestFixnum(c *C) {
+	sexpr, err := Parse("9223372036854775807")
+	c.Assert(err, IsNil)
+	c.Assert(int(TypeOf(sexpr)), Equals, IntegerType)
+	c.Assert(IntegerValue(sexpr), Equals, int64(9223372036854775807))
+}
+
+func (s *ParsingSuite) TestRational(c *C) {
+	sexpr, err := Parse("-6/4")
+	c.Assert(err, IsNil)
+	c.Assert(sexpr, NotNil)
+	c.Assert(int(TypeOf(sexpr)), Equals, RationalType)
+	c.Assert(String(sexpr), Equals, "-3/2")
+}
+
+func (s *ParsingSuite) TestWholeRational(c *C) {
+	sexpr, err := Parse("8/4")
+	c.Assert(err, IsNil)
+	c.Assert(int(TypeOf(sexpr)), Equals, IntegerType)
+	c.Assert(IntegerValue(sexpr), Equals, int64(2))
+}
+
+func (s *ParsingSuite) TestZeroDenominator(c *C) {
+	_, err := Parse("1/0")
+	c.Assert(err, NotNil)
+}
+
 func (s *ParsingSuite) TestProperHexInteger(c *C) {
 	sexpr, err := Parse("#xa5")
 	c.Assert(err, IsNil)
@@ -73,7 +113,7 @@ func (s *ParsingSuite) TestFloat(c *C) {
 	c.Assert(err, IsNil)
 	c.Assert(sexpr, NotNil)
 	c.Assert(int(TypeOf(sexpr)), Equals, FloatType)
-	c.Assert(FloatValue(sexpr), Equals, float32(12.345))
+	c.Assert(FloatValue(sexpr), Equals, 12.345)
 }
 
 func (s *ParsingSuite) TestNegativeFloat(c *C) {
@@ -81,7 +121,7 @@ func (s *ParsingSuite) TestNegativeFloat(c *C) {
 	c.Assert(err, IsNil)
 	c.Assert(sexpr, NotNil)
 	c.Assert(int(TypeOf(sexpr)), Equals, FloatType)
-	c.Assert(FloatValue(sexpr), Equals, float32(-12.345))
+	c.Assert(FloatValue(sexpr), Equals, -12.345)
 }
 
 func (s *ParsingSuite) TestUppercaseHexInteger(c *C) {
@@ -324,6 +364,47 @@ func (s *ParsingSuite) TestUnquoteSplicing(c *C) {
 	c.Assert(StringValue(Cadr(sexpr)), Equals, "a")
 }
 
+func (s *ParsingSuite) TestVector(c *C) {
+	sexpr, err := Parse("#(1 a (b))")
+	c.Assert(err, IsNil)
+	c.Assert(sexpr, NotNil)
+	c.Assert(int(TypeOf(sexpr)), Equals, VectorType)
+
+	elements := VectorValue(sexpr)
+	c.Assert(len(elements), Equals, 3)
+	c.Assert(IntegerValue(elements[0]), Equals, int64(1))
+	c.Assert(StringValue(elements[1]), Equals, "a")
+	c.Assert(String(elements[2]), Equals, "(b)")
+}
+
+func (s *ParsingSuite) TestEmptyVector(c *C) {
+	sexpr, err := Parse("#()")
+	c.Assert(err, IsNil)
+	c.Assert(int(TypeOf(sexpr)), Equals, VectorType)
+	c.Assert(len(VectorValue(sexpr)), Equals, 0)
+}
+
+func (s *ParsingSuite) TestCharacters(c *C) {
+	for src, expected := range map[string]rune{`#\a`: 'a', `#\space`: ' ', `#\x41`: 'A', `#\(`: '(', `#\x`: 'x'} {
+		sexpr, err := Parse(src)
+		c.Assert(err, IsNil)
+		c.Assert(int(TypeOf(sexpr)), Equals, CharacterType)
+		c.Assert(CharacterValue(sexpr), Equals, expected)
+	}
+}
+
+func (s *ParsingSuite) TestCharacterInList(c *C) {
+	sexpr, err := Parse(`(#\a #\))`)
+	c.Assert(err, IsNil)
+	c.Assert(Length(sexpr), Equals, 2)
+	c.Assert(CharacterValue(Cadr(sexpr)), Equals, ')')
+}
+
+func (s *ParsingSuite) TestUnknownCharacterName(c *C) {
+	_, err := Parse(`#\bogus`)
+	c.Assert(err, NotNil)
+}
+
 func (s *ParsingSuite) TestComment(c *C) {
 	sexpr, err := Parse("; comment\n42")
 	c.Assert(err, IsNil)
@@ -340,6 +421,62 @@ func (s *ParsingSuite) TestParseAndEval(c *C) {
 	c.Assert(IntegerValue(result), Equals, int64(25))
 }
 
+func (s *ParsingSuite) TestListLocations(c *C) {
+	sexpr, err := Parse("(a\n (b c)\n 'd)")
+	c.Assert(err, IsNil)
+	c.Assert(SourceLocationOf(sexpr).String(), Equals, "1:1")
+	c.Assert(SourceLocationOf(Cadr(sexpr)).String(), Equals, "2:2")
+	c.Assert(SourceLocationOf(Caddr(sexpr)).String(), Equals, "3:2")
+}
+
+func (s *ParsingSuite) TestEvalErrorLocations(c *C) {
+	_, err := ParseAndEval("(begin\n  (car 1 2))")
+	c.Assert(err, NotNil)
+	c.Assert(InnermostSourceLocation(err).String(), Equals, "2:3")
+	var evalErr *EvalError
+	c.Assert(errors.As(err, &evalErr), Equals, true)
+	c.Assert(String(evalErr.Expr), Equals, "(begin (car 1 2))")
+}
+
+func (s *ParsingSuite) TestLoadErrorLocations(c *C) {
+	f, err := ioutil.TempFile("", "config*.lsp")
+	c.Assert(err, IsNil)
+	defer os.Remove(f.Name())
+	f.WriteString("(define x 1)\n\n(define (f y)\n  (+ y\n     (car 5 6)))\n\n(f 2)\n")
+	f.Close()
+
+	_, err = ProcessFile(f.Name())
+	c.Assert(err, NotNil)
+	c.Assert(strings.HasPrefix(err.Error(), f.Name()+":5:6: Wrong number of args to car"), Equals, true)
+	c.Assert(strings.Count(err.Error(), "Wrong number of args to car"), Equals, 1)
+}
+
+func (s *ParsingSuite) TestNestedLoadParseErrorLocations(c *C) {
+	bad, err := ioutil.TempFile("", "bad*.lsp")
+	c.Assert(err, IsNil)
+	defer os.Remove(bad.Name())
+	bad.WriteString("(define (f x)\n  (+ x 1)")
+	bad.Close()
+
+	loader, err := ioutil.TempFile("", "ld*.lsp")
+	c.Assert(err, IsNil)
+	defer os.Remove(loader.Name())
+	fmt.Fprintf(loader, "(define y 1)\n(load %q)\n", bad.Name())
+	loader.Close()
+
+	_, err = ProcessFile(loader.Name())
+	c.Assert(err, NotNil)
+	c.Assert(InnermostSourceLocation(err).File, Equals, bad.Name())
+	c.Assert(strings.HasPrefix(err.Error(), bad.Name()+":2:9: Unexpected EOF"), Equals, true)
+	c.Assert(strings.Count(err.Error(), "Unexpected EOF"), Equals, 1)
+	c.Assert(strings.Contains(err.Error(), loader.Name()+":2:1: Evaling (load"), Equals, true)
+
+	// Wrapping the error in another located error leaves it intact.
+	var inner *LocatedError
+	c.Assert(errors.As(errors.Unwrap(err), &inner), Equals, true)
+	c.Assert(strings.HasPrefix(inner.Error(), bad.Name()+":2:9: Unexpected EOF"), Equals, true)
+}
+
 func (s *ParsingSuite) BenchmarkParse(c *C) {
 	c.ResetTimer()
 	for i := 0; i < c.N; i++ {
@@ -0,0 +1,362 @@
+// Copyright 2014 SteelSeries ApS.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// This package implements a basic LISP interpretor for embedding in a go program for scripting.
+// This file implements ports.
+
+package golisp
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/SteelSeries/bufrr"
+)
+
+// A port reads from an io.Reader, writes to an io.Writer, or both. Output
+// ports made by open-output-string collect what is written in Output.
+type Port struct {
+	Name        string
+	Writer      io.Writer
+	Closer      io.Closer
+	Output      *bytes.Buffer
+	Closed      bool
+	Interactive bool
+	Mutex       sync.Mutex
+	reader      *portReader
+}
+
+// Reads runes, and bytes, from a port's input. Runes that are read and then
+// given back keep their original bytes, so that reading characters, lines,
+// bytes and expressions from the same port can be mixed freely.
+type portReader struct {
+	source   *bufio.Reader
+	pushback [][]byte
+	last     []byte
+	previous []byte
+}
+
+func newPortReader(r io.Reader) *portReader {
+	return &portReader{source: bufio.NewReader(r)}
+}
+
+// Returns bufrr.EOF at the end of the input, as the tokenizer expects.
+func (self *portReader) ReadRune() (r rune, size int, err error) {
+	var raw []byte
+	if n := len(self.pushback); n > 0 {
+		raw = self.pushback[n-1]
+		self.pushback = self.pushback[:n-1]
+	} else {
+		buffer, peekErr := self.source.Peek(utf8.UTFMax)
+		if len(buffer) == 0 {
+			if peekErr == io.EOF {
+				return bufrr.EOF, 0, nil
+			}
+			return 0, 0, peekErr
+		}
+		_, size = utf8.DecodeRune(buffer)
+		raw = make([]byte, size)
+		copy(raw, buffer)
+		self.source.Discard(size)
+	}
+	self.previous, self.last = self.last, raw
+	r, size = utf8.DecodeRune(raw)
+	return
+}
+
+// Gives back the most recently read rune. It can be called twice in a row,
+// which the tokenizer's lookahead needs.
+func (self *portReader) UnreadRune() error {
+	if self.last == nil {
+		return bufrr.ErrInvalidUnreadRune
+	}
+	self.pushback = append(self.pushback, self.last)
+	self.last, self.previous = self.previous, nil
+	return nil
+}
+
+func (self *portReader) PeekRune() (r rune, size int, err error) {
+	r, size, err = self.ReadRune()
+	if err == nil && r != bufrr.EOF {
+		self.UnreadRune()
+	}
+	return
+}
+
+func (self *portReader) Read(b []byte) (n int, err error) {
+	self.last, self.previous = nil, nil
+	for n < len(b) && len(self.pushback) > 0 {
+		last := len(self.pushback) - 1
+		copied := copy(b[n:], self.pushback[last])
+		n += copied
+		if copied < len(self.pushback[last]) {
+			self.pushback[last] = self.pushback[last][copied:]
+		} else {
+			self.pushback = self.pushback[:last]
+		}
+	}
+	if n > 0 {
+		return
+	}
+	return self.source.Read(b)
+}
+
+// Whether input can be read without blocking.
+func (self *portReader) Ready() bool {
+	return len(self.pushback) > 0 || self.source.Buffered() > 0
+}
+
+// Writes to stdout, dropping the output when there is no stdout, as happens
+// with LDFLAGS="-H windowsgui".
+type stdoutWriter struct{}
+
+func (stdoutWriter) Write(b []byte) (n int, err error) {
+	stat, statErr := os.Stdout.Stat()
+	if stat == nil || statErr != nil {
+		return len(b), nil
+	}
+	return os.Stdout.Write(b)
+}
+
+var (
+	stdinPort         = PortWithPort(&Port{Name: os.Stdin.Name(), Interactive: true, reader: newPortReader(os.Stdin)})
+	stdoutPort        = PortWithPort(&Port{Name: os.Stdout.Name(), Writer: stdoutWriter{}})
+	currentPortMutex  sync.RWMutex
+	defaultInputPort  = stdinPort
+	defaultOutputPort = stdoutPort
+)
+
+func NewPort(name string, r io.Reader, w io.Writer) *Port {
+	port := &Port{Name: name, Writer: w}
+	if r != nil {
+		port.reader = newPortReader(r)
+	}
+	if closer, ok := r.(io.Closer); ok {
+		port.Closer = closer
+	} else if closer, ok := w.(io.Closer); ok {
+		port.Closer = closer
+	}
+	return port
+}
+
+func PortWithReader(name string, r io.Reader) *Data {
+	return PortWithPort(NewPort(name, r, nil))
+}
+
+func PortWithWriter(name string, w io.Writer) *Data {
+	return PortWithPort(NewPort(name, nil, w))
+}
+
+func PortWithReadWriter(name string, rw io.ReadWriter) *Data {
+	return PortWithPort(NewPort(name, rw, rw))
+}
+
+// An output port that collects what is written to it.
+func StringOutputPort() *Data {
+	buffer := &bytes.Buffer{}
+	port := NewPort("string", nil, buffer)
+	port.Output = buffer
+	return PortWithPort(port)
+}
+
+// The current ports are dynamic state: with-output-to-string and the like
+// only redirect output for the evaluation in their extent, not for other
+// goroutines. Outside of them the default ports are used.
+
+func CurrentOutputPort(env *SymbolTableFrame) *Data {
+	return currentPort(env, true)
+}
+
+func CurrentInputPort(env *SymbolTableFrame) *Data {
+	return currentPort(env, false)
+}
+
+// Makes port the default output port for every goroutine, returning the one
+// it replaces.
+func SetDefaultOutputPort(port *Data) (previous *Data) {
+	currentPortMutex.Lock()
+	defer currentPortMutex.Unlock()
+	previous = defaultOutputPort
+	defaultOutputPort = port
+	return
+}
+
+// Makes port the default input port for every goroutine, returning the one
+// it replaces.
+func SetDefaultInputPort(port *Data) (previous *Data) {
+	currentPortMutex.Lock()
+	defer currentPortMutex.Unlock()
+	previous = defaultInputPort
+	defaultInputPort = port
+	return
+}
+
+func (self *Port) IsInput() bool {
+	return self.reader != nil
+}
+
+func (self *Port) IsOutput() bool {
+	return self.Writer != nil
+}
+
+func (self *Port) Write(b []byte) (n int, err error) {
+	self.Mutex.Lock()
+	defer self.Mutex.Unlock()
+	if self.Closed {
+		return 0, fmt.Errorf("port %s is closed", self.Name)
+	}
+	if self.Writer == nil {
+		return 0, fmt.Errorf("port %s is not an output port", self.Name)
+	}
+	return self.Writer.Write(b)
+}
+
+func (self *Port) WriteString(s string) (n int, err error) {
+	return self.Write([]byte(s))
+}
+
+func (self *Port) checkInput() error {
+	if self.Closed {
+		return fmt.Errorf("port %s is closed", self.Name)
+	}
+	if self.reader == nil {
+		return fmt.Errorf("port %s is not an input port", self.Name)
+	}
+	return nil
+}
+
+// Reads the next object, which is EofObject at the end of the input. The
+// input following the object is left unread.
+func (self *Port) ReadObject() (result *Data, err error) {
+	self.Mutex.Lock()
+	defer self.Mutex.Unlock()
+	if err = self.checkInput(); err != nil {
+		return
+	}
+
+	tokenizer := newLazyTokenizer(self.reader, self.Name)
+	result, eof, err := parseExpression(tokenizer)
+	if !tokenizer.Eof {
+		// The tokenizer has read one character past the object.
+		self.reader.UnreadRune()
+	}
+	if err != nil {
+		return
+	}
+	if eof {
+		result = EofObject
+	}
+	return
+}
+
+// Reads the next character, returning eof true at the end of the input.
+// The character is left unread when peek is true.
+func (self *Port) ReadChar(peek bool) (ch rune, eof bool, err error) {
+	self.Mutex.Lock()
+	defer self.Mutex.Unlock()
+	if err = self.checkInput(); err != nil {
+		return
+	}
+
+	if peek {
+		ch, _, err = self.reader.PeekRune()
+	} else {
+		ch, _, err = self.reader.ReadRune()
+	}
+	eof = err == nil && ch == bufrr.EOF
+	return
+}
+
+// Reads up to k characters, stopping after a newline when line is true. The
+// newline isn't included in the result. eof is true when nothing was left to
+// read.
+func (self *Port) ReadChars(k int, line bool) (s string, eof bool, err error) {
+	self.Mutex.Lock()
+	defer self.Mutex.Unlock()
+	if err = self.checkInput(); err != nil {
+		return
+	}
+
+	var buffer bytes.Buffer
+	read := 0
+	for k < 0 || read < k {
+		var ch rune
+		ch, _, err = self.reader.ReadRune()
+		if err != nil {
+			return
+		}
+		if ch == bufrr.EOF {
+			eof = read == 0
+			break
+		}
+		read++
+		if line && ch == '\n' {
+			break
+		}
+		buffer.WriteRune(ch)
+	}
+	s = buffer.String()
+	if line && len(s) > 0 && s[len(s)-1] == '\r' {
+		s = s[:len(s)-1]
+	}
+	return
+}
+
+// Reads up to n bytes. eof is true when nothing was left to read.
+func (self *Port) ReadBytes(n int) (b []byte, eof bool, err error) {
+	self.Mutex.Lock()
+	defer self.Mutex.Unlock()
+	if err = self.checkInput(); err != nil {
+		return
+	}
+
+	b = make([]byte, n)
+	read, err := io.ReadFull(self.reader, b)
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		err = nil
+		eof = read == 0 && n > 0
+	}
+	b = b[:read]
+	return
+}
+
+// Whether a character can be read without blocking. Only interactive ports,
+// like stdin, can block.
+func (self *Port) CharReady() (ready bool, err error) {
+	self.Mutex.Lock()
+	defer self.Mutex.Unlock()
+	if err = self.checkInput(); err != nil {
+		return
+	}
+	return !self.Interactive || self.reader.Ready(), nil
+}
+
+// What has been written to a port made by open-output-string.
+func (self *Port) OutputString() (s string, err error) {
+	self.Mutex.Lock()
+	defer self.Mutex.Unlock()
+	if self.Output == nil {
+		return "", errors.New("not a string output port")
+	}
+	return self.Output.String(), nil
+}
+
+func (self *Port) Close() (err error) {
+	self.Mutex.Lock()
+	defer self.Mutex.Unlock()
+	if self.Closed {
+		return
+	}
+	self.Closed = true
+	if self.Closer != nil {
+		err = self.Closer.Close()
+	}
+	return
+}
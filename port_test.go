@@ -0,0 +1,106 @@
+// Copyright 2014 SteelSeries ApS.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// This package implements a basic LISP interpretor for embedding in a go program for scripting.
+// This file tests ports.
+
+package golisp
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	. "gopkg.in/check.v1"
+)
+
+type PortSuite struct{}
+
+var _ = Suite(&PortSuite{})
+
+func (s *PortSuite) TestWriterPort(c *C) {
+	buffer := &bytes.Buffer{}
+	Global.BindTo(Intern("test-writer-port"), PortWithWriter("buffer", buffer))
+	_, err := ParseAndEvalAll(`(write-string "a" test-writer-port)
+(write '(1 "b") test-writer-port)
+(newline test-writer-port)
+(format test-writer-port "~A-~D" 'x 42)`)
+	c.Assert(err, IsNil)
+	c.Assert(buffer.String(), Equals, "a(1 \"b\")\nx-42")
+}
+
+func (s *PortSuite) TestReaderPort(c *C) {
+	Global.BindTo(Intern("test-reader-port"), PortWithReader("input", strings.NewReader("(a b) 42 \"c\"")))
+	result, err := ParseAndEval(`(list (read test-reader-port) (read test-reader-port) (read test-reader-port) (eof-object? (read test-reader-port)))`)
+	c.Assert(err, IsNil)
+	c.Assert(String(result), Equals, "((a b) 42 \"c\" #t)")
+}
+
+func (s *PortSuite) TestWrongDirection(c *C) {
+	port := PortValue(PortWithReader("input", strings.NewReader("")))
+	c.Assert(port.IsInput(), Equals, true)
+	c.Assert(port.IsOutput(), Equals, false)
+	_, err := port.WriteString("a")
+	c.Assert(err, NotNil)
+}
+
+func (s *PortSuite) TestStringOutputPort(c *C) {
+	port := StringOutputPort()
+	previous := SetDefaultOutputPort(port)
+	_, err := ParseAndEvalAll(`(write-string "a") (format #t "~A" 1)`)
+	SetDefaultOutputPort(previous)
+	c.Assert(err, IsNil)
+	str, err := PortValue(port).OutputString()
+	c.Assert(err, IsNil)
+	c.Assert(str, Equals, "a1")
+}
+
+func (s *PortSuite) TestCurrentOutputPortIsPerGoroutine(c *C) {
+	code := `(with-output-to-string (lambda () (do ((i 0 (+ i 1))) ((== i 200)) (write-string "%s"))))`
+	results := make(chan string, 2)
+	for _, s := range []string{"a", "b"} {
+		go func(s string) {
+			result, err := ParseAndEval(fmt.Sprintf(code, s))
+			if err != nil {
+				results <- err.Error()
+				return
+			}
+			results <- StringValue(result)
+		}(s)
+	}
+	outputs := []string{<-results, <-results}
+	sort.Strings(outputs)
+	c.Assert(outputs, DeepEquals, []string{strings.Repeat("a", 200), strings.Repeat("b", 200)})
+}
+
+func (s *PortSuite) TestClose(c *C) {
+	port := PortValue(PortWithWriter("buffer", &bytes.Buffer{}))
+	c.Assert(port.Close(), IsNil)
+	_, err := port.WriteString("a")
+	c.Assert(err, NotNil)
+}
+
+func (s *PortSuite) TestReadLine(c *C) {
+	port := PortValue(PortWithReader("input", strings.NewReader("a\r\nb")))
+	line, eof, err := port.ReadChars(-1, true)
+	c.Assert(err, IsNil)
+	c.Assert(eof, Equals, false)
+	c.Assert(line, Equals, "a")
+	line, _, _ = port.ReadChars(-1, true)
+	c.Assert(line, Equals, "b")
+	_, eof, _ = port.ReadChars(-1, true)
+	c.Assert(eof, Equals, true)
+}
+
+func (s *PortSuite) TestReadBytesKeepsInvalidUtf8(c *C) {
+	port := PortValue(PortWithReader("input", bytes.NewReader([]byte{'x', ' ', 0xff, 0xfe})))
+	obj, err := port.ReadObject()
+	c.Assert(err, IsNil)
+	c.Assert(String(obj), Equals, "x")
+	b, eof, err := port.ReadBytes(5)
+	c.Assert(err, IsNil)
+	c.Assert(eof, Equals, false)
+	c.Assert(b, DeepEquals, []byte{' ', 0xff, 0xfe})
+}
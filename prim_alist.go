@@ -46,14 +46,14 @@ func AconsImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 func PairlisImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	keys := Car(args)
 	if !PairP(keys) {
-		err = ProcessError("First arg of pairlis must be a list", env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, "First arg of pairlis must be a list", env)
 		return
 	}
 
 	values := Cadr(args)
 
 	if !PairP(values) {
-		err = ProcessError("Second arg of Pairlis must be a list", env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, "Second arg of Pairlis must be a list", env)
 		return
 	}
 
@@ -66,7 +66,7 @@ func PairlisImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 
 	if NotNilP(result) {
 		if !PairP(result) {
-			err = ProcessError("Third arg of pairlis must be an association list (if provided)", env)
+			err = ProcessTypedError(WrongTypeArgumentCondition, "Third arg of pairlis must be an association list (if provided)", env)
 			return
 		}
 	}
@@ -96,7 +96,7 @@ func RassocImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	for c := list; NotNilP(c); c = Cdr(c) {
 		pair := Car(c)
 		if !PairP(pair) && !DottedPairP(pair) {
-			err = ProcessError("Assoc list must consist of dotted pairs", env)
+			err = ProcessTypedError(WrongTypeArgumentCondition, "Assoc list must consist of dotted pairs", env)
 			return
 		}
 		if IsEqual(Cdr(pair), value) {
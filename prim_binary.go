@@ -9,6 +9,8 @@ package golisp
 
 import (
 	"fmt"
+	"math/big"
+	"math/bits"
 )
 
 func RegisterBinaryPrimitives() {
@@ -17,19 +19,35 @@ func RegisterBinaryPrimitives() {
 	MakePrimitiveFunction("binary-not", "1", BinaryNotImpl)
 	MakePrimitiveFunction("left-shift", "2", LeftShiftImpl)
 	MakePrimitiveFunction("right-shift", "2", RightShiftImpl)
+	MakePrimitiveFunction("binary-xor", "2", BinaryXorImpl)
+
+	MakePrimitiveFunction("bitwise-and", "*", BitwiseAndImpl)
+	MakePrimitiveFunction("bitwise-or", "*", BitwiseOrImpl)
+	MakePrimitiveFunction("bitwise-xor", "*", BitwiseXorImpl)
+	MakePrimitiveFunction("bitwise-not", "1", BitwiseNotImpl)
+	MakePrimitiveFunction("arithmetic-shift", "2", ArithmeticShiftImpl)
+	MakePrimitiveFunction("bit-count", "1", BitCountImpl)
+	MakePrimitiveFunction("integer-length", "1", IntegerLengthImpl)
+	MakePrimitiveFunction("bit-set?", "2", BitSetPImpl)
+	MakePrimitiveFunction("set-bit", "2", SetBitImpl)
+	MakePrimitiveFunction("clear-bit", "2", ClearBitImpl)
+	MakePrimitiveFunction("bit-field", "3", BitFieldImpl)
+	MakePrimitiveFunction("bit-field-insert", "4", BitFieldInsertImpl)
+	MakePrimitiveFunction("sign-extend", "2", SignExtendImpl)
+	MakePrimitiveFunction("zero-extend", "2", ZeroExtendImpl)
 }
 
 func BinaryAndImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	arg1 := First(args)
 	if !IntegerP(arg1) {
-		err = ProcessError(fmt.Sprintf("Integer expected, received %s %s", TypeName(TypeOf(arg1)), String(arg1)), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("Integer expected, received %s %s", TypeName(TypeOf(arg1)), String(arg1)), env)
 		return
 	}
 	b1 := uint64(IntegerValue(arg1))
 
 	arg2 := Second(args)
 	if !IntegerP(arg2) {
-		err = ProcessError(fmt.Sprintf("Integer expected, received %s %s", TypeName(TypeOf(arg2)), String(arg2)), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("Integer expected, received %s %s", TypeName(TypeOf(arg2)), String(arg2)), env)
 		return
 	}
 	b2 := uint64(IntegerValue(arg2))
@@ -40,14 +58,14 @@ func BinaryAndImpl(args *Data, env *SymbolTableFrame) (result *Data, err error)
 func BinaryOrImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	arg1 := First(args)
 	if !IntegerP(arg1) {
-		err = ProcessError(fmt.Sprintf("Integer expected, received %s %s", TypeName(TypeOf(arg1)), String(arg1)), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("Integer expected, received %s %s", TypeName(TypeOf(arg1)), String(arg1)), env)
 		return
 	}
 	b1 := uint64(IntegerValue(arg1))
 
 	arg2 := Second(args)
 	if !IntegerP(arg2) {
-		err = ProcessError(fmt.Sprintf("Integer expected, received %s %s", TypeName(TypeOf(arg2)), String(arg2)), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("Integer expected, received %s %s", TypeName(TypeOf(arg2)), String(arg2)), env)
 		return
 	}
 	b2 := uint64(IntegerValue(arg2))
@@ -58,7 +76,7 @@ func BinaryOrImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 func BinaryNotImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	arg1 := First(args)
 	if !IntegerP(arg1) {
-		err = ProcessError(fmt.Sprintf("Integer expected, received %s %s", TypeName(TypeOf(arg1)), String(arg1)), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("Integer expected, received %s %s", TypeName(TypeOf(arg1)), String(arg1)), env)
 		return
 	}
 	b1 := uint64(IntegerValue(arg1))
@@ -66,17 +84,35 @@ func BinaryNotImpl(args *Data, env *SymbolTableFrame) (result *Data, err error)
 	return IntegerWithValue(int64(b1 ^ uint64(0xFFFFFFFF))), nil
 }
 
+func BinaryXorImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	arg1 := First(args)
+	if !IntegerP(arg1) {
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("Integer expected, received %s %s", TypeName(TypeOf(arg1)), String(arg1)), env)
+		return
+	}
+	b1 := uint64(IntegerValue(arg1))
+
+	arg2 := Second(args)
+	if !IntegerP(arg2) {
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("Integer expected, received %s %s", TypeName(TypeOf(arg2)), String(arg2)), env)
+		return
+	}
+	b2 := uint64(IntegerValue(arg2))
+
+	return IntegerWithValue(int64(b1 ^ b2)), nil
+}
+
 func LeftShiftImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	arg1 := First(args)
 	if !IntegerP(arg1) {
-		err = ProcessError(fmt.Sprintf("Integer expected, received %s %s", TypeName(TypeOf(arg1)), String(arg1)), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("Integer expected, received %s %s", TypeName(TypeOf(arg1)), String(arg1)), env)
 		return
 	}
 	b1 := uint64(IntegerValue(arg1))
 
 	arg2 := Second(args)
 	if !IntegerP(arg2) {
-		err = ProcessError(fmt.Sprintf("Integer expected, received %s %s", TypeName(TypeOf(arg2)), String(arg2)), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("Integer expected, received %s %s", TypeName(TypeOf(arg2)), String(arg2)), env)
 		return
 	}
 	b2 := uint64(IntegerValue(arg2))
@@ -87,17 +123,263 @@ func LeftShiftImpl(args *Data, env *SymbolTableFrame) (result *Data, err error)
 func RightShiftImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	arg1 := First(args)
 	if !IntegerP(arg1) {
-		err = ProcessError(fmt.Sprintf("Integer expected, received %s %s", TypeName(TypeOf(arg1)), String(arg1)), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("Integer expected, received %s %s", TypeName(TypeOf(arg1)), String(arg1)), env)
 		return
 	}
 	b1 := uint64(IntegerValue(arg1))
 
 	arg2 := Second(args)
 	if !IntegerP(arg2) {
-		err = ProcessError(fmt.Sprintf("Integer expected, received %s %s", TypeName(TypeOf(arg2)), String(arg2)), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("Integer expected, received %s %s", TypeName(TypeOf(arg2)), String(arg2)), env)
 		return
 	}
 	b2 := uint64(IntegerValue(arg2))
 
 	return IntegerWithValue(int64(b1 >> b2)), nil
 }
+
+// The bitwise primitives below work on exact integers of any size, treating
+// negative numbers as two's complement with infinitely many leading ones.
+
+func exactIntegerArg(name string, d *Data, env *SymbolTableFrame) (n *big.Int, err error) {
+	if !ExactIntegerP(d) {
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("%s expected an integer, received %s %s", name, TypeName(TypeOf(d)), String(d)), env)
+		return
+	}
+	return bigIntValue(d), nil
+}
+
+// A bit position or a width in bits.
+func bitIndexArg(name string, d *Data, env *SymbolTableFrame) (index uint, err error) {
+	if !IntegerP(d) {
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("%s expected an integer bit index, received %s %s", name, TypeName(TypeOf(d)), String(d)), env)
+		return
+	}
+	if IntegerValue(d) < 0 || IntegerValue(d) > 1<<24 {
+		err = ProcessTypedError(BadRangeArgumentCondition, fmt.Sprintf("%s expected a bit index between 0 and %d, received %d", name, 1<<24, IntegerValue(d)), env)
+		return
+	}
+	return uint(IntegerValue(d)), nil
+}
+
+func bitwiseFold(name string, args *Data, initial int64, op func(z, x, y *big.Int) *big.Int, env *SymbolTableFrame) (result *Data, err error) {
+	accumulator := big.NewInt(initial)
+	for c := args; NotNilP(c); c = Cdr(c) {
+		var n *big.Int
+		n, err = exactIntegerArg(name, Car(c), env)
+		if err != nil {
+			return
+		}
+		op(accumulator, accumulator, n)
+	}
+	return ExactIntegerWithValue(accumulator), nil
+}
+
+func BitwiseAndImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	return bitwiseFold("bitwise-and", args, -1, (*big.Int).And, env)
+}
+
+func BitwiseOrImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	return bitwiseFold("bitwise-or", args, 0, (*big.Int).Or, env)
+}
+
+func BitwiseXorImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	return bitwiseFold("bitwise-xor", args, 0, (*big.Int).Xor, env)
+}
+
+func BitwiseNotImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	n, err := exactIntegerArg("bitwise-not", First(args), env)
+	if err != nil {
+		return
+	}
+	return ExactIntegerWithValue(new(big.Int).Not(n)), nil
+}
+
+// Shifts left for a positive count and right, rounding toward negative
+// infinity, for a negative one.
+func ArithmeticShiftImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	n, err := exactIntegerArg("arithmetic-shift", First(args), env)
+	if err != nil {
+		return
+	}
+	countObj := Second(args)
+	if !IntegerP(countObj) {
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("arithmetic-shift expected an integer count, received %s %s", TypeName(TypeOf(countObj)), String(countObj)), env)
+		return
+	}
+
+	count := IntegerValue(countObj)
+	if count >= 0 {
+		var shift uint
+		shift, err = bitIndexArg("arithmetic-shift", countObj, env)
+		if err != nil {
+			return
+		}
+		return ExactIntegerWithValue(new(big.Int).Lsh(n, shift)), nil
+	}
+	if count < -int64(n.BitLen()) {
+		if n.Sign() < 0 {
+			return IntegerWithValue(-1), nil
+		}
+		return IntegerWithValue(0), nil
+	}
+	return ExactIntegerWithValue(new(big.Int).Rsh(n, uint(-count))), nil
+}
+
+// The number of one bits in a non-negative integer, or of zero bits in a
+// negative one.
+func BitCountImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	n, err := exactIntegerArg("bit-count", First(args), env)
+	if err != nil {
+		return
+	}
+	if n.Sign() < 0 {
+		n = new(big.Int).Not(n)
+	}
+	count := 0
+	for _, word := range n.Bits() {
+		count += bits.OnesCount(uint(word))
+	}
+	return IntegerWithValue(int64(count)), nil
+}
+
+// The number of bits needed to represent the integer, not counting the sign.
+func IntegerLengthImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	n, err := exactIntegerArg("integer-length", First(args), env)
+	if err != nil {
+		return
+	}
+	if n.Sign() < 0 {
+		n = new(big.Int).Not(n)
+	}
+	return IntegerWithValue(int64(n.BitLen())), nil
+}
+
+func BitSetPImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	n, err := exactIntegerArg("bit-set?", First(args), env)
+	if err != nil {
+		return
+	}
+	index, err := bitIndexArg("bit-set?", Second(args), env)
+	if err != nil {
+		return
+	}
+	return BooleanWithValue(n.Bit(int(index)) == 1), nil
+}
+
+func changeBit(name string, args *Data, bit uint, env *SymbolTableFrame) (result *Data, err error) {
+	n, err := exactIntegerArg(name, First(args), env)
+	if err != nil {
+		return
+	}
+	index, err := bitIndexArg(name, Second(args), env)
+	if err != nil {
+		return
+	}
+	return ExactIntegerWithValue(new(big.Int).SetBit(n, int(index), bit)), nil
+}
+
+func SetBitImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	return changeBit("set-bit", args, 1, env)
+}
+
+func ClearBitImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	return changeBit("clear-bit", args, 0, env)
+}
+
+// A mask of the bits from start up to, but not including, end.
+func bitFieldMask(start uint, end uint) *big.Int {
+	mask := new(big.Int).Lsh(big.NewInt(1), end-start)
+	mask.Sub(mask, big.NewInt(1))
+	return mask.Lsh(mask, start)
+}
+
+func bitFieldRangeArgs(name string, startObj *Data, endObj *Data, env *SymbolTableFrame) (start uint, end uint, err error) {
+	start, err = bitIndexArg(name, startObj, env)
+	if err != nil {
+		return
+	}
+	end, err = bitIndexArg(name, endObj, env)
+	if err != nil {
+		return
+	}
+	if start > end {
+		err = ProcessTypedError(BadRangeArgumentCondition, fmt.Sprintf("%s expected start <= end, received %d and %d", name, start, end), env)
+	}
+	return
+}
+
+// (bit-field n start end) is the unsigned value of the bits of n from start
+// up to, but not including, end.
+func BitFieldImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	n, err := exactIntegerArg("bit-field", First(args), env)
+	if err != nil {
+		return
+	}
+	start, end, err := bitFieldRangeArgs("bit-field", Second(args), Third(args), env)
+	if err != nil {
+		return
+	}
+
+	field := new(big.Int).And(n, bitFieldMask(start, end))
+	return ExactIntegerWithValue(field.Rsh(field, start)), nil
+}
+
+// (bit-field-insert n value start end) replaces the bits of n from start up
+// to end with the low bits of value.
+func BitFieldInsertImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	n, err := exactIntegerArg("bit-field-insert", First(args), env)
+	if err != nil {
+		return
+	}
+	value, err := exactIntegerArg("bit-field-insert", Second(args), env)
+	if err != nil {
+		return
+	}
+	start, end, err := bitFieldRangeArgs("bit-field-insert", Third(args), Nth(args, 4), env)
+	if err != nil {
+		return
+	}
+
+	mask := bitFieldMask(start, end)
+	field := new(big.Int).Lsh(value, start)
+	field.And(field, mask)
+	inserted := new(big.Int).AndNot(n, mask)
+	return ExactIntegerWithValue(inserted.Or(inserted, field)), nil
+}
+
+// (sign-extend n width) reads the low width bits of n as a two's complement
+// number.
+func SignExtendImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	n, err := exactIntegerArg("sign-extend", First(args), env)
+	if err != nil {
+		return
+	}
+	width, err := bitIndexArg("sign-extend", Second(args), env)
+	if err != nil {
+		return
+	}
+	if width == 0 {
+		return IntegerWithValue(0), nil
+	}
+
+	field := new(big.Int).And(n, bitFieldMask(0, width))
+	if field.Bit(int(width-1)) == 1 {
+		field.Sub(field, new(big.Int).Lsh(big.NewInt(1), width))
+	}
+	return ExactIntegerWithValue(field), nil
+}
+
+// (zero-extend n width) reads the low width bits of n as an unsigned number,
+// which turns a negative number into its width bit two's complement encoding.
+func ZeroExtendImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	n, err := exactIntegerArg("zero-extend", First(args), env)
+	if err != nil {
+		return
+	}
+	width, err := bitIndexArg("zero-extend", Second(args), env)
+	if err != nil {
+		return
+	}
+	return ExactIntegerWithValue(new(big.Int).And(n, bitFieldMask(0, width))), nil
+}
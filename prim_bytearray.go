@@ -8,6 +8,8 @@
 package golisp
 
 import (
+	"bytes"
+	"encoding/binary"
 	"fmt"
 	"unsafe"
 )
@@ -23,6 +25,388 @@ func RegisterBytearrayPrimitives() {
 	MakePrimitiveFunction("append-bytes", "*", AppendBytesImpl)
 	MakePrimitiveFunction("append-bytes!", "*", AppendBytesBangImpl)
 	MakePrimitiveFunction("extract-bytes", "3", ExtractBytesImpl)
+	MakePrimitiveFunction("make-bytearray", "1|2", MakeBytearrayImpl)
+	MakePrimitiveFunction("bytearray-length", "1", BytearrayLengthImpl)
+	MakePrimitiveFunction("bytearray-index-of", "2|3", BytearrayIndexOfImpl)
+	MakePrimitiveFunction("bytearray-compare", "2", BytearrayCompareImpl)
+	MakePrimitiveFunction("bytearray-fill!", "2|3|4", BytearrayFillImpl)
+	MakePrimitiveFunction("bytearray-copy!", "3|4|5", BytearrayCopyImpl)
+	MakePrimitiveFunction("bytearray-slice", "2|3", BytearraySliceImpl)
+
+	// bytearray-u16-ref, bytearray-u16-be-ref, bytearray-u16-set! and so on.
+	// The unsuffixed versions are little-endian unless given 'big.
+	for _, field := range binaryFields {
+		registerBinaryFieldPrimitives(field)
+	}
+	MakePrimitiveFunction("pack", "2", PackImpl)
+	MakePrimitiveFunction("unpack", "2|3", UnpackImpl)
+	MakePrimitiveFunction("pack-size", "1", PackSizeImpl)
+}
+
+func bytearrayWithValue(b []byte) *Data {
+	return ObjectWithTypeAndValue("[]byte", unsafe.Pointer(&b))
+}
+
+func bytearrayArg(name string, d *Data, env *SymbolTableFrame) (b []byte, err error) {
+	if !ObjectP(d) || ObjectType(d) != "[]byte" {
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("%s expects a bytearray but received %s.", name, String(d)), env)
+		return
+	}
+	return *(*[]byte)(ObjectValue(d)), nil
+}
+
+// An optional position in a bytearray of the given length, which is
+// defaultValue when the argument at index isn't there.
+func bytearrayPositionArg(name string, args *Data, index int, length int, defaultValue int, env *SymbolTableFrame) (position int, err error) {
+	if Length(args) <= index {
+		return defaultValue, nil
+	}
+	positionObject := Nth(args, index+1)
+	if !IntegerP(positionObject) {
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("%s expects an integer position but received %s.", name, String(positionObject)), env)
+		return
+	}
+	if IntegerValue(positionObject) < 0 || IntegerValue(positionObject) > int64(length) {
+		err = ProcessTypedError(BadRangeArgumentCondition, fmt.Sprintf("%s position was out of range. Was %d but bytearray has length of %d.", name, IntegerValue(positionObject), length), env)
+		return
+	}
+	return int(IntegerValue(positionObject)), nil
+}
+
+func byteArg(name string, d *Data, env *SymbolTableFrame) (b byte, err error) {
+	if !IntegerP(d) || IntegerValue(d) < 0 || IntegerValue(d) > 255 {
+		err = ProcessTypedError(BadRangeArgumentCondition, fmt.Sprintf("%s expects a byte but received %s.", name, String(d)), env)
+		return
+	}
+	return byte(IntegerValue(d)), nil
+}
+
+func registerBinaryFieldPrimitives(field binaryField) {
+	ref := fmt.Sprintf("bytearray-%s-ref", field.Name)
+	set := fmt.Sprintf("bytearray-%s-set!", field.Name)
+	if field.Size == 1 {
+		MakePrimitiveFunction(ref, "2", func(args *Data, env *SymbolTableFrame) (*Data, error) {
+			return binaryFieldRef(ref, field, nil, args, env)
+		})
+		MakePrimitiveFunction(set, "3", func(args *Data, env *SymbolTableFrame) (*Data, error) {
+			return binaryFieldSet(set, field, nil, args, env)
+		})
+		return
+	}
+
+	MakePrimitiveFunction(ref, "2|3", func(args *Data, env *SymbolTableFrame) (*Data, error) {
+		return binaryFieldRef(ref, field, nil, args, env)
+	})
+	MakePrimitiveFunction(set, "3|4", func(args *Data, env *SymbolTableFrame) (*Data, error) {
+		return binaryFieldSet(set, field, nil, args, env)
+	})
+	for suffix, order := range map[string]binary.ByteOrder{"le": binary.LittleEndian, "be": binary.BigEndian} {
+		ref := fmt.Sprintf("bytearray-%s-%s-ref", field.Name, suffix)
+		set := fmt.Sprintf("bytearray-%s-%s-set!", field.Name, suffix)
+		order := order
+		MakePrimitiveFunction(ref, "2", func(args *Data, env *SymbolTableFrame) (*Data, error) {
+			return binaryFieldRef(ref, field, order, args, env)
+		})
+		MakePrimitiveFunction(set, "3", func(args *Data, env *SymbolTableFrame) (*Data, error) {
+			return binaryFieldSet(set, field, order, args, env)
+		})
+	}
+}
+
+// The bytes of the field at the index given as the second argument, and the
+// byte order, which is taken from the optional argument at orderIndex when
+// order is nil.
+func binaryFieldArgs(name string, field binaryField, order binary.ByteOrder, orderIndex int, args *Data, env *SymbolTableFrame) (dataBytes []byte, resultOrder binary.ByteOrder, err error) {
+	dataByteObject := First(args)
+	if !ObjectP(dataByteObject) || ObjectType(dataByteObject) != "[]byte" {
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("%s expects a bytearray as its first argument but received %s.", name, String(dataByteObject)), env)
+		return
+	}
+	allBytes := *(*[]byte)(ObjectValue(dataByteObject))
+
+	indexObject := Second(args)
+	if !IntegerP(indexObject) {
+		err = ProcessTypedError(WrongTypeArgumentCondition, "Bytearray index should be an integer.", env)
+		return
+	}
+	index := IntegerValue(indexObject)
+	if index < 0 || index > int64(len(allBytes))-int64(field.Size) {
+		err = ProcessTypedError(BadRangeArgumentCondition, fmt.Sprintf("%s index was out of range. Was %d but bytearray has length of %d.", name, index, len(allBytes)), env)
+		return
+	}
+	dataBytes = allBytes[index : index+int64(field.Size)]
+
+	resultOrder = order
+	if resultOrder == nil {
+		resultOrder = binary.LittleEndian
+		if Length(args) > orderIndex {
+			orderObject := Nth(args, orderIndex+1)
+			switch {
+			case SymbolP(orderObject) && StringValue(orderObject) == "big":
+				resultOrder = binary.BigEndian
+			case SymbolP(orderObject) && StringValue(orderObject) == "little":
+			default:
+				err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("%s expects the byte order to be 'big or 'little but received %s.", name, String(orderObject)), env)
+			}
+		}
+	}
+	return
+}
+
+func binaryFieldRef(name string, field binaryField, order binary.ByteOrder, args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	dataBytes, order, err := binaryFieldArgs(name, field, order, 2, args, env)
+	if err != nil {
+		return
+	}
+	return field.decode(dataBytes, order), nil
+}
+
+// Stores the value in place, returning the bytearray.
+func binaryFieldSet(name string, field binaryField, order binary.ByteOrder, args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	dataBytes, order, err := binaryFieldArgs(name, field, order, 3, args, env)
+	if err != nil {
+		return
+	}
+	encodeErr := field.encode(Third(args), dataBytes, order)
+	if encodeErr != nil {
+		err = ProcessTypedError(BadRangeArgumentCondition, fmt.Sprintf("%s: %s.", name, encodeErr), env)
+		return
+	}
+	return First(args), nil
+}
+
+func MakeBytearrayImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	n := First(args)
+	if !IntegerP(n) || IntegerValue(n) < 0 {
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("make-bytearray expects a non-negative length but received %s.", String(n)), env)
+		return
+	}
+
+	var fill byte
+	if Length(args) == 2 {
+		fill, err = byteArg("make-bytearray", Second(args), env)
+		if err != nil {
+			return
+		}
+	}
+
+	b := make([]byte, IntegerValue(n))
+	if fill != 0 {
+		for i := range b {
+			b[i] = fill
+		}
+	}
+	return bytearrayWithValue(b), nil
+}
+
+func BytearrayLengthImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	b, err := bytearrayArg("bytearray-length", First(args), env)
+	if err != nil {
+		return
+	}
+	return IntegerWithValue(int64(len(b))), nil
+}
+
+// The index of the first occurrence of a byte or of a bytearray, at or after
+// the optional start, or #f.
+func BytearrayIndexOfImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	b, err := bytearrayArg("bytearray-index-of", First(args), env)
+	if err != nil {
+		return
+	}
+	start, err := bytearrayPositionArg("bytearray-index-of", args, 2, len(b), 0, env)
+	if err != nil {
+		return
+	}
+
+	var index int
+	pattern := Second(args)
+	if IntegerP(pattern) {
+		var octet byte
+		octet, err = byteArg("bytearray-index-of", pattern, env)
+		if err != nil {
+			return
+		}
+		index = bytes.IndexByte(b[start:], octet)
+	} else {
+		var patternBytes []byte
+		patternBytes, err = bytearrayArg("bytearray-index-of", pattern, env)
+		if err != nil {
+			return
+		}
+		index = bytes.Index(b[start:], patternBytes)
+	}
+
+	if index < 0 {
+		return LispFalse, nil
+	}
+	return IntegerWithValue(int64(start + index)), nil
+}
+
+// Compares bytearrays lexicographically, returning -1, 0 or 1.
+func BytearrayCompareImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	a, err := bytearrayArg("bytearray-compare", First(args), env)
+	if err != nil {
+		return
+	}
+	b, err := bytearrayArg("bytearray-compare", Second(args), env)
+	if err != nil {
+		return
+	}
+	return IntegerWithValue(int64(bytes.Compare(a, b))), nil
+}
+
+// Fills the bytearray, or the part of it from start to end, with a byte.
+func BytearrayFillImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	b, err := bytearrayArg("bytearray-fill!", First(args), env)
+	if err != nil {
+		return
+	}
+	fill, err := byteArg("bytearray-fill!", Second(args), env)
+	if err != nil {
+		return
+	}
+	start, err := bytearrayPositionArg("bytearray-fill!", args, 2, len(b), 0, env)
+	if err != nil {
+		return
+	}
+	end, err := bytearrayPositionArg("bytearray-fill!", args, 3, len(b), len(b), env)
+	if err != nil {
+		return
+	}
+	if start > end {
+		err = ProcessTypedError(BadRangeArgumentCondition, "bytearray-fill! requires start <= end.", env)
+		return
+	}
+
+	for i := start; i < end; i++ {
+		b[i] = fill
+	}
+	return First(args), nil
+}
+
+// (bytearray-copy! to at from [start [end]]) copies the bytes of from, or the
+// part of it from start to end, into to at the given position. The ranges
+// may overlap.
+func BytearrayCopyImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	to, err := bytearrayArg("bytearray-copy!", First(args), env)
+	if err != nil {
+		return
+	}
+	at, err := bytearrayPositionArg("bytearray-copy!", args, 1, len(to), 0, env)
+	if err != nil {
+		return
+	}
+	from, err := bytearrayArg("bytearray-copy!", Third(args), env)
+	if err != nil {
+		return
+	}
+	start, err := bytearrayPositionArg("bytearray-copy!", args, 3, len(from), 0, env)
+	if err != nil {
+		return
+	}
+	end, err := bytearrayPositionArg("bytearray-copy!", args, 4, len(from), len(from), env)
+	if err != nil {
+		return
+	}
+	if start > end {
+		err = ProcessTypedError(BadRangeArgumentCondition, "bytearray-copy! requires start <= end.", env)
+		return
+	}
+	if end-start > len(to)-at {
+		err = ProcessTypedError(BadRangeArgumentCondition, fmt.Sprintf("bytearray-copy! can't copy %d bytes to position %d of a bytearray of length %d.", end-start, at, len(to)), env)
+		return
+	}
+
+	copy(to[at:], from[start:end])
+	return First(args), nil
+}
+
+// A view of part of a bytearray that shares its bytes, so that changes to
+// either are seen in both. Appending to the view doesn't affect the original.
+func BytearraySliceImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	b, err := bytearrayArg("bytearray-slice", First(args), env)
+	if err != nil {
+		return
+	}
+	start, err := bytearrayPositionArg("bytearray-slice", args, 1, len(b), 0, env)
+	if err != nil {
+		return
+	}
+	end, err := bytearrayPositionArg("bytearray-slice", args, 2, len(b), len(b), env)
+	if err != nil {
+		return
+	}
+	if start > end {
+		err = ProcessTypedError(BadRangeArgumentCondition, "bytearray-slice requires start <= end.", env)
+		return
+	}
+	return bytearrayWithValue(b[start:end:end]), nil
+}
+
+func PackImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	format := First(args)
+	if !StringP(format) {
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("pack expects a format string but received %s.", String(format)), env)
+		return
+	}
+	values := Second(args)
+	if !ListP(values) {
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("pack expects a list of values but received %s.", String(values)), env)
+		return
+	}
+
+	packed, packErr := Pack(StringValue(format), values)
+	if packErr != nil {
+		err = ProcessError(fmt.Sprintf("pack: %s.", packErr), env)
+		return
+	}
+	return ObjectWithTypeAndValue("[]byte", unsafe.Pointer(&packed)), nil
+}
+
+// Unpacks the bytes at the optional offset, returning a list of values.
+func UnpackImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	format := First(args)
+	if !StringP(format) {
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("unpack expects a format string but received %s.", String(format)), env)
+		return
+	}
+	dataByteObject := Second(args)
+	if !ObjectP(dataByteObject) || ObjectType(dataByteObject) != "[]byte" {
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("unpack expects a bytearray but received %s.", String(dataByteObject)), env)
+		return
+	}
+	dataBytes := *(*[]byte)(ObjectValue(dataByteObject))
+
+	if Length(args) == 3 {
+		offset := Third(args)
+		if !IntegerP(offset) || IntegerValue(offset) < 0 || IntegerValue(offset) > int64(len(dataBytes)) {
+			err = ProcessTypedError(BadRangeArgumentCondition, fmt.Sprintf("unpack offset was out of range. Was %s but bytearray has length of %d.", String(offset), len(dataBytes)), env)
+			return
+		}
+		dataBytes = dataBytes[IntegerValue(offset):]
+	}
+
+	result, unpackErr := Unpack(StringValue(format), dataBytes)
+	if unpackErr != nil {
+		err = ProcessError(fmt.Sprintf("unpack: %s.", unpackErr), env)
+	}
+	return
+}
+
+func PackSizeImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	format := First(args)
+	if !StringP(format) {
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("pack-size expects a format string but received %s.", String(format)), env)
+		return
+	}
+
+	items, _, parseErr := parsePackFormat(StringValue(format))
+	if parseErr != nil {
+		err = ProcessError(fmt.Sprintf("pack-size: %s.", parseErr), env)
+		return
+	}
+	return IntegerWithValue(int64(packSize(items))), nil
 }
 
 func ListToBytesImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
@@ -32,7 +416,7 @@ func ListToBytesImpl(args *Data, env *SymbolTableFrame) (result *Data, err error
 		return
 	}
 	if !ListP(list) {
-		err = ProcessError("Argument to list->bytes must be a list.", env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, "Argument to list->bytes must be a list.", env)
 		return
 	}
 
@@ -40,7 +424,7 @@ func ListToBytesImpl(args *Data, env *SymbolTableFrame) (result *Data, err error
 	for c := list; NotNilP(c); c = Cdr(c) {
 		n := Car(c)
 		if !IntegerP(n) && !(ObjectP(n) && ObjectType(n) == "[]byte") {
-			err = ProcessError(fmt.Sprintf("Byte arrays can only contain numbers, but found %v.", n), env)
+			err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("Byte arrays can only contain numbers, but found %v.", n), env)
 			return
 		}
 
@@ -48,7 +432,7 @@ func ListToBytesImpl(args *Data, env *SymbolTableFrame) (result *Data, err error
 			b := IntegerValue(n)
 			if b < 0 || b > 255 {
 
-				err = ProcessError(fmt.Sprintf("Byte arrays can only contain bytes, but found %d.", b), env)
+				err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("Byte arrays can only contain bytes, but found %d.", b), env)
 				return
 			}
 			bytes = append(bytes, byte(b))
@@ -81,7 +465,7 @@ func BytesToListImpl(args *Data, env *SymbolTableFrame) (result *Data, err error
 func internalReplaceByte(args *Data, env *SymbolTableFrame, makeCopy bool) (result *Data, err error) {
 	dataByteObject := First(args)
 	if !ObjectP(dataByteObject) || ObjectType(dataByteObject) != "[]byte" {
-		err = ProcessError(fmt.Sprintf("replace-byte expects a bytearray as it's first argument but received %s.", ObjectType(dataByteObject)), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("replace-byte expects a bytearray as it's first argument but received %s.", ObjectType(dataByteObject)), env)
 		return
 	}
 
@@ -97,29 +481,29 @@ func internalReplaceByte(args *Data, env *SymbolTableFrame, makeCopy bool) (resu
 
 	indexObject := Second(args)
 	if !IntegerP(indexObject) {
-		err = ProcessError("Bytearray index should be an integer.", env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, "Bytearray index should be an integer.", env)
 		return
 	}
 	index := int(IntegerValue(indexObject))
 
 	if index >= len(*dataBytes) {
-		err = ProcessError(fmt.Sprintf("replace-byte index was out of range. Was %d but bytearray has length of %d.", index, len(*dataBytes)), env)
+		err = ProcessTypedError(BadRangeArgumentCondition, fmt.Sprintf("replace-byte index was out of range. Was %d but bytearray has length of %d.", index, len(*dataBytes)), env)
 		return
 	}
 
 	if index < 0 {
-		err = ProcessError(fmt.Sprintf("replace-byte index was out of range: %d.", index), env)
+		err = ProcessTypedError(BadRangeArgumentCondition, fmt.Sprintf("replace-byte index was out of range: %d.", index), env)
 		return
 	}
 
 	valueObject := Third(args)
 	if !IntegerP(valueObject) {
-		err = ProcessError("Bytearray value should be an integer.", env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, "Bytearray value should be an integer.", env)
 		return
 	}
 
 	if IntegerValue(valueObject) < 0 || IntegerValue(valueObject) > 255 {
-		err = ProcessError(fmt.Sprintf("replace-byte value was not a byte. Was %d.", index), env)
+		err = ProcessTypedError(BadRangeArgumentCondition, fmt.Sprintf("replace-byte value was not a byte. Was %d.", index), env)
 		return
 	}
 
@@ -154,18 +538,18 @@ func ExtractByteImpl(args *Data, env *SymbolTableFrame) (result *Data, err error
 
 	indexObject := Cadr(args)
 	if !IntegerP(indexObject) {
-		err = ProcessError("Bytearray index should be a number.", env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, "Bytearray index should be a number.", env)
 		return
 	}
 	index := int(IntegerValue(indexObject))
 
 	if index >= len(*dataBytes) {
-		err = ProcessError(fmt.Sprintf("extract-byte index was out of range. Was %d but bytearray has length of %d.", index, len(*dataBytes)), env)
+		err = ProcessTypedError(BadRangeArgumentCondition, fmt.Sprintf("extract-byte index was out of range. Was %d but bytearray has length of %d.", index, len(*dataBytes)), env)
 		return
 	}
 
 	if index < 0 {
-		err = ProcessError(fmt.Sprintf("extract-byte index was out of range: %d.", index), env)
+		err = ProcessTypedError(BadRangeArgumentCondition, fmt.Sprintf("extract-byte index was out of range: %d.", index), env)
 		return
 	}
 
@@ -177,7 +561,7 @@ func ExtractByteImpl(args *Data, env *SymbolTableFrame) (result *Data, err error
 func internalAppendBytes(args *Data, env *SymbolTableFrame) (newBytes *[]byte, err error) {
 	dataByteObject := Car(args)
 	if !ObjectP(dataByteObject) || ObjectType(dataByteObject) != "[]byte" {
-		err = ProcessError(fmt.Sprintf("append-bytes extects first argument to be a bytearray, but was %s.", ObjectType(dataByteObject)), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("append-bytes extects first argument to be a bytearray, but was %s.", ObjectType(dataByteObject)), env)
 		return
 	}
 
@@ -243,27 +627,27 @@ func ExtractBytesImpl(args *Data, env *SymbolTableFrame) (result *Data, err erro
 
 	indexObject := Cadr(args)
 	if !IntegerP(indexObject) {
-		err = ProcessError("Bytearray index should be a number.", env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, "Bytearray index should be a number.", env)
 		return
 	}
 	index := int(IntegerValue(indexObject))
 	if index < 0 || index >= len(*dataBytes) {
-		err = ProcessError(fmt.Sprintf("extract-bytes index was out of range. Was %d but bytearray has length of %d.", index, len(*dataBytes)), env)
+		err = ProcessTypedError(BadRangeArgumentCondition, fmt.Sprintf("extract-bytes index was out of range. Was %d but bytearray has length of %d.", index, len(*dataBytes)), env)
 		return
 	}
 
 	numToExtractObject := Caddr(args)
 	if !IntegerP(numToExtractObject) {
-		err = ProcessError(fmt.Sprintf("Number to extract must be a number, but was %s.", TypeName(TypeOf(numToExtractObject))), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("Number to extract must be a number, but was %s.", TypeName(TypeOf(numToExtractObject))), env)
 		return
 	}
 	numToExtract := int(IntegerValue(numToExtractObject))
 	if numToExtract < 0 {
-		err = ProcessError("Number to extract can not be negative.", env)
+		err = ProcessTypedError(BadRangeArgumentCondition, "Number to extract can not be negative.", env)
 		return
 	}
 	if index+numToExtract > len(*dataBytes) {
-		err = ProcessError(fmt.Sprintf("extract-bytes final index was out of range.  Was %d but bytearray has length of %d.", index+numToExtract-1, len(*dataBytes)), env)
+		err = ProcessTypedError(BadRangeArgumentCondition, fmt.Sprintf("extract-bytes final index was out of range.  Was %d but bytearray has length of %d.", index+numToExtract-1, len(*dataBytes)), env)
 		return
 	}
 
@@ -29,21 +29,21 @@ func MakeChannelImpl(args *Data, env *SymbolTableFrame) (result *Data, err error
 	if Length(args) == 1 {
 		lengthObj := Car(args)
 		if !IntegerP(lengthObj) {
-			err = ProcessError(fmt.Sprintf("make-channel expects an Integer as its second argument but received %s.", TypeName(TypeOf(lengthObj))), env)
+			err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("make-channel expects an Integer as its second argument but received %s.", TypeName(TypeOf(lengthObj))), env)
 			return
 		}
 
 		channelLength := IntegerValue(lengthObj)
 
 		if channelLength < 0 {
-			err = ProcessError(fmt.Sprintf("channel size needs to be positive; got %d.", channelLength), env)
+			err = ProcessTypedError(BadRangeArgumentCondition, fmt.Sprintf("channel size needs to be positive; got %d.", channelLength), env)
 			return
 		}
 
 		const maxInt = int64(int(^uint(0) >> 1))
 
 		if channelLength > maxInt {
-			err = ProcessError(fmt.Sprintf("channel size is too big; got %d, max is %d.", channelLength, maxInt), env)
+			err = ProcessTypedError(BadRangeArgumentCondition, fmt.Sprintf("channel size is too big; got %d, max is %d.", channelLength, maxInt), env)
 			return
 		}
 
@@ -58,7 +58,7 @@ func MakeChannelImpl(args *Data, env *SymbolTableFrame) (result *Data, err error
 func ChannelWriteImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	channelObj := Car(args)
 	if !ObjectP(channelObj) || ObjectType(channelObj) != "Channel" {
-		err = ProcessError(fmt.Sprintf("channel<- expects an Channel object but received %s.", ObjectType(channelObj)), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("channel<- expects an Channel object but received %s.", ObjectType(channelObj)), env)
 		return
 	}
 
@@ -84,7 +84,7 @@ func ChannelWriteImpl(args *Data, env *SymbolTableFrame) (result *Data, err erro
 func ChannelReadImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	channelObj := Car(args)
 	if !ObjectP(channelObj) || ObjectType(channelObj) != "Channel" {
-		err = ProcessError(fmt.Sprintf("<-channel expects an Channel object but received %s.", ObjectType(channelObj)), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("<-channel expects an Channel object but received %s.", ObjectType(channelObj)), env)
 		return
 	}
 
@@ -98,7 +98,7 @@ func ChannelReadImpl(args *Data, env *SymbolTableFrame) (result *Data, err error
 func ChannelTryWriteImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	channelObj := Car(args)
 	if !ObjectP(channelObj) || ObjectType(channelObj) != "Channel" {
-		err = ProcessError(fmt.Sprintf("channel-try-write expects an Channel object but received %s.", ObjectType(channelObj)), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("channel-try-write expects an Channel object but received %s.", ObjectType(channelObj)), env)
 		return
 	}
 
@@ -129,7 +129,7 @@ func ChannelTryWriteImpl(args *Data, env *SymbolTableFrame) (result *Data, err e
 func ChannelTryReadImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	channelObj := Car(args)
 	if !ObjectP(channelObj) || ObjectType(channelObj) != "Channel" {
-		err = ProcessError(fmt.Sprintf("<-channel expects an Channel object but received %s.", ObjectType(channelObj)), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("<-channel expects an Channel object but received %s.", ObjectType(channelObj)), env)
 		return
 	}
 
@@ -151,7 +151,7 @@ func ChannelTryReadImpl(args *Data, env *SymbolTableFrame) (result *Data, err er
 func CloseChannelImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	channelObj := Car(args)
 	if !ObjectP(channelObj) || ObjectType(channelObj) != "Channel" {
-		err = ProcessError(fmt.Sprintf("<-channel expects an Channel object but received %s.", ObjectType(channelObj)), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("<-channel expects an Channel object but received %s.", ObjectType(channelObj)), env)
 		return
 	}
 
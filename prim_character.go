@@ -0,0 +1,212 @@
+// Copyright 2014 SteelSeries ApS.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// This package implements a basic LISP interpretor for embedding in a go program for scripting.
+// This file contains the character primitive functions.
+
+package golisp
+
+import (
+	"fmt"
+	"unicode"
+	"unicode/utf8"
+)
+
+func RegisterCharacterPrimitives() {
+	MakePrimitiveFunction("char?", "1", IsCharacterImpl)
+	MakePrimitiveFunction("char->integer", "1", CharToIntegerImpl)
+	MakePrimitiveFunction("integer->char", "1", IntegerToCharImpl)
+	MakePrimitiveFunction("char-upcase", "1", CharUpcaseImpl)
+	MakePrimitiveFunction("char-downcase", "1", CharDowncaseImpl)
+	MakePrimitiveFunction("char-alphabetic?", "1", CharAlphabeticImpl)
+	MakePrimitiveFunction("char-numeric?", "1", CharNumericImpl)
+	MakePrimitiveFunction("char-whitespace?", "1", CharWhitespaceImpl)
+	MakePrimitiveFunction("char-upper-case?", "1", CharUpperCaseImpl)
+	MakePrimitiveFunction("char-lower-case?", "1", CharLowerCaseImpl)
+	MakePrimitiveFunction("char->digit", "1|2", CharToDigitImpl)
+	MakePrimitiveFunction("digit->char", "1|2", DigitToCharImpl)
+
+	MakePrimitiveFunction("char=?", ">=1", CharEqualImpl)
+	MakePrimitiveFunction("char<?", ">=1", CharLessThanImpl)
+	MakePrimitiveFunction("char>?", ">=1", CharGreaterThanImpl)
+	MakePrimitiveFunction("char<=?", ">=1", CharLessThanEqualImpl)
+	MakePrimitiveFunction("char>=?", ">=1", CharGreaterThanEqualImpl)
+	MakePrimitiveFunction("char-ci=?", ">=1", CharEqualCiImpl)
+}
+
+func characterArg(name string, c *Data, env *SymbolTableFrame) (ch rune, err error) {
+	if !CharacterP(c) {
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("%s requires a character but was given %s.", name, String(c)), env)
+		return
+	}
+	return CharacterValue(c), nil
+}
+
+func characterPredicate(name string, args *Data, env *SymbolTableFrame, predicate func(rune) bool) (result *Data, err error) {
+	ch, err := characterArg(name, Car(args), env)
+	if err != nil {
+		return
+	}
+	return BooleanWithValue(predicate(ch)), nil
+}
+
+func characterConversion(name string, args *Data, env *SymbolTableFrame, conversion func(rune) rune) (result *Data, err error) {
+	ch, err := characterArg(name, Car(args), env)
+	if err != nil {
+		return
+	}
+	return CharacterWithValue(conversion(ch)), nil
+}
+
+// Whether each neighbouring pair of characters is in order.
+func characterComparison(name string, args *Data, env *SymbolTableFrame, inOrder func(rune, rune) bool) (result *Data, err error) {
+	previous, err := characterArg(name, Car(args), env)
+	if err != nil {
+		return
+	}
+	ordered := true
+	for c := Cdr(args); NotNilP(c); c = Cdr(c) {
+		var ch rune
+		ch, err = characterArg(name, Car(c), env)
+		if err != nil {
+			return
+		}
+		ordered = ordered && inOrder(previous, ch)
+		previous = ch
+	}
+	return BooleanWithValue(ordered), nil
+}
+
+func radixArg(name string, args *Data, env *SymbolTableFrame) (radix int, err error) {
+	radix = 10
+	if Length(args) == 2 {
+		r := Cadr(args)
+		if !IntegerP(r) || IntegerValue(r) < 2 || IntegerValue(r) > 36 {
+			err = ProcessTypedError(BadRangeArgumentCondition, fmt.Sprintf("%s requires a radix between 2 and 36 but was given %s.", name, String(r)), env)
+			return
+		}
+		radix = int(IntegerValue(r))
+	}
+	return
+}
+
+func IsCharacterImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	return BooleanWithValue(CharacterP(Car(args))), nil
+}
+
+func CharToIntegerImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	ch, err := characterArg("char->integer", Car(args), env)
+	if err != nil {
+		return
+	}
+	return IntegerWithValue(int64(ch)), nil
+}
+
+func IntegerToCharImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	n := Car(args)
+	if !IntegerP(n) {
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("integer->char requires an integer but was given %s.", String(n)), env)
+		return
+	}
+	if IntegerValue(n) < 0 || IntegerValue(n) > unicode.MaxRune || !utf8.ValidRune(rune(IntegerValue(n))) {
+		err = ProcessTypedError(BadRangeArgumentCondition, fmt.Sprintf("integer->char requires a valid code point but was given %d.", IntegerValue(n)), env)
+		return
+	}
+	return CharacterWithValue(rune(IntegerValue(n))), nil
+}
+
+func CharUpcaseImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	return characterConversion("char-upcase", args, env, unicode.ToUpper)
+}
+
+func CharDowncaseImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	return characterConversion("char-downcase", args, env, unicode.ToLower)
+}
+
+func CharAlphabeticImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	return characterPredicate("char-alphabetic?", args, env, unicode.IsLetter)
+}
+
+func CharNumericImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	return characterPredicate("char-numeric?", args, env, unicode.IsDigit)
+}
+
+func CharWhitespaceImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	return characterPredicate("char-whitespace?", args, env, unicode.IsSpace)
+}
+
+func CharUpperCaseImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	return characterPredicate("char-upper-case?", args, env, unicode.IsUpper)
+}
+
+func CharLowerCaseImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	return characterPredicate("char-lower-case?", args, env, unicode.IsLower)
+}
+
+func CharToDigitImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	ch, err := characterArg("char->digit", Car(args), env)
+	if err != nil {
+		return
+	}
+	radix, err := radixArg("char->digit", args, env)
+	if err != nil {
+		return
+	}
+
+	digit := -1
+	switch {
+	case ch >= '0' && ch <= '9':
+		digit = int(ch - '0')
+	case ch >= 'a' && ch <= 'z':
+		digit = int(ch-'a') + 10
+	case ch >= 'A' && ch <= 'Z':
+		digit = int(ch-'A') + 10
+	}
+	if digit < 0 || digit >= radix {
+		return LispFalse, nil
+	}
+	return IntegerWithValue(int64(digit)), nil
+}
+
+func DigitToCharImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	n := Car(args)
+	if !IntegerP(n) {
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("digit->char requires an integer but was given %s.", String(n)), env)
+		return
+	}
+	radix, err := radixArg("digit->char", args, env)
+	if err != nil {
+		return
+	}
+
+	digit := IntegerValue(n)
+	if digit < 0 || digit >= int64(radix) {
+		return LispFalse, nil
+	}
+	return CharacterWithValue(rune("0123456789abcdefghijklmnopqrstuvwxyz"[digit])), nil
+}
+
+func CharEqualImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	return characterComparison("char=?", args, env, func(a rune, b rune) bool { return a == b })
+}
+
+func CharLessThanImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	return characterComparison("char<?", args, env, func(a rune, b rune) bool { return a < b })
+}
+
+func CharGreaterThanImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	return characterComparison("char>?", args, env, func(a rune, b rune) bool { return a > b })
+}
+
+func CharLessThanEqualImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	return characterComparison("char<=?", args, env, func(a rune, b rune) bool { return a <= b })
+}
+
+func CharGreaterThanEqualImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	return characterComparison("char>=?", args, env, func(a rune, b rune) bool { return a >= b })
+}
+
+func CharEqualCiImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	return characterComparison("char-ci=?", args, env, func(a rune, b rune) bool { return unicode.ToLower(a) == unicode.ToLower(b) })
+}
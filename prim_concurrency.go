@@ -50,7 +50,7 @@ func ForkImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	f := Car(args)
 
 	if !FunctionP(f) {
-		err = ProcessError(fmt.Sprintf("fork expected a function, but received %v.", f), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("fork expected a function, but received %v.", f), env)
 		return
 	}
 
@@ -100,7 +100,7 @@ func ProcSleepImpl(args *Data, env *SymbolTableFrame) (result *Data, err error)
 	procObj := Car(args)
 
 	if !ObjectP(procObj) || ObjectType(procObj) != "Process" {
-		err = ProcessError(fmt.Sprintf("proc-sleep expects a Process object expected but received %s.", ObjectType(procObj)), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("proc-sleep expects a Process object expected but received %s.", ObjectType(procObj)), env)
 		return
 	}
 
@@ -108,7 +108,7 @@ func ProcSleepImpl(args *Data, env *SymbolTableFrame) (result *Data, err error)
 
 	millis := Cadr(args)
 	if !IntegerP(millis) {
-		err = ProcessError(fmt.Sprintf("proc-sleep expected an integer as a delay, but received %v.", millis), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("proc-sleep expected an integer as a delay, but received %v.", millis), env)
 		return
 	}
 
@@ -126,7 +126,7 @@ func WakeImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	procObj := Car(args)
 
 	if !ObjectP(procObj) || ObjectType(procObj) != "Process" {
-		err = ProcessError(fmt.Sprintf("wake expects a Process object expected but received %s.", ObjectType(procObj)), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("wake expects a Process object expected but received %s.", ObjectType(procObj)), env)
 		return
 	}
 
@@ -141,13 +141,13 @@ func WakeImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 func ScheduleImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	millis := Car(args)
 	if !IntegerP(millis) {
-		err = ProcessError(fmt.Sprintf("schedule expected an integer as a delay, but received %v.", millis), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("schedule expected an integer as a delay, but received %v.", millis), env)
 		return
 	}
 	f := Cadr(args)
 
 	if !FunctionP(f) {
-		err = ProcessError(fmt.Sprintf("schedule expected a function, but received %v.", f), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("schedule expected a function, but received %v.", f), env)
 		return
 	}
 
@@ -209,7 +209,7 @@ func AbandonImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	procObj := Car(args)
 
 	if !ObjectP(procObj) || ObjectType(procObj) != "Process" {
-		err = ProcessError(fmt.Sprintf("adandon expects a Process object expected but received %s.", ObjectType(procObj)), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("adandon expects a Process object expected but received %s.", ObjectType(procObj)), env)
 		return
 	}
 
@@ -230,7 +230,7 @@ func ResetTimeoutImpl(args *Data, env *SymbolTableFrame) (result *Data, err erro
 	procObj := Car(args)
 
 	if !ObjectP(procObj) || ObjectType(procObj) != "Process" {
-		err = ProcessError(fmt.Sprintf("restart expects a Process object expected but received %s.", ObjectType(procObj)), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("restart expects a Process object expected but received %s.", ObjectType(procObj)), env)
 		return
 	}
 
@@ -254,7 +254,7 @@ func JoinImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	procObj := Car(args)
 
 	if !ObjectP(procObj) || ObjectType(procObj) != "Process" {
-		err = ProcessError(fmt.Sprintf("join expects a Process object but received %s.", ObjectType(procObj)), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("join expects a Process object but received %s.", ObjectType(procObj)), env)
 		return
 	}
 	proc := (*Process)(ObjectValue(procObj))
@@ -284,7 +284,7 @@ func AtomicImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	if Length(args) == 1 {
 		initObj := Car(args)
 		if !IntegerP(initObj) {
-			err = ProcessError(fmt.Sprintf("atomic expects an Integer as its argument but received %s.", TypeName(TypeOf(initObj))), env)
+			err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("atomic expects an Integer as its argument but received %s.", TypeName(TypeOf(initObj))), env)
 			return
 		}
 		atomicVal = IntegerValue(initObj)
@@ -296,7 +296,7 @@ func AtomicImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 func AtomicLoadImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	atomicObj := Car(args)
 	if !ObjectP(atomicObj) || ObjectType(atomicObj) != "Atomic" {
-		err = ProcessError(fmt.Sprintf("atomic-load expects an Atomic object but received %s.", ObjectType(atomicObj)), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("atomic-load expects an Atomic object but received %s.", ObjectType(atomicObj)), env)
 		return
 	}
 
@@ -310,7 +310,7 @@ func AtomicLoadImpl(args *Data, env *SymbolTableFrame) (result *Data, err error)
 func AtomicStoreImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	atomicObj := Car(args)
 	if !ObjectP(atomicObj) || ObjectType(atomicObj) != "Atomic" {
-		err = ProcessError(fmt.Sprintf("atomic-store! expects an Atomic object but received %s.", ObjectType(atomicObj)), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("atomic-store! expects an Atomic object but received %s.", ObjectType(atomicObj)), env)
 		return
 	}
 
@@ -319,7 +319,7 @@ func AtomicStoreImpl(args *Data, env *SymbolTableFrame) (result *Data, err error
 	newObj := Cadr(args)
 
 	if !IntegerP(newObj) {
-		err = ProcessError(fmt.Sprintf("atomic-store! expects an Integer as its second argument but received %s.", TypeName(TypeOf(newObj))), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("atomic-store! expects an Integer as its second argument but received %s.", TypeName(TypeOf(newObj))), env)
 		return
 	}
 
@@ -333,7 +333,7 @@ func AtomicStoreImpl(args *Data, env *SymbolTableFrame) (result *Data, err error
 func AtomicAddImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	atomicObj := Car(args)
 	if !ObjectP(atomicObj) || ObjectType(atomicObj) != "Atomic" {
-		err = ProcessError(fmt.Sprintf("atomic-add! expects an Atomic object but received %s.", ObjectType(atomicObj)), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("atomic-add! expects an Atomic object but received %s.", ObjectType(atomicObj)), env)
 		return
 	}
 
@@ -342,7 +342,7 @@ func AtomicAddImpl(args *Data, env *SymbolTableFrame) (result *Data, err error)
 	deltaObj := Cadr(args)
 
 	if !IntegerP(deltaObj) {
-		err = ProcessError(fmt.Sprintf("atomic-add! expects an Integer as its second argument but received %s.", TypeName(TypeOf(deltaObj))), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("atomic-add! expects an Integer as its second argument but received %s.", TypeName(TypeOf(deltaObj))), env)
 		return
 	}
 
@@ -356,7 +356,7 @@ func AtomicAddImpl(args *Data, env *SymbolTableFrame) (result *Data, err error)
 func AtomicSwapImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	atomicObj := Car(args)
 	if !ObjectP(atomicObj) || ObjectType(atomicObj) != "Atomic" {
-		err = ProcessError(fmt.Sprintf("atomic-swap! expects an Atomic object but received %s.", ObjectType(atomicObj)), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("atomic-swap! expects an Atomic object but received %s.", ObjectType(atomicObj)), env)
 		return
 	}
 
@@ -365,7 +365,7 @@ func AtomicSwapImpl(args *Data, env *SymbolTableFrame) (result *Data, err error)
 	newObj := Cadr(args)
 
 	if !IntegerP(newObj) {
-		err = ProcessError(fmt.Sprintf("atomic-swap! expects an Integer as its second argument but received %s.", TypeName(TypeOf(newObj))), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("atomic-swap! expects an Integer as its second argument but received %s.", TypeName(TypeOf(newObj))), env)
 		return
 	}
 
@@ -379,7 +379,7 @@ func AtomicSwapImpl(args *Data, env *SymbolTableFrame) (result *Data, err error)
 func AtomicCompareAndSwapImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	atomicObj := Car(args)
 	if !ObjectP(atomicObj) || ObjectType(atomicObj) != "Atomic" {
-		err = ProcessError(fmt.Sprintf("atomic-compare-and-swap! expects an Atomic object but received %s.", ObjectType(atomicObj)), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("atomic-compare-and-swap! expects an Atomic object but received %s.", ObjectType(atomicObj)), env)
 		return
 	}
 
@@ -388,14 +388,14 @@ func AtomicCompareAndSwapImpl(args *Data, env *SymbolTableFrame) (result *Data,
 	oldObj := Cadr(args)
 
 	if !IntegerP(oldObj) {
-		err = ProcessError(fmt.Sprintf("atomic-compare-and-swap! expects an Integer as its second argument but received %s.", TypeName(TypeOf(oldObj))), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("atomic-compare-and-swap! expects an Integer as its second argument but received %s.", TypeName(TypeOf(oldObj))), env)
 		return
 	}
 
 	newObj := Caddr(args)
 
 	if !IntegerP(newObj) {
-		err = ProcessError(fmt.Sprintf("atomic-compare-and-swap! expects an Integer as its third argument but received %s.", TypeName(TypeOf(newObj))), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("atomic-compare-and-swap! expects an Integer as its third argument but received %s.", TypeName(TypeOf(newObj))), env)
 		return
 	}
 
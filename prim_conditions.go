@@ -0,0 +1,290 @@
+// Copyright 2014 SteelSeries ApS.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// This package implements a basic LISP interpretor for embedding in a go program for scripting.
+// This file contains the condition primitive functions.
+
+package golisp
+
+import (
+	"fmt"
+)
+
+func RegisterConditionPrimitives() {
+	MakePrimitiveFunction("make-condition", "2|3", MakeConditionImpl)
+	MakePrimitiveFunction("raise", "1", RaiseImpl)
+	MakePrimitiveFunction("raise-continuable", "1", RaiseContinuableImpl)
+	MakePrimitiveFunction("with-exception-handler", "2", WithExceptionHandlerImpl)
+	MakeSpecialForm("guard", ">=1", GuardImpl)
+	MakeSpecialForm("ignore-errors", "*", IgnoreErrorsImpl)
+
+	MakePrimitiveFunction("condition?", "1", IsConditionImpl)
+	MakePrimitiveFunction("error?", "1", IsConditionImpl)
+	MakePrimitiveFunction("error-object?", "1", IsConditionImpl)
+	MakePrimitiveFunction("condition/type", "1", ConditionTypeImpl)
+	MakePrimitiveFunction("condition/type?", "2", IsConditionOfTypeImpl)
+	MakePrimitiveFunction("condition/report-string", "1", ConditionReportStringImpl)
+	MakePrimitiveFunction("access-condition", "2", AccessConditionImpl)
+	MakePrimitiveFunction("error-message", "1", ConditionMessageImpl)
+	MakePrimitiveFunction("error-object-message", "1", ConditionMessageImpl)
+	MakePrimitiveFunction("error-irritants", "1", ConditionIrritantsImpl)
+	MakePrimitiveFunction("error-object-irritants", "1", ConditionIrritantsImpl)
+}
+
+func conditionArg(name string, args *Data, env *SymbolTableFrame) (condition *Condition, err error) {
+	c := Car(args)
+	if !ConditionP(c) {
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("%s requires a condition as its first argument, but received %s.", name, String(c)), env)
+		return
+	}
+	return ConditionValue(c), nil
+}
+
+func conditionTypeArg(name string, t *Data, env *SymbolTableFrame) (conditionType string, err error) {
+	if !SymbolP(t) {
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("%s requires a condition type symbol, but received %s.", name, String(t)), env)
+		return
+	}
+	conditionType = StringValue(t)
+	for _, known := range ConditionTypes {
+		if known == conditionType {
+			return
+		}
+	}
+	err = ProcessTypedError(BadRangeArgumentCondition, fmt.Sprintf("%s received an unknown condition type: %s.", name, conditionType), env)
+	return
+}
+
+func conditionMessage(m *Data) string {
+	if StringP(m) {
+		return StringValue(m)
+	}
+	return String(m)
+}
+
+func MakeConditionImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	conditionType, err := conditionTypeArg("make-condition", Car(args), env)
+	if err != nil {
+		return
+	}
+
+	irritants := Caddr(args)
+	if !ListP(irritants) {
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("make-condition requires a list of irritants, but received %s.", String(irritants)), env)
+		return
+	}
+
+	return ConditionWithValue(MakeCondition(conditionType, conditionMessage(Cadr(args)), irritants)), nil
+}
+
+func raisedError(obj *Data) error {
+	if ConditionP(obj) {
+		return ConditionValue(obj)
+	}
+	return &RaisedObject{Object: obj}
+}
+
+func RaiseImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	obj := Car(args)
+	if ConditionP(obj) {
+		return nil, ProcessCondition(ConditionValue(obj), env)
+	}
+	_, err = signalError(raisedError(obj), false, env)
+	return
+}
+
+// Like raise, but the value the handler returns is returned from
+// raise-continuable.
+func RaiseContinuableImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	return signalError(raisedError(Car(args)), true, env)
+}
+
+// The handler is called in the dynamic extent of the raise, with the
+// handler outside this one installed. It can escape through a continuation
+// or raise again; returning from a non-continuable raise is an error. Errors
+// signalled without an environment reach the handler once the thunk has
+// unwound.
+func WithExceptionHandlerImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	handler := Car(args)
+	if !FunctionOrPrimitiveP(handler) {
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("with-exception-handler requires a function as its first argument, but received %s.", String(handler)), env)
+		return
+	}
+
+	thunk := Cadr(args)
+	if !FunctionOrPrimitiveP(thunk) {
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("with-exception-handler requires a function as its second argument, but received %s.", String(thunk)), env)
+		return
+	}
+
+	extent := &dynamicExtent{Handler: handler}
+	result, err = ApplyWithoutEval(thunk, nil, newDynamicFrame(env, "with-exception-handler", extent))
+	extent.exit()
+	if IsHandleableError(err) && !isDispatched(err) {
+		return invokeHandler(handler, env, err, false, env)
+	}
+	return
+}
+
+// Evaluates body in a frame that keeps exception handlers outside it from
+// seeing the errors it signals, so the caller can deal with them.
+func evaluateGuarded(body *Data, env *SymbolTableFrame) (result *Data, err error) {
+	extent := &dynamicExtent{Catches: true}
+	defer extent.exit()
+	guardedEnv := newDynamicFrame(env, "guarded", extent)
+	for c := body; NotNilP(c); c = Cdr(c) {
+		result, err = Eval(Car(c), guardedEnv)
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+// (guard (var clause...) body...) evaluates body and, if it signals, binds var
+// to the condition and selects a clause as cond does, including (test =>
+// receiver) clauses. If no clause is selected the condition is passed on.
+func GuardImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	spec := Car(args)
+	if !PairP(spec) || !SymbolP(Car(spec)) {
+		err = ProcessTypedError(SyntaxErrorCondition, "guard requires a (variable clause ...) list as its first argument", env)
+		return
+	}
+
+	result, err = evaluateGuarded(Cdr(args), env)
+	if !IsHandleableError(err) {
+		return
+	}
+
+	bodyErr := err
+	localEnv := NewSymbolTableFrameBelow(env, "guard")
+	_, err = localEnv.BindLocallyTo(Car(spec), ConditionObject(bodyErr))
+	if err != nil {
+		return
+	}
+
+	var test *Data
+	for c := Cdr(spec); NotNilP(c); c = Cdr(c) {
+		clause := Car(c)
+		if !PairP(clause) {
+			err = ProcessTypedError(SyntaxErrorCondition, "guard expects a sequence of clauses that are lists", env)
+			return
+		}
+		if IsEqual(Car(clause), Intern("else")) {
+			return evaluateBody(Cdr(clause), localEnv)
+		}
+		test, err = Eval(Car(clause), localEnv)
+		if err != nil {
+			return
+		}
+		if BooleanValue(test) {
+			if NilP(Cdr(clause)) {
+				return test, nil
+			}
+			if IsEqual(Cadr(clause), Intern("=>")) {
+				return applyGuardReceiver(clause, test, localEnv)
+			}
+			return evaluateBody(Cdr(clause), localEnv)
+		}
+	}
+	return signalError(bodyErr, false, env)
+}
+
+// Applies the receiver of a (test => receiver) clause to the test's value.
+func applyGuardReceiver(clause *Data, test *Data, env *SymbolTableFrame) (result *Data, err error) {
+	if Length(clause) != 3 {
+		err = ProcessTypedError(SyntaxErrorCondition, fmt.Sprintf("guard expects a (test => receiver) clause, but received %s", String(clause)), env)
+		return
+	}
+	receiver, err := Eval(Caddr(clause), env)
+	if err != nil {
+		return
+	}
+	if !FunctionOrPrimitiveP(receiver) {
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("guard requires the receiver of a => clause to be a function, but received %s.", String(receiver)), env)
+		return
+	}
+	return ApplyWithoutEval(receiver, InternalMakeList(test), env)
+}
+
+// Evaluates body, returning the condition instead of signalling it.
+func IgnoreErrorsImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	result, err = evaluateGuarded(args, env)
+	if IsHandleableError(err) {
+		return ConditionObject(err), nil
+	}
+	return
+}
+
+func IsConditionImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	return BooleanWithValue(ConditionP(Car(args))), nil
+}
+
+func ConditionTypeImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	condition, err := conditionArg("condition/type", args, env)
+	if err != nil {
+		return
+	}
+	return Intern(condition.Type), nil
+}
+
+func IsConditionOfTypeImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	conditionType, err := conditionTypeArg("condition/type?", Cadr(args), env)
+	if err != nil {
+		return
+	}
+	if !ConditionP(Car(args)) {
+		return LispFalse, nil
+	}
+	return BooleanWithValue(ConditionValue(Car(args)).IsA(conditionType)), nil
+}
+
+func ConditionReportStringImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	condition, err := conditionArg("condition/report-string", args, env)
+	if err != nil {
+		return
+	}
+	return StringWithValue(condition.ReportString()), nil
+}
+
+func AccessConditionImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	condition, err := conditionArg("access-condition", args, env)
+	if err != nil {
+		return
+	}
+
+	field := Cadr(args)
+	if !SymbolP(field) {
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("access-condition requires a field name symbol as its second argument, but received %s.", String(field)), env)
+		return
+	}
+
+	switch StringValue(field) {
+	case "type":
+		return Intern(condition.Type), nil
+	case "message":
+		return StringWithValue(condition.Message), nil
+	case "irritants":
+		return condition.Irritants, nil
+	default:
+		err = ProcessTypedError(BadRangeArgumentCondition, fmt.Sprintf("access-condition: conditions have no field named %s.", StringValue(field)), env)
+		return
+	}
+}
+
+func ConditionMessageImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	condition, err := conditionArg("error-message", args, env)
+	if err != nil {
+		return
+	}
+	return StringWithValue(condition.Message), nil
+}
+
+func ConditionIrritantsImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	condition, err := conditionArg("error-irritants", args, env)
+	if err != nil {
+		return
+	}
+	return condition.Irritants, nil
+}
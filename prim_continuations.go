@@ -0,0 +1,77 @@
+// Copyright 2014 SteelSeries ApS.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// This package implements a basic LISP interpretor for embedding in a go program for scripting.
+// This file contains the continuation primitive functions.
+
+package golisp
+
+import (
+	"fmt"
+)
+
+func RegisterContinuationPrimitives() {
+	MakePrimitiveFunction("call-with-current-continuation", "1", CallCCImpl)
+	MakePrimitiveFunction("call/cc", "1", CallCCImpl)
+	MakePrimitiveFunction("call-with-escape-continuation", "1", CallCCImpl)
+	MakePrimitiveFunction("within-continuation", "2", WithinContinuationImpl)
+	MakePrimitiveFunction("dynamic-wind", "3", DynamicWindImpl)
+	MakePrimitiveFunction("continuation?", "1", IsContinuationImpl)
+}
+
+func CallCCImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	f := Car(args)
+	if !FunctionOrPrimitiveP(f) {
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("call/cc requires a function as its argument, but received %s.", String(f)), env)
+		return
+	}
+
+	return CallWithCurrentContinuation(f, env)
+}
+
+func WithinContinuationImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	k := Car(args)
+	if !ContinuationP(k) {
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("within-continuation requires a continuation as its first argument, but received %s.", String(k)), env)
+		return
+	}
+
+	thunk := Cadr(args)
+	if !FunctionOrPrimitiveP(thunk) {
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("within-continuation requires a function as its second argument, but received %s.", String(thunk)), env)
+		return
+	}
+
+	return ContinuationValue(k).Within(thunk, env)
+}
+
+func DynamicWindImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	for a := args; NotNilP(a); a = Cdr(a) {
+		if !FunctionOrPrimitiveP(Car(a)) {
+			err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("dynamic-wind requires three functions, but received %s.", String(Car(a))), env)
+			return
+		}
+	}
+
+	_, err = ApplyWithoutEval(First(args), nil, env)
+	if err != nil {
+		return
+	}
+
+	result, err = ApplyWithoutEval(Second(args), nil, env)
+
+	// the after thunk runs however the extent is left, including escapes
+	_, afterErr := ApplyWithoutEval(Third(args), nil, env)
+	if err == nil {
+		err = afterErr
+	}
+	if err != nil {
+		result = nil
+	}
+	return
+}
+
+func IsContinuationImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	return BooleanWithValue(ContinuationP(Car(args))), nil
+}
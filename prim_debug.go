@@ -19,6 +19,7 @@ var DebugCommandPrefix string = ":"
 func RegisterDebugPrimitives() {
 	MakePrimitiveFunction("debug-trace", "0|1", DebugTraceImpl)
 	MakePrimitiveFunction("lisp-trace", "0|1", LispTraceImpl)
+	MakePrimitiveFunction("compile-functions", "0|1", CompileFunctionsImpl)
 	MakePrimitiveFunction("debug-on-entry", "0", DebugOnEntryImpl)
 	MakePrimitiveFunction("remove-debug-on-entry", "1", RemoveDebugOnEntryImpl)
 	MakePrimitiveFunction("dump", "0", DumpSymbolTableImpl)
@@ -47,6 +48,13 @@ func LispTraceImpl(args *Data, env *SymbolTableFrame) (result *Data, err error)
 	return BooleanWithValue(LispTrace), nil
 }
 
+func CompileFunctionsImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	if Length(args) == 1 {
+		CompilationEnabled = BooleanValue(Car(args))
+	}
+	return BooleanWithValue(CompilationEnabled), nil
+}
+
 func DebugOnEntryImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	var names = make([]*Data, 0, 0)
 	for _, f := range set.StringSlice(DebugOnEntry) {
@@ -252,11 +260,20 @@ func DebugRepl(env *SymbolTableFrame) {
 }
 
 func ProcessError(errorMessage string, env *SymbolTableFrame) error {
+	return ProcessCondition(MakeCondition(PrimitiveProcedureErrorCondition, errorMessage, nil), env)
+}
+
+func ProcessTypedError(conditionType string, errorMessage string, env *SymbolTableFrame) error {
+	return ProcessCondition(MakeCondition(conditionType, errorMessage, nil), env)
+}
+
+func ProcessCondition(condition *Condition, env *SymbolTableFrame) error {
 	if DebugOnError && IsInteractive {
-		fmt.Printf("ERROR!  %s\n", errorMessage)
+		fmt.Printf("ERROR!  %s\n", condition.ReportString())
 		DebugRepl(env)
 		return nil
 	} else {
-		return errors.New(errorMessage)
+		_, err := signalError(condition, false, env)
+		return err
 	}
 }
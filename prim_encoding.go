@@ -0,0 +1,182 @@
+// Copyright 2014 SteelSeries ApS.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// This package implements a basic LISP interpretor for embedding in a go program for scripting.
+// This file contains the bytearray encoding, checksum and digest primitive functions.
+
+package golisp
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"hash/adler32"
+	"hash/crc32"
+)
+
+func RegisterEncodingPrimitives() {
+	MakePrimitiveFunction("bytearray->hex", "1", BytearrayToHexImpl)
+	MakePrimitiveFunction("hex->bytearray", "1", HexToBytearrayImpl)
+	MakePrimitiveFunction("bytearray->base64", "1", BytearrayToBase64Impl)
+	MakePrimitiveFunction("base64->bytearray", "1", Base64ToBytearrayImpl)
+
+	MakePrimitiveFunction("crc32", "1|2", Crc32Impl)
+	MakePrimitiveFunction("crc16-ccitt", "1|2", Crc16CcittImpl)
+	MakePrimitiveFunction("adler32", "1", Adler32Impl)
+	MakePrimitiveFunction("md5", "1", Md5Impl)
+	MakePrimitiveFunction("sha1", "1", Sha1Impl)
+	MakePrimitiveFunction("sha256", "1", Sha256Impl)
+}
+
+// Checksums and digests take a bytearray, or a string whose UTF-8 bytes are
+// used.
+func checksumArg(name string, d *Data, env *SymbolTableFrame) (b []byte, err error) {
+	if StringP(d) {
+		return []byte(StringValue(d)), nil
+	}
+	if !ObjectP(d) || ObjectType(d) != "[]byte" {
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("%s expects a bytearray or string but received %s.", name, String(d)), env)
+		return
+	}
+	return *(*[]byte)(ObjectValue(d)), nil
+}
+
+func BytearrayToHexImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	b, err := bytearrayArg("bytearray->hex", Car(args), env)
+	if err != nil {
+		return
+	}
+	return StringWithValue(hex.EncodeToString(b)), nil
+}
+
+func HexToBytearrayImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	str := Car(args)
+	if !StringP(str) {
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("hex->bytearray expects a string but received %s.", String(str)), env)
+		return
+	}
+
+	b, decodeErr := hex.DecodeString(StringValue(str))
+	if decodeErr != nil {
+		err = ProcessError(fmt.Sprintf("hex->bytearray was given invalid hex: %s.", decodeErr), env)
+		return
+	}
+	return bytearrayWithValue(b), nil
+}
+
+func BytearrayToBase64Impl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	b, err := bytearrayArg("bytearray->base64", Car(args), env)
+	if err != nil {
+		return
+	}
+	return StringWithValue(base64.StdEncoding.EncodeToString(b)), nil
+}
+
+func Base64ToBytearrayImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	str := Car(args)
+	if !StringP(str) {
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("base64->bytearray expects a string but received %s.", String(str)), env)
+		return
+	}
+
+	b, decodeErr := base64.StdEncoding.DecodeString(StringValue(str))
+	if decodeErr != nil {
+		err = ProcessError(fmt.Sprintf("base64->bytearray was given invalid base64: %s.", decodeErr), env)
+		return
+	}
+	return bytearrayWithValue(b), nil
+}
+
+// The optional starting value for a checksum, so that it can be computed a
+// piece at a time.
+func checksumInitialArg(name string, args *Data, initial uint32, max int64, env *SymbolTableFrame) (value uint32, err error) {
+	if Length(args) < 2 {
+		return initial, nil
+	}
+	initialObj := Cadr(args)
+	if !IntegerP(initialObj) || IntegerValue(initialObj) < 0 || IntegerValue(initialObj) > max {
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("%s expects its initial value to be an integer from 0 to %d but received %s.", name, max, String(initialObj)), env)
+		return
+	}
+	return uint32(IntegerValue(initialObj)), nil
+}
+
+// The IEEE CRC-32. Given the CRC of earlier data, it continues from there.
+func Crc32Impl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	b, err := checksumArg("crc32", Car(args), env)
+	if err != nil {
+		return
+	}
+	initial, err := checksumInitialArg("crc32", args, 0, 0xFFFFFFFF, env)
+	if err != nil {
+		return
+	}
+	return IntegerWithValue(int64(crc32.Update(initial, crc32.IEEETable, b))), nil
+}
+
+func crc16Ccitt(crc uint16, b []byte) uint16 {
+	for _, octet := range b {
+		crc ^= uint16(octet) << 8
+		for i := 0; i < 8; i++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
+
+// CRC-16/CCITT with the polynomial 0x1021, starting from 0xFFFF unless given
+// another initial value.
+func Crc16CcittImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	b, err := checksumArg("crc16-ccitt", Car(args), env)
+	if err != nil {
+		return
+	}
+	initial, err := checksumInitialArg("crc16-ccitt", args, 0xFFFF, 0xFFFF, env)
+	if err != nil {
+		return
+	}
+	return IntegerWithValue(int64(crc16Ccitt(uint16(initial), b))), nil
+}
+
+func Adler32Impl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	b, err := checksumArg("adler32", Car(args), env)
+	if err != nil {
+		return
+	}
+	return IntegerWithValue(int64(adler32.Checksum(b))), nil
+}
+
+func Md5Impl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	b, err := checksumArg("md5", Car(args), env)
+	if err != nil {
+		return
+	}
+	digest := md5.Sum(b)
+	return bytearrayWithValue(digest[:]), nil
+}
+
+func Sha1Impl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	b, err := checksumArg("sha1", Car(args), env)
+	if err != nil {
+		return
+	}
+	digest := sha1.Sum(b)
+	return bytearrayWithValue(digest[:]), nil
+}
+
+func Sha256Impl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	b, err := checksumArg("sha256", Car(args), env)
+	if err != nil {
+		return
+	}
+	digest := sha256.Sum256(b)
+	return bytearrayWithValue(digest[:]), nil
+}
@@ -38,7 +38,7 @@ func EnvironmentPImpl(args *Data, env *SymbolTableFrame) (result *Data, err erro
 
 func EnvironmentParentPImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	if !EnvironmentP(Car(args)) {
-		err = ProcessError("environment-has-parent? requires an environment as it's argument", env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, "environment-has-parent? requires an environment as it's argument", env)
 		return
 	}
 	e := EnvironmentValue(Car(args))
@@ -47,7 +47,7 @@ func EnvironmentParentPImpl(args *Data, env *SymbolTableFrame) (result *Data, er
 
 func EnvironmentParentImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	if !EnvironmentP(Car(args)) {
-		err = ProcessError("environment-parent requires an environment as it's argument", env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, "environment-parent requires an environment as it's argument", env)
 		return
 	}
 	e := EnvironmentValue(Car(args))
@@ -60,12 +60,12 @@ func EnvironmentParentImpl(args *Data, env *SymbolTableFrame) (result *Data, err
 
 func EnvironmentBoundNamesImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	if !EnvironmentP(Car(args)) {
-		err = ProcessError("environment-bound-names requires an environment as it's argument", env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, "environment-bound-names requires an environment as it's argument", env)
 		return
 	}
 	e := EnvironmentValue(Car(args))
 	keys := make([]*Data, 0, 0)
-	for _, val := range e.Bindings {
+	for _, val := range e.AllBindings() {
 		keys = append(keys, val.Sym)
 	}
 	return ArrayToList(keys), nil
@@ -73,12 +73,12 @@ func EnvironmentBoundNamesImpl(args *Data, env *SymbolTableFrame) (result *Data,
 
 func EnvironmentMacroNamesImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	if !EnvironmentP(Car(args)) {
-		err = ProcessError("environment-macro-names requires an environment as it's argument", env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, "environment-macro-names requires an environment as it's argument", env)
 		return
 	}
 	e := EnvironmentValue(Car(args))
 	keys := make([]*Data, 0, 0)
-	for _, val := range e.Bindings {
+	for _, val := range e.AllBindings() {
 		if MacroP(val.Val) {
 			keys = append(keys, val.Sym)
 		}
@@ -88,12 +88,12 @@ func EnvironmentMacroNamesImpl(args *Data, env *SymbolTableFrame) (result *Data,
 
 func EnvironmentBindingsImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	if !EnvironmentP(Car(args)) {
-		err = ProcessError("environment-bindings requires an environment as it's argument", env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, "environment-bindings requires an environment as it's argument", env)
 		return
 	}
 	e := EnvironmentValue(Car(args))
 	keys := make([]*Data, 0, 0)
-	for _, val := range e.Bindings {
+	for _, val := range e.AllBindings() {
 		if NilP(val.Val) {
 			keys = append(keys, InternalMakeList(val.Sym))
 		} else {
@@ -105,11 +105,11 @@ func EnvironmentBindingsImpl(args *Data, env *SymbolTableFrame) (result *Data, e
 
 func EnvironmentReferenceTypeImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	if !EnvironmentP(Car(args)) {
-		err = ProcessError("environment-reference-type? requires an environment as it's first argument", env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, "environment-reference-type? requires an environment as it's first argument", env)
 		return
 	}
 	if !SymbolP(Cadr(args)) {
-		err = ProcessError("environment-reference-type? requires a symbol as it's second argument", env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, "environment-reference-type? requires a symbol as it's second argument", env)
 		return
 	}
 
@@ -129,11 +129,11 @@ func EnvironmentReferenceTypeImpl(args *Data, env *SymbolTableFrame) (result *Da
 
 func EnvironmentBoundPImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	if !EnvironmentP(Car(args)) {
-		err = ProcessError("environment-bound? requires an environment as it's first argument", env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, "environment-bound? requires an environment as it's first argument", env)
 		return
 	}
 	if !SymbolP(Cadr(args)) {
-		err = ProcessError("environment-bound? requires a symbol as it's second argument", env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, "environment-bound? requires a symbol as it's second argument", env)
 		return
 	}
 
@@ -144,11 +144,11 @@ func EnvironmentBoundPImpl(args *Data, env *SymbolTableFrame) (result *Data, err
 
 func EnvironmentAssignedPImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	if !EnvironmentP(Car(args)) {
-		err = ProcessError("environment-asigned? requires an environment as it's first argument", env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, "environment-asigned? requires an environment as it's first argument", env)
 		return
 	}
 	if !SymbolP(Cadr(args)) {
-		err = ProcessError("environment-assigned? requires a symbol as it's second argument", env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, "environment-assigned? requires a symbol as it's second argument", env)
 		return
 	}
 
@@ -164,7 +164,7 @@ func EnvironmentAssignedPImpl(args *Data, env *SymbolTableFrame) (result *Data,
 			result = LispTrue
 		}
 	} else {
-		err = ProcessError("environment-assigned?: name is unbound", env)
+		err = ProcessTypedError(UnboundVariableCondition, "environment-assigned?: name is unbound", env)
 		return
 	}
 	return
@@ -172,11 +172,11 @@ func EnvironmentAssignedPImpl(args *Data, env *SymbolTableFrame) (result *Data,
 
 func EnvironmentLookupImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	if !EnvironmentP(Car(args)) {
-		err = ProcessError("environment-lookup requires an environment as it's first argument", env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, "environment-lookup requires an environment as it's first argument", env)
 		return
 	}
 	if !SymbolP(Cadr(args)) {
-		err = ProcessError("environment-lookup requires a symbol as it's second argument", env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, "environment-lookup requires a symbol as it's second argument", env)
 		return
 	}
 
@@ -193,18 +193,18 @@ func EnvironmentLookupImpl(args *Data, env *SymbolTableFrame) (result *Data, err
 			return binding.Val, nil
 		}
 	} else {
-		err = ProcessError("environment-lookup: name is unbound", env)
+		err = ProcessTypedError(UnboundVariableCondition, "environment-lookup: name is unbound", env)
 		return
 	}
 }
 
 func EnvironmentLookupMacroImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	if !EnvironmentP(Car(args)) {
-		err = ProcessError("environment-lookup-macro requires an environment as it's first argument", env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, "environment-lookup-macro requires an environment as it's first argument", env)
 		return
 	}
 	if !SymbolP(Cadr(args)) {
-		err = ProcessError("environment-lookup-macro requires a symbol as it's second argument", env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, "environment-lookup-macro requires a symbol as it's second argument", env)
 		return
 	}
 
@@ -220,11 +220,11 @@ func EnvironmentLookupMacroImpl(args *Data, env *SymbolTableFrame) (result *Data
 
 func EnvironmentAssignablePImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	if !EnvironmentP(Car(args)) {
-		err = ProcessError("environment-assignable? requires an environment as it's first argument", env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, "environment-assignable? requires an environment as it's first argument", env)
 		return
 	}
 	if !SymbolP(Cadr(args)) {
-		err = ProcessError("environment-assignable? requires a symbol as it's second argument", env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, "environment-assignable? requires a symbol as it's second argument", env)
 		return
 	}
 
@@ -235,11 +235,11 @@ func EnvironmentAssignablePImpl(args *Data, env *SymbolTableFrame) (result *Data
 
 func EnvironmentAssignBangImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	if !EnvironmentP(Car(args)) {
-		err = ProcessError("environment-assign! requires an environment as it's first argument", env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, "environment-assign! requires an environment as it's first argument", env)
 		return
 	}
 	if !SymbolP(Cadr(args)) {
-		err = ProcessError("environment-assign! requires a symbol as it's second argument", env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, "environment-assign! requires a symbol as it's second argument", env)
 		return
 	}
 
@@ -254,11 +254,11 @@ func EnvironmentAssignBangImpl(args *Data, env *SymbolTableFrame) (result *Data,
 
 func EnvironmentDefinablePImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	if !EnvironmentP(Car(args)) {
-		err = ProcessError("environment-definable? requires an environment as it's first argument", env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, "environment-definable? requires an environment as it's first argument", env)
 		return
 	}
 	if !SymbolP(Cadr(args)) {
-		err = ProcessError("environment-definable? requires a symbol as it's second argument", env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, "environment-definable? requires a symbol as it's second argument", env)
 		return
 	}
 
@@ -267,11 +267,11 @@ func EnvironmentDefinablePImpl(args *Data, env *SymbolTableFrame) (result *Data,
 
 func EnvironmentDefineImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	if !EnvironmentP(Car(args)) {
-		err = ProcessError("environment-define requires an environment as it's first argument", env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, "environment-define requires an environment as it's first argument", env)
 		return
 	}
 	if !SymbolP(Cadr(args)) {
-		err = ProcessError("environment-define requires a symbol as it's second argument", env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, "environment-define requires a symbol as it's second argument", env)
 		return
 	}
 	_, err = EnvironmentValue(Car(args)).BindLocallyTo(Cadr(args), Caddr(args))
@@ -303,12 +303,12 @@ func MakeTopLevelEnvironmentImpl(args *Data, env *SymbolTableFrame) (result *Dat
 	newEnv := NewSymbolTableFrameBelow(Global, name)
 	if Length(args) == 1 {
 		if !ListP(Car(args)) {
-			err = ProcessError("make-top-level-environment expects binding names to be a list", env)
+			err = ProcessTypedError(WrongTypeArgumentCondition, "make-top-level-environment expects binding names to be a list", env)
 			return
 		}
 		for cell := Car(args); NotNilP(cell); cell = Cdr(cell) {
 			if !SymbolP(Car(cell)) {
-				err = ProcessError("make-top-level-environment expects binding names to be symbols", env)
+				err = ProcessTypedError(WrongTypeArgumentCondition, "make-top-level-environment expects binding names to be symbols", env)
 				return
 			}
 			_, err = newEnv.BindLocallyTo(Car(cell), nil)
@@ -318,11 +318,11 @@ func MakeTopLevelEnvironmentImpl(args *Data, env *SymbolTableFrame) (result *Dat
 		}
 	} else if Length(args) == 2 {
 		if !ListP(Car(args)) {
-			err = ProcessError("make-top-level-environment expects binding names to be a list", env)
+			err = ProcessTypedError(WrongTypeArgumentCondition, "make-top-level-environment expects binding names to be a list", env)
 			return
 		}
 		if !ListP(Cadr(args)) {
-			err = ProcessError("make-top-level-environment expects binding values to be a list", env)
+			err = ProcessTypedError(WrongTypeArgumentCondition, "make-top-level-environment expects binding values to be a list", env)
 			return
 		}
 		if Length(Car(args)) != Length(Cadr(args)) {
@@ -331,7 +331,7 @@ func MakeTopLevelEnvironmentImpl(args *Data, env *SymbolTableFrame) (result *Dat
 		}
 		for cell, valcell := Car(args), Cadr(args); NotNilP(cell); cell, valcell = Cdr(cell), Cdr(valcell) {
 			if !SymbolP(Car(cell)) {
-				err = ProcessError("make-top-level-environment expects binding names to be symbols", env)
+				err = ProcessTypedError(WrongTypeArgumentCondition, "make-top-level-environment expects binding names to be symbols", env)
 				return
 			}
 			_, err = newEnv.BindLocallyTo(Car(cell), Car(valcell))
@@ -346,7 +346,7 @@ func MakeTopLevelEnvironmentImpl(args *Data, env *SymbolTableFrame) (result *Dat
 
 func FindTopLevelEnvironmentImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	if !StringP(Car(args)) && !SymbolP(Car(args)) {
-		err = ProcessError("find-top-level-environment expects a symbol or string environment name", env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, "find-top-level-environment expects a symbol or string environment name", env)
 		return
 	}
 	TopLevelEnvironments.Mutex.RLock()
@@ -0,0 +1,240 @@
+// Copyright 2014 SteelSeries ApS.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// This package implements a basic LISP interpretor for embedding in a go program for scripting.
+// This file contains the filesystem primitive functions.
+
+package golisp
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+func RegisterFilePrimitives() {
+	MakeRestrictedPrimitiveFunction("file-exists?", "1", FileExistsImpl)
+	MakeRestrictedPrimitiveFunction("file-directory?", "1", FileDirectoryImpl)
+	MakeRestrictedPrimitiveFunction("file-length", "1", FileLengthImpl)
+	MakeRestrictedPrimitiveFunction("file-modification-time", "1", FileModificationTimeImpl)
+	MakeRestrictedPrimitiveFunction("delete-file", "1", DeleteFileImpl)
+	MakeRestrictedPrimitiveFunction("rename-file", "2", RenameFileImpl)
+	MakeRestrictedPrimitiveFunction("make-directory", "1|2", MakeDirectoryImpl)
+	MakeRestrictedPrimitiveFunction("copy-file", "2", CopyFileImpl)
+	MakeRestrictedPrimitiveFunction("pwd", "0", PwdImpl)
+	MakeRestrictedPrimitiveFunction("set-working-directory!", "1", SetWorkingDirectoryImpl)
+	MakeRestrictedPrimitiveFunction("temporary-file-name", "0|1", TemporaryFileNameImpl)
+
+	MakePrimitiveFunction("path-join", ">=1", PathJoinImpl)
+	MakePrimitiveFunction("path-basename", "1", PathBasenameImpl)
+	MakePrimitiveFunction("path-directory", "1", PathDirectoryImpl)
+	MakePrimitiveFunction("path-extension", "1", PathExtensionImpl)
+}
+
+func filenameArg(name string, d *Data, env *SymbolTableFrame) (filename string, err error) {
+	if !StringP(d) {
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("%s expects a string filename, but was %s", name, String(d)), env)
+		return
+	}
+	return StringValue(d), nil
+}
+
+func FileExistsImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	filename, err := filenameArg("file-exists?", Car(args), env)
+	if err != nil {
+		return
+	}
+
+	_, statErr := os.Stat(filename)
+	return BooleanWithValue(statErr == nil), nil
+}
+
+func FileDirectoryImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	filename, err := filenameArg("file-directory?", Car(args), env)
+	if err != nil {
+		return
+	}
+
+	info, statErr := os.Stat(filename)
+	return BooleanWithValue(statErr == nil && info.IsDir()), nil
+}
+
+func FileLengthImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	filename, err := filenameArg("file-length", Car(args), env)
+	if err != nil {
+		return
+	}
+
+	info, err := os.Stat(filename)
+	if err != nil {
+		return
+	}
+	return IntegerWithValue(info.Size()), nil
+}
+
+// The modification time is in seconds since the Unix epoch.
+func FileModificationTimeImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	filename, err := filenameArg("file-modification-time", Car(args), env)
+	if err != nil {
+		return
+	}
+
+	info, err := os.Stat(filename)
+	if err != nil {
+		return
+	}
+	return IntegerWithValue(info.ModTime().Unix()), nil
+}
+
+func DeleteFileImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	filename, err := filenameArg("delete-file", Car(args), env)
+	if err != nil {
+		return
+	}
+
+	err = os.Remove(filename)
+	return
+}
+
+func RenameFileImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	from, err := filenameArg("rename-file", Car(args), env)
+	if err != nil {
+		return
+	}
+	to, err := filenameArg("rename-file", Cadr(args), env)
+	if err != nil {
+		return
+	}
+
+	err = os.Rename(from, to)
+	return
+}
+
+// Missing parent directories are made too when the second argument is true.
+func MakeDirectoryImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	dirname, err := filenameArg("make-directory", Car(args), env)
+	if err != nil {
+		return
+	}
+
+	if Length(args) == 2 && BooleanValue(Cadr(args)) {
+		err = os.MkdirAll(dirname, 0777)
+	} else {
+		err = os.Mkdir(dirname, 0777)
+	}
+	return
+}
+
+func CopyFileImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	from, err := filenameArg("copy-file", Car(args), env)
+	if err != nil {
+		return
+	}
+	to, err := filenameArg("copy-file", Cadr(args), env)
+	if err != nil {
+		return
+	}
+
+	source, err := os.Open(from)
+	if err != nil {
+		return
+	}
+	defer source.Close()
+
+	info, err := source.Stat()
+	if err != nil {
+		return
+	}
+	destination, err := os.OpenFile(to, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, info.Mode().Perm())
+	if err != nil {
+		return
+	}
+
+	_, err = io.Copy(destination, source)
+	closeErr := destination.Close()
+	if err == nil {
+		err = closeErr
+	}
+	return
+}
+
+func PwdImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	dir, err := os.Getwd()
+	if err != nil {
+		return
+	}
+	return StringWithValue(dir), nil
+}
+
+func SetWorkingDirectoryImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	dirname, err := filenameArg("set-working-directory!", Car(args), env)
+	if err != nil {
+		return
+	}
+
+	err = os.Chdir(dirname)
+	if err != nil {
+		return
+	}
+	return PwdImpl(nil, env)
+}
+
+// Creates an empty file in the temporary directory, so that the name can't
+// be taken by anything else, and returns its name.
+func TemporaryFileNameImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	prefix := "golisp"
+	if Length(args) == 1 {
+		prefix, err = filenameArg("temporary-file-name", Car(args), env)
+		if err != nil {
+			return
+		}
+	}
+
+	f, err := ioutil.TempFile("", prefix)
+	if err != nil {
+		return
+	}
+	f.Close()
+	return StringWithValue(f.Name()), nil
+}
+
+func PathJoinImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	parts := make([]string, 0, Length(args))
+	for c := args; NotNilP(c); c = Cdr(c) {
+		var part string
+		part, err = filenameArg("path-join", Car(c), env)
+		if err != nil {
+			return
+		}
+		parts = append(parts, part)
+	}
+	return StringWithValue(filepath.Join(parts...)), nil
+}
+
+func PathBasenameImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	path, err := filenameArg("path-basename", Car(args), env)
+	if err != nil {
+		return
+	}
+	return StringWithValue(filepath.Base(path)), nil
+}
+
+func PathDirectoryImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	path, err := filenameArg("path-directory", Car(args), env)
+	if err != nil {
+		return
+	}
+	return StringWithValue(filepath.Dir(path)), nil
+}
+
+// The extension includes its leading dot, and is "" when there is none.
+func PathExtensionImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	path, err := filenameArg("path-extension", Car(args), env)
+	if err != nil {
+		return
+	}
+	return StringWithValue(filepath.Ext(path)), nil
+}
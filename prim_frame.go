@@ -39,7 +39,7 @@ func MakeFrameImpl(args *Data, env *SymbolTableFrame) (result *Data, err error)
 	for c := args; NotNilP(c); c = Cddr(c) {
 		k := Car(c)
 		if !NakedP(k) {
-			err = ProcessError(fmt.Sprintf("Frame keys must be naked symbols, but was given %s.", String(k)), env)
+			err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("Frame keys must be naked symbols, but was given %s.", String(k)), env)
 			return
 		}
 		v := Cadr(c)
@@ -51,13 +51,13 @@ func MakeFrameImpl(args *Data, env *SymbolTableFrame) (result *Data, err error)
 func HasSlotImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	f := Car(args)
 	if !FrameP(f) {
-		err = ProcessError(fmt.Sprintf("has-slot? requires a frame as it's first argument, but was given %s.", String(f)), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("has-slot? requires a frame as it's first argument, but was given %s.", String(f)), env)
 		return
 	}
 
 	k := Cadr(args)
 	if !NakedP(k) {
-		err = ProcessError(fmt.Sprintf("has-slot? requires a naked symbol as it's second argument, but was given %s.", String(k)), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("has-slot? requires a naked symbol as it's second argument, but was given %s.", String(k)), env)
 		return
 	}
 
@@ -67,7 +67,7 @@ func HasSlotImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 func GetSlotImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	f := Car(args)
 	if !FrameP(f) {
-		err = ProcessError(fmt.Sprintf("get-slot requires a frame as it's first argument, but was given %s.", String(f)), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("get-slot requires a frame as it's first argument, but was given %s.", String(f)), env)
 		return
 	}
 
@@ -78,12 +78,12 @@ func GetSlotImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 
 	k := Cadr(args)
 	if !NakedP(k) {
-		err = ProcessError(fmt.Sprintf("get-slot requires a naked symbol as it's second argument, but was given %s.", String(k)), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("get-slot requires a naked symbol as it's second argument, but was given %s.", String(k)), env)
 		return
 	}
 
 	if !FrameValue(f).HasSlot(StringValue(k)) {
-		err = ProcessError(fmt.Sprintf("get-slot requires an existing slot, but was given %s.", String(k)), env)
+		err = ProcessTypedError(BadRangeArgumentCondition, fmt.Sprintf("get-slot requires an existing slot, but was given %s.", String(k)), env)
 		return
 	}
 
@@ -93,13 +93,13 @@ func GetSlotImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 func GetSlotOrNilImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	f := Car(args)
 	if !FrameP(f) {
-		err = ProcessError(fmt.Sprintf("get-slot-or-nil requires a frame as it's first argument, but was given %s.", String(f)), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("get-slot-or-nil requires a frame as it's first argument, but was given %s.", String(f)), env)
 		return
 	}
 
 	k := Cadr(args)
 	if !NakedP(k) {
-		err = ProcessError(fmt.Sprintf("get-slot-or-nil requires a naked symbol as it's second argument, but was given %s.", String(k)), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("get-slot-or-nil requires a naked symbol as it's second argument, but was given %s.", String(k)), env)
 		return
 	}
 
@@ -114,13 +114,13 @@ func RemoveSlotImpl(args *Data, env *SymbolTableFrame) (result *Data, err error)
 	}
 
 	if !FrameP(f) {
-		err = ProcessError(fmt.Sprintf("remove-slot! requires a frame as it's first argument, but was given %s.", String(f)), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("remove-slot! requires a frame as it's first argument, but was given %s.", String(f)), env)
 		return
 	}
 
 	k := Cadr(args)
 	if !NakedP(k) {
-		err = ProcessError(fmt.Sprintf("remove-slot! requires a naked symbol as it's second argument, but was given %s.", String(k)), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("remove-slot! requires a naked symbol as it's second argument, but was given %s.", String(k)), env)
 		return
 	}
 
@@ -130,13 +130,13 @@ func RemoveSlotImpl(args *Data, env *SymbolTableFrame) (result *Data, err error)
 func SetSlotImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	f := Car(args)
 	if !FrameP(f) {
-		err = ProcessError(fmt.Sprintf("set-slot! requires a frame as it's first argument, but was given %s.", String(f)), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("set-slot! requires a frame as it's first argument, but was given %s.", String(f)), env)
 		return
 	}
 
 	k := Cadr(args)
 	if !NakedP(k) {
-		err = ProcessError(fmt.Sprintf("set-slot! requires a naked symbol as it's second argument, but was given %s.", String(k)), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("set-slot! requires a naked symbol as it's second argument, but was given %s.", String(k)), env)
 		return
 	}
 
@@ -148,24 +148,24 @@ func SetSlotImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 func SendImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	f := Car(args)
 	if !FrameP(f) {
-		err = ProcessError(fmt.Sprintf("send requires a frame as it's first argument, but was given %s.", String(f)), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("send requires a frame as it's first argument, but was given %s.", String(f)), env)
 		return
 	}
 
 	k := Cadr(args)
 	if !NakedP(k) {
-		err = ProcessError(fmt.Sprintf("send requires a naked symbol as it's second argument, but was given %s.", String(k)), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("send requires a naked symbol as it's second argument, but was given %s.", String(k)), env)
 		return
 	}
 
 	if !FrameValue(f).HasSlot(StringValue(k)) {
-		err = ProcessError(fmt.Sprintf("send requires an existing slot, but was given %s.", String(k)), env)
+		err = ProcessTypedError(BadRangeArgumentCondition, fmt.Sprintf("send requires an existing slot, but was given %s.", String(k)), env)
 		return
 	}
 
 	fun := FrameValue(f).Get(StringValue(k))
 	if !FunctionP(fun) {
-		err = ProcessError(fmt.Sprintf("send requires a function slot, but was given a slot containing a %s.", TypeName(TypeOf(fun))), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("send requires a function slot, but was given a slot containing a %s.", TypeName(TypeOf(fun))), env)
 		return
 	}
 
@@ -199,13 +199,13 @@ func SendSuperImpl(args *Data, env *SymbolTableFrame) (result *Data, err error)
 
 	selector := Car(args)
 	if !NakedP(selector) {
-		err = ProcessError(fmt.Sprintf("Selector must be a naked symbol but was %s.", TypeName(TypeOf(selector))), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("Selector must be a naked symbol but was %s.", TypeName(TypeOf(selector))), env)
 		return
 	}
 
 	fun := getSuperFunction(StringValue(selector), env)
 	if fun == nil || !FunctionP(fun) {
-		err = ProcessError(fmt.Sprintf("Message sent must select a function slot but was %s.", TypeName(TypeOf(fun))), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("Message sent must select a function slot but was %s.", TypeName(TypeOf(fun))), env)
 		return
 	}
 
@@ -224,7 +224,7 @@ func ApplySlotImpl(args *Data, env *SymbolTableFrame) (result *Data, err error)
 		return
 	}
 	if !FrameP(f) {
-		err = ProcessError(fmt.Sprintf("apply-slot requires a frame as it's first argument, but was given %s.", String(f)), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("apply-slot requires a frame as it's first argument, but was given %s.", String(f)), env)
 		return
 	}
 
@@ -233,18 +233,18 @@ func ApplySlotImpl(args *Data, env *SymbolTableFrame) (result *Data, err error)
 		return
 	}
 	if !NakedP(k) {
-		err = ProcessError(fmt.Sprintf("apply-slot requires a naked symbol as it's second argument, but was given %s.", String(k)), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("apply-slot requires a naked symbol as it's second argument, but was given %s.", String(k)), env)
 		return
 	}
 
 	if !FrameValue(f).HasSlot(StringValue(k)) {
-		err = ProcessError(fmt.Sprintf("apply-slot requires an existing slot, but was given %s.", String(k)), env)
+		err = ProcessTypedError(BadRangeArgumentCondition, fmt.Sprintf("apply-slot requires an existing slot, but was given %s.", String(k)), env)
 		return
 	}
 
 	fun := FrameValue(f).Get(StringValue(k))
 	if !FunctionP(fun) {
-		err = ProcessError(fmt.Sprintf("apply-slot requires a function slot, but was given a slot containing a %s.", TypeName(TypeOf(fun))), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("apply-slot requires a function slot, but was given a slot containing a %s.", TypeName(TypeOf(fun))), env)
 		return
 	}
 
@@ -267,7 +267,7 @@ func ApplySlotImpl(args *Data, env *SymbolTableFrame) (result *Data, err error)
 			argList = ary[0]
 		}
 	} else {
-		err = ProcessError("The last argument to apply must be a list", env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, "The last argument to apply must be a list", env)
 		return
 	}
 
@@ -285,13 +285,13 @@ func ApplySlotSuperImpl(args *Data, env *SymbolTableFrame) (result *Data, err er
 		return
 	}
 	if !NakedP(selector) {
-		err = ProcessError(fmt.Sprintf("Selector must be a naked symbol but was %s.", TypeName(TypeOf(selector))), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("Selector must be a naked symbol but was %s.", TypeName(TypeOf(selector))), env)
 		return
 	}
 
 	fun := getSuperFunction(StringValue(selector), env)
 	if fun == nil || !FunctionP(fun) {
-		err = ProcessError(fmt.Sprintf("Message sent must select a function slot but was %s.", TypeName(TypeOf(fun))), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("Message sent must select a function slot but was %s.", TypeName(TypeOf(fun))), env)
 		return
 	}
 
@@ -314,7 +314,7 @@ func ApplySlotSuperImpl(args *Data, env *SymbolTableFrame) (result *Data, err er
 			argList = ary[0]
 		}
 	} else {
-		err = ProcessError("The last argument to apply must be a list", env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, "The last argument to apply must be a list", env)
 		return
 	}
 
@@ -329,7 +329,7 @@ func ApplySlotSuperImpl(args *Data, env *SymbolTableFrame) (result *Data, err er
 func CloneImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	f := Car(args)
 	if !FrameP(f) {
-		err = ProcessError(fmt.Sprintf("clone requires a frame as it's argument, but was given %s.", String(f)), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("clone requires a frame as it's argument, but was given %s.", String(f)), env)
 		return
 	}
 
@@ -339,7 +339,7 @@ func CloneImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 func JsonToLispImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	j := Car(args)
 	if !StringP(j) {
-		err = ProcessError(fmt.Sprintf("json->lisp requires a string as it's argument, but was given %s.", String(j)), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("json->lisp requires a string as it's argument, but was given %s.", String(j)), env)
 		return
 	}
 
@@ -355,7 +355,7 @@ func LispToJsonImpl(args *Data, env *SymbolTableFrame) (result *Data, err error)
 func FrameKeysImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	f := Car(args)
 	if !FrameP(f) {
-		err = ProcessError(fmt.Sprintf("frame-keys requires a frame as it's argument, but was given %s.", String(f)), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("frame-keys requires a frame as it's argument, but was given %s.", String(f)), env)
 		return
 	}
 
@@ -365,7 +365,7 @@ func FrameKeysImpl(args *Data, env *SymbolTableFrame) (result *Data, err error)
 func FrameValuesImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	f := Car(args)
 	if !FrameP(f) {
-		err = ProcessError(fmt.Sprintf("frame-values requires a frame as it's argument, but was given %s.", String(f)), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("frame-values requires a frame as it's argument, but was given %s.", String(f)), env)
 		return
 	}
 
@@ -0,0 +1,337 @@
+// Copyright 2014 SteelSeries ApS.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// This package implements a basic LISP interpretor for embedding in a go program for scripting.
+// This file contains the hash table primitive functions.
+
+package golisp
+
+import (
+	"fmt"
+)
+
+func RegisterHashTablePrimitives() {
+	MakePrimitiveFunction("make-equal-hash-table", "0|1", MakeEqualHashTableImpl)
+	MakePrimitiveFunction("make-eqv-hash-table", "0|1", MakeEqvHashTableImpl)
+	MakePrimitiveFunction("make-string-hash-table", "0|1", MakeStringHashTableImpl)
+	MakePrimitiveFunction("hash-table?", "1", IsHashTableImpl)
+	MakePrimitiveFunction("hash-table-ref", "2|3|4", HashTableRefImpl)
+	MakePrimitiveFunction("hash-table-ref/default", "3", HashTableRefDefaultImpl)
+	MakePrimitiveFunction("hash-table-set!", "3", HashTableSetImpl)
+	MakePrimitiveFunction("hash-table-delete!", "2", HashTableDeleteImpl)
+	MakePrimitiveFunction("hash-table-contains?", "2", HashTableContainsImpl)
+	MakePrimitiveFunction("hash-table-exists?", "2", HashTableContainsImpl)
+	MakePrimitiveFunction("hash-table-update!", "3|4", HashTableUpdateImpl)
+	MakePrimitiveFunction("hash-table-update!/default", "4", HashTableUpdateDefaultImpl)
+	MakePrimitiveFunction("hash-table-walk", "2", HashTableWalkImpl)
+	MakePrimitiveFunction("hash-table->alist", "1", HashTableToAlistImpl)
+	MakePrimitiveFunction("hash-table-keys", "1", HashTableKeysImpl)
+	MakePrimitiveFunction("hash-table-values", "1", HashTableValuesImpl)
+	MakePrimitiveFunction("hash-table-count", "1", HashTableCountImpl)
+	MakePrimitiveFunction("hash-table-size", "1", HashTableCountImpl)
+	MakePrimitiveFunction("hash-table-copy", "1", HashTableCopyImpl)
+	MakePrimitiveFunction("hash-table-clear!", "1", HashTableClearImpl)
+}
+
+func makeHashTable(name string, kind int, args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	size := 0
+	if Length(args) == 1 {
+		if !IntegerP(Car(args)) || IntegerValue(Car(args)) < 0 {
+			err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("%s requires a non-negative integer size, but was given %s.", name, String(Car(args))), env)
+			return
+		}
+		size = int(IntegerValue(Car(args)))
+	}
+	return HashTableWithValue(MakeHashTable(kind, size)), nil
+}
+
+func MakeEqualHashTableImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	return makeHashTable("make-equal-hash-table", EqualHashTable, args, env)
+}
+
+func MakeEqvHashTableImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	return makeHashTable("make-eqv-hash-table", EqvHashTable, args, env)
+}
+
+func MakeStringHashTableImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	return makeHashTable("make-string-hash-table", StringHashTable, args, env)
+}
+
+func IsHashTableImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	return BooleanWithValue(HashTableP(Car(args))), nil
+}
+
+func hashTableArg(name string, args *Data, env *SymbolTableFrame) (table *HashTable, err error) {
+	if !HashTableP(Car(args)) {
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("%s requires a hash table as it's first argument, but was given %s.", name, String(Car(args))), env)
+		return
+	}
+	return HashTableValue(Car(args)), nil
+}
+
+// The table and key arguments of the primitives that look up a key.
+func hashTableAndKeyArgs(name string, args *Data, env *SymbolTableFrame) (table *HashTable, key *Data, err error) {
+	table, err = hashTableArg(name, args, env)
+	if err != nil {
+		return
+	}
+	key = Cadr(args)
+	if !table.AcceptsKey(key) {
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("%s requires a string key for a string hash table, but was given %s.", name, String(key)), env)
+	}
+	return
+}
+
+func hashTableFunctionArg(name string, position string, f *Data, env *SymbolTableFrame) (err error) {
+	if !FunctionOrPrimitiveP(f) {
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("%s requires a function as it's %s argument, but was given %s.", name, position, String(f)), env)
+	}
+	return
+}
+
+// Looks key up, calling fail when it is missing. Without fail a missing key
+// is an error.
+func hashTableLookup(name string, table *HashTable, key *Data, fail *Data, env *SymbolTableFrame) (value *Data, err error) {
+	value, found := table.Get(key)
+	if found {
+		return
+	}
+	if fail == nil {
+		err = ProcessTypedError(BadRangeArgumentCondition, fmt.Sprintf("%s could not find the key %s.", name, String(key)), env)
+		return
+	}
+	return ApplyWithoutEval(fail, nil, env)
+}
+
+func HashTableRefImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	table, key, err := hashTableAndKeyArgs("hash-table-ref", args, env)
+	if err != nil {
+		return
+	}
+
+	var fail, succeed *Data
+	if Length(args) > 2 {
+		fail = Caddr(args)
+		err = hashTableFunctionArg("hash-table-ref", "third", fail, env)
+		if err != nil {
+			return
+		}
+	}
+	if Length(args) > 3 {
+		succeed = Fourth(args)
+		err = hashTableFunctionArg("hash-table-ref", "fourth", succeed, env)
+		if err != nil {
+			return
+		}
+	}
+
+	value, found := table.Get(key)
+	if !found {
+		return hashTableLookup("hash-table-ref", table, key, fail, env)
+	}
+	if succeed != nil {
+		return ApplyWithoutEval(succeed, InternalMakeList(value), env)
+	}
+	return value, nil
+}
+
+func HashTableRefDefaultImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	table, key, err := hashTableAndKeyArgs("hash-table-ref/default", args, env)
+	if err != nil {
+		return
+	}
+
+	value, found := table.Get(key)
+	if !found {
+		return Caddr(args), nil
+	}
+	return value, nil
+}
+
+func HashTableSetImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	table, key, err := hashTableAndKeyArgs("hash-table-set!", args, env)
+	if err != nil {
+		return
+	}
+
+	value := Caddr(args)
+	table.Set(key, value)
+	return value, nil
+}
+
+func HashTableDeleteImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	table, key, err := hashTableAndKeyArgs("hash-table-delete!", args, env)
+	if err != nil {
+		return
+	}
+
+	return BooleanWithValue(table.Delete(key)), nil
+}
+
+func HashTableContainsImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	table, key, err := hashTableAndKeyArgs("hash-table-contains?", args, env)
+	if err != nil {
+		return
+	}
+
+	_, found := table.Get(key)
+	return BooleanWithValue(found), nil
+}
+
+// The table is not locked while f runs, so f is free to use it.
+func hashTableUpdate(name string, table *HashTable, key *Data, f *Data, fail *Data, env *SymbolTableFrame) (result *Data, err error) {
+	value, err := hashTableLookup(name, table, key, fail, env)
+	if err != nil {
+		return
+	}
+
+	result, err = ApplyWithoutEval(f, InternalMakeList(value), env)
+	if err != nil {
+		return
+	}
+
+	table.Set(key, result)
+	return
+}
+
+func HashTableUpdateImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	table, key, err := hashTableAndKeyArgs("hash-table-update!", args, env)
+	if err != nil {
+		return
+	}
+
+	f := Caddr(args)
+	err = hashTableFunctionArg("hash-table-update!", "third", f, env)
+	if err != nil {
+		return
+	}
+
+	var fail *Data
+	if Length(args) == 4 {
+		fail = Fourth(args)
+		err = hashTableFunctionArg("hash-table-update!", "fourth", fail, env)
+		if err != nil {
+			return
+		}
+	}
+
+	return hashTableUpdate("hash-table-update!", table, key, f, fail, env)
+}
+
+func HashTableUpdateDefaultImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	table, key, err := hashTableAndKeyArgs("hash-table-update!/default", args, env)
+	if err != nil {
+		return
+	}
+
+	f := Caddr(args)
+	err = hashTableFunctionArg("hash-table-update!/default", "third", f, env)
+	if err != nil {
+		return
+	}
+
+	value, found := table.Get(key)
+	if !found {
+		value = Fourth(args)
+	}
+
+	result, err = ApplyWithoutEval(f, InternalMakeList(value), env)
+	if err != nil {
+		return
+	}
+
+	table.Set(key, result)
+	return
+}
+
+func HashTableWalkImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	table, err := hashTableArg("hash-table-walk", args, env)
+	if err != nil {
+		return
+	}
+
+	f := Cadr(args)
+	err = hashTableFunctionArg("hash-table-walk", "second", f, env)
+	if err != nil {
+		return
+	}
+
+	for _, entry := range table.Entries() {
+		_, err = ApplyWithoutEval(f, InternalMakeList(entry.Key, entry.Value), env)
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+func HashTableToAlistImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	table, err := hashTableArg("hash-table->alist", args, env)
+	if err != nil {
+		return
+	}
+
+	entries := table.Entries()
+	pairs := make([]*Data, 0, len(entries))
+	for _, entry := range entries {
+		pairs = append(pairs, Cons(entry.Key, entry.Value))
+	}
+	return ArrayToList(pairs), nil
+}
+
+func HashTableKeysImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	table, err := hashTableArg("hash-table-keys", args, env)
+	if err != nil {
+		return
+	}
+
+	entries := table.Entries()
+	keys := make([]*Data, 0, len(entries))
+	for _, entry := range entries {
+		keys = append(keys, entry.Key)
+	}
+	return ArrayToList(keys), nil
+}
+
+func HashTableValuesImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	table, err := hashTableArg("hash-table-values", args, env)
+	if err != nil {
+		return
+	}
+
+	entries := table.Entries()
+	values := make([]*Data, 0, len(entries))
+	for _, entry := range entries {
+		values = append(values, entry.Value)
+	}
+	return ArrayToList(values), nil
+}
+
+func HashTableCountImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	table, err := hashTableArg("hash-table-count", args, env)
+	if err != nil {
+		return
+	}
+
+	return IntegerWithValue(int64(table.Size())), nil
+}
+
+func HashTableCopyImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	table, err := hashTableArg("hash-table-copy", args, env)
+	if err != nil {
+		return
+	}
+
+	return HashTableWithValue(table.Copy()), nil
+}
+
+func HashTableClearImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	table, err := hashTableArg("hash-table-clear!", args, env)
+	if err != nil {
+		return
+	}
+
+	table.Clear()
+	return
+}
@@ -11,9 +11,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"strconv"
 	"strings"
-	"unicode"
+	"unsafe"
 )
 
 func RegisterIOPrimitives() {
@@ -21,12 +20,27 @@ func RegisterIOPrimitives() {
 	MakeRestrictedPrimitiveFunction("open-output-file", "1|2", OpenOutputFileImpl)
 	MakeRestrictedPrimitiveFunction("close-port", "1", ClosePortImpl)
 	MakeRestrictedPrimitiveFunction("write-bytes", "2", WriteBytesImpl)
+	MakeRestrictedPrimitiveFunction("with-input-from-file", "2", WithInputFromFileImpl)
+	MakeRestrictedPrimitiveFunction("with-output-to-file", "2", WithOutputToFileImpl)
+	MakePrimitiveFunction("current-output-port", "0", CurrentOutputPortImpl)
+	MakePrimitiveFunction("current-input-port", "0", CurrentInputPortImpl)
 
 	MakePrimitiveFunction("write-string", "1|2", WriteStringImpl)
 	MakePrimitiveFunction("newline", "0|1", NewlineImpl)
 	MakePrimitiveFunction("write", "1|2", WriteImpl)
-	MakePrimitiveFunction("read", "1", ReadImpl)
+	MakePrimitiveFunction("read", "0|1", ReadImpl)
 	MakePrimitiveFunction("eof-object?", "1", EofObjectImpl)
+	MakePrimitiveFunction("read-line", "0|1", ReadLineImpl)
+	MakePrimitiveFunction("read-char", "0|1", ReadCharImpl)
+	MakePrimitiveFunction("peek-char", "0|1", PeekCharImpl)
+	MakePrimitiveFunction("read-string", "1|2", ReadStringImpl)
+	MakePrimitiveFunction("char-ready?", "0|1", CharReadyImpl)
+	MakePrimitiveFunction("read-bytes", "1|2", ReadBytesImpl)
+
+	MakePrimitiveFunction("open-input-string", "1", OpenInputStringImpl)
+	MakePrimitiveFunction("open-output-string", "0", OpenOutputStringImpl)
+	MakePrimitiveFunction("get-output-string", "1", GetOutputStringImpl)
+	MakePrimitiveFunction("with-output-to-string", "1", WithOutputToStringImpl)
 
 	MakePrimitiveFunction("list-directory", "1|2", ListDirectoryImpl)
 
@@ -36,7 +50,7 @@ func RegisterIOPrimitives() {
 func OpenOutputFileImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	filename := Car(args)
 	if !StringP(filename) {
-		err = ProcessError("open-output-port expects its argument to be a string", env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, "open-output-port expects its argument to be a string", env)
 		return
 	}
 
@@ -55,7 +69,7 @@ func OpenOutputFileImpl(args *Data, env *SymbolTableFrame) (result *Data, err er
 func OpenInputFileImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	filename := Car(args)
 	if !StringP(filename) {
-		err = ProcessError("open-input-port expects its argument to be a string", env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, "open-input-port expects its argument to be a string", env)
 		return
 	}
 
@@ -69,46 +83,94 @@ func OpenInputFileImpl(args *Data, env *SymbolTableFrame) (result *Data, err err
 func ClosePortImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	p := Car(args)
 	if !PortP(p) {
-		err = ProcessError("close-port expects its argument be a port", env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, "close-port expects its argument be a port", env)
 		return
 	}
 
-	(*os.File)(PortValue(p)).Close()
+	PortValue(p).Close()
 	return
 
 }
 
+func OpenInputStringImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	str := Car(args)
+	if !StringP(str) {
+		err = ProcessTypedError(WrongTypeArgumentCondition, "open-input-string expects its argument to be a string", env)
+		return
+	}
+	return PortWithReader("string", strings.NewReader(StringValue(str))), nil
+}
+
+func OpenOutputStringImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	return StringOutputPort(), nil
+}
+
+func GetOutputStringImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	p := Car(args)
+	if !PortP(p) {
+		err = ProcessTypedError(WrongTypeArgumentCondition, "get-output-string expects its argument be a port", env)
+		return
+	}
+
+	str, err := PortValue(p).OutputString()
+	if err != nil {
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("get-output-string expects a string output port, but was %s", String(p)), env)
+		return
+	}
+	return StringWithValue(str), nil
+}
+
+func WithOutputToStringImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	thunk := Car(args)
+	if !FunctionOrPrimitiveP(thunk) {
+		err = ProcessTypedError(WrongTypeArgumentCondition, "with-output-to-string expects its argument be a function", env)
+		return
+	}
+
+	port := StringOutputPort()
+	_, err = withCurrentPort(port, true, thunk, env)
+	if err != nil {
+		return
+	}
+
+	str, err := PortValue(port).OutputString()
+	if err != nil {
+		return
+	}
+	return StringWithValue(str), nil
+}
+
 func WriteBytesImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	bytes := Car(args)
 	if !ObjectP(bytes) || ObjectType(bytes) != "[]byte" {
-		err = ProcessError("write expects its first argument to be a bytearray", env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, "write expects its first argument to be a bytearray", env)
 		return
 	}
 
 	p := Cadr(args)
 	if !PortP(p) {
-		err = ProcessError("write expects its second argument be a port", env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, "write expects its second argument be a port", env)
 		return
 	}
 
-	_, err = (*os.File)(PortValue(p)).Write(*(*[]byte)(ObjectValue(bytes)))
+	_, err = PortValue(p).Write(*(*[]byte)(ObjectValue(bytes)))
 	return
 }
 
 func WriteStringImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	str := Car(args)
 	if !StringP(str) {
-		err = ProcessError("write-string expects its first argument to be a string", env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, "write-string expects its first argument to be a string", env)
 		return
 	}
 
-	var port *os.File
+	var port *Port
 	if Length(args) == 1 {
-		port = os.Stdout
+		port = PortValue(CurrentOutputPort(env))
 	} else {
 		p := Cadr(args)
 		if !PortP(p) {
-			err = ProcessError("write-string expects its second argument be a port", env)
+			err = ProcessTypedError(WrongTypeArgumentCondition, "write-string expects its second argument be a port", env)
 			return
 		}
 		port = PortValue(p)
@@ -119,14 +181,14 @@ func WriteStringImpl(args *Data, env *SymbolTableFrame) (result *Data, err error
 }
 
 func WriteImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
-	var port *os.File
+	var port *Port
 
 	if Length(args) == 1 {
-		port = os.Stdout
+		port = PortValue(CurrentOutputPort(env))
 	} else {
 		p := Cadr(args)
 		if !PortP(p) {
-			err = ProcessError("write expects its second argument be a port", env)
+			err = ProcessTypedError(WrongTypeArgumentCondition, "write expects its second argument be a port", env)
 			return
 		}
 		port = PortValue(p)
@@ -137,14 +199,14 @@ func WriteImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 }
 
 func NewlineImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
-	var port *os.File
+	var port *Port
 
 	if Length(args) == 0 {
-		port = os.Stdout
+		port = PortValue(CurrentOutputPort(env))
 	} else {
 		p := Car(args)
 		if !PortP(p) {
-			err = ProcessError("newline expects its argument be a port", env)
+			err = ProcessTypedError(WrongTypeArgumentCondition, "newline expects its argument be a port", env)
 			return
 		}
 		port = PortValue(p)
@@ -155,23 +217,179 @@ func NewlineImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 }
 
 func ReadImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
-	var port *os.File
+	var port *Port
 
 	if Length(args) == 0 {
-		port = os.Stdin
+		port = PortValue(CurrentInputPort(env))
 	} else {
 		p := Car(args)
 		if !PortP(p) {
-			err = ProcessError("read expects its argument be a port", env)
+			err = ProcessTypedError(WrongTypeArgumentCondition, "read expects its argument be a port", env)
 			return
 		}
 		port = PortValue(p)
 	}
 
-	result, err = ParseObjectFromFileInEnv(port, env)
+	result, err = port.ReadObject()
 	return
 }
 
+// The input port given as the optional argument at index, or the current
+// input port.
+func inputPortArg(name string, args *Data, index int, env *SymbolTableFrame) (port *Port, err error) {
+	if Length(args) <= index {
+		return PortValue(CurrentInputPort(env)), nil
+	}
+	p := Nth(args, index+1)
+	if !PortP(p) {
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("%s expects a port, but was %s", name, String(p)), env)
+		return
+	}
+	return PortValue(p), nil
+}
+
+func ReadLineImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	port, err := inputPortArg("read-line", args, 0, env)
+	if err != nil {
+		return
+	}
+
+	line, eof, err := port.ReadChars(-1, true)
+	if err != nil {
+		return
+	}
+	if eof {
+		return EofObject, nil
+	}
+	return StringWithValue(line), nil
+}
+
+func readCharacter(name string, peek bool, args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	port, err := inputPortArg(name, args, 0, env)
+	if err != nil {
+		return
+	}
+
+	ch, eof, err := port.ReadChar(peek)
+	if err != nil {
+		return
+	}
+	if eof {
+		return EofObject, nil
+	}
+	return CharacterWithValue(ch), nil
+}
+
+func ReadCharImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	return readCharacter("read-char", false, args, env)
+}
+
+func PeekCharImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	return readCharacter("peek-char", true, args, env)
+}
+
+func ReadStringImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	k := Car(args)
+	if !IntegerP(k) || IntegerValue(k) < 0 {
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("read-string expects its first argument to be a non-negative integer, but was %s", String(k)), env)
+		return
+	}
+
+	port, err := inputPortArg("read-string", args, 1, env)
+	if err != nil {
+		return
+	}
+
+	str, eof, err := port.ReadChars(int(IntegerValue(k)), false)
+	if err != nil {
+		return
+	}
+	if eof && IntegerValue(k) > 0 {
+		return EofObject, nil
+	}
+	return StringWithValue(str), nil
+}
+
+func CharReadyImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	port, err := inputPortArg("char-ready?", args, 0, env)
+	if err != nil {
+		return
+	}
+
+	ready, err := port.CharReady()
+	if err != nil {
+		return
+	}
+	return BooleanWithValue(ready), nil
+}
+
+func ReadBytesImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	n := Car(args)
+	if !IntegerP(n) || IntegerValue(n) < 0 {
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("read-bytes expects its first argument to be a non-negative integer, but was %s", String(n)), env)
+		return
+	}
+
+	port, err := inputPortArg("read-bytes", args, 1, env)
+	if err != nil {
+		return
+	}
+
+	bytes, eof, err := port.ReadBytes(int(IntegerValue(n)))
+	if err != nil {
+		return
+	}
+	if eof {
+		return EofObject, nil
+	}
+	return ObjectWithTypeAndValue("[]byte", unsafe.Pointer(&bytes)), nil
+}
+
+// Calls thunk with a file opened as the current input or output port, closing
+// the file afterwards.
+func withFilePort(name string, output bool, args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	filename := Car(args)
+	if !StringP(filename) {
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("%s expects its first argument to be a string", name), env)
+		return
+	}
+	thunk := Cadr(args)
+	if !FunctionOrPrimitiveP(thunk) {
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("%s expects its second argument be a function", name), env)
+		return
+	}
+
+	var f *os.File
+	if output {
+		f, err = os.OpenFile(StringValue(filename), os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0666)
+	} else {
+		f, err = os.Open(StringValue(filename))
+	}
+	if err != nil {
+		return
+	}
+
+	port := PortWithValue(f)
+	defer PortValue(port).Close()
+	return withCurrentPort(port, output, thunk, env)
+}
+
+func WithInputFromFileImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	return withFilePort("with-input-from-file", false, args, env)
+}
+
+func WithOutputToFileImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	return withFilePort("with-output-to-file", true, args, env)
+}
+
+func CurrentOutputPortImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	return CurrentOutputPort(env), nil
+}
+
+func CurrentInputPortImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	return CurrentInputPort(env), nil
+}
+
 func EofObjectImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	return BooleanWithValue(IsEqual(Car(args), EofObject)), nil
 }
@@ -199,153 +417,26 @@ func ListDirectoryImpl(args *Data, env *SymbolTableFrame) (result *Data, err err
 func FormatImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	destination := Car(args)
 	if !BooleanP(destination) && !PortP(destination) {
-		err = ProcessError(fmt.Sprintf("format expects its second argument be a boolean or port, but was %s", String(destination)), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("format expects its second argument be a boolean or port, but was %s", String(destination)), env)
 		return
 	}
 
 	controlStringObj := Cadr(args)
 	if !StringP(controlStringObj) {
-		err = ProcessError("format expects its second argument be a string", env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, "format expects its second argument be a string", env)
 		return
 	}
 	controlString := StringValue(controlStringObj)
 
-	arguments := Cddr(args)
-
-	numberOfSubstitutions := strings.Count(controlString, "~")
-	parts := make([]string, 0, numberOfSubstitutions*2+1)
-	start := 0
-	var i int
-	var numericArg int
-	var atModifier bool
-	var substitution string
-	var padding string
-	var n int64
-
-	for i < len(controlString) {
-		if controlString[i] == '~' { // start of a substitution
-			parts = append(parts, controlString[start:i])
-			i++
-			start = i
-			for unicode.IsDigit(rune(controlString[i])) {
-				i++
-			}
-			if i == start {
-				if controlString[i] == '#' {
-					numericArg = Length(arguments)
-					i++
-				} else if controlString[i] == 'V' || controlString[i] == 'v' {
-					if IntegerP(Car(arguments)) {
-						numericArg = int(IntegerValue(Car(arguments)))
-						arguments = Cdr(arguments)
-					} else {
-						err = ProcessError(fmt.Sprintf("format encountered a size argument mismatch at index %d", i), env)
-						return
-					}
-					i++
-				} else {
-					numericArg = 0
-				}
-			} else {
-				n, err = strconv.ParseInt(string(controlString[start:i]), 10, 64)
-				if err != nil {
-					return
-				}
-				numericArg = int(n)
-			}
-			if controlString[i] == '@' {
-				atModifier = true
-				i++
-			}
-			switch controlString[i] {
-			case 'A', 'a':
-				substitution = PrintString(Car(arguments))
-				if len(substitution) < numericArg {
-					padding = strings.Repeat(" ", numericArg-len(substitution))
-				} else {
-					padding = ""
-				}
-				if atModifier {
-					parts = append(parts, padding)
-				}
-				parts = append(parts, substitution)
-				if !atModifier {
-					parts = append(parts, padding)
-				}
-				arguments = Cdr(arguments)
-				start = i + 1
-
-			case 'S', 's':
-				substitution = String(Car(arguments))
-				if len(substitution) < numericArg {
-					padding = strings.Repeat(" ", numericArg-len(substitution))
-				} else {
-					padding = ""
-				}
-				if atModifier {
-					parts = append(parts, padding)
-				}
-				parts = append(parts, substitution)
-				if !atModifier {
-					parts = append(parts, padding)
-				}
-				arguments = Cdr(arguments)
-				start = i + 1
-
-			case '%':
-				if numericArg > 0 {
-					parts = append(parts, strings.Repeat("\n", numericArg))
-				} else {
-					parts = append(parts, "\n")
-				}
-				start = i + 1
-
-			case '~':
-				if numericArg > 0 {
-					parts = append(parts, strings.Repeat("~", numericArg))
-				} else {
-					parts = append(parts, "~")
-				}
-				start = i + 1
-
-			case '\n':
-				for i < len(controlString) && unicode.IsSpace(rune(controlString[i])) {
-					i++
-				}
-				if atModifier {
-					parts = append(parts, "\n")
-				}
-				start = i
-				i--
-
-			default:
-				err = ProcessError(fmt.Sprintf("format encountered an unsupported substitution at index %d", i), env)
-				return
-			}
-		}
-		i++
-	}
-
-	if start < len(controlString) {
-		parts = append(parts, controlString[start:i])
-	}
-
-	if i < len(controlString) || !NilP(arguments) {
-		err = ProcessError("number of replacements in the control string and number of arguments must be equal", env)
+	combinedString, err := FormatToString(controlString, Cddr(args), env)
+	if err != nil {
 		return
 	}
 
-	combinedString := strings.Join(parts, "")
-
 	if PortP(destination) {
-		port := PortValue(destination)
-		_, err = port.WriteString(combinedString)
+		_, err = PortValue(destination).WriteString(combinedString)
 	} else if BooleanValue(destination) {
-		// Make sure Stdout exists before writing to it, prevents issues with LDFLAGS="-H windowsgui"
-		stat, statErr := os.Stdout.Stat()
-		if stat != nil && statErr == nil {
-			_, err = os.Stdout.WriteString(combinedString)
-		}
+		_, err = PortValue(CurrentOutputPort(env)).WriteString(combinedString)
 	} else {
 		result = StringWithValue(combinedString)
 	}
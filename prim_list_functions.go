@@ -43,7 +43,7 @@ func intMin(x, y int64) int64 {
 func MapImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	f := First(args)
 	if !FunctionOrPrimitiveP(f) {
-		err = ProcessError(fmt.Sprintf("map needs a function as its first argument, but got %s.", String(f)), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("map needs a function as its first argument, but got %s.", String(f)), env)
 		return
 	}
 
@@ -53,7 +53,7 @@ func MapImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	for a := Cdr(args); NotNilP(a); a = Cdr(a) {
 		col = Car(a)
 		if !ListP(col) {
-			err = ProcessError(fmt.Sprintf("map needs lists as its other arguments, but got %s.", String(col)), env)
+			err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("map needs lists as its other arguments, but got %s.", String(col)), env)
 			return
 		}
 		if NilP(col) || col == nil {
@@ -90,7 +90,7 @@ func MapImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 func ForEachImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	f := First(args)
 	if !FunctionOrPrimitiveP(f) {
-		err = ProcessError(fmt.Sprintf("foreach needs a function as its first argument, but got %s.", String(f)), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("foreach needs a function as its first argument, but got %s.", String(f)), env)
 		return
 	}
 
@@ -100,7 +100,7 @@ func ForEachImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	for a := Cdr(args); NotNilP(a); a = Cdr(a) {
 		col = Car(a)
 		if !ListP(col) {
-			err = ProcessError(fmt.Sprintf("foreach needs lists as its other arguments, but got %s.", String(col)), env)
+			err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("foreach needs lists as its other arguments, but got %s.", String(col)), env)
 			return
 		}
 		collections = append(collections, col)
@@ -131,7 +131,7 @@ func ForEachImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 func AnyImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	f := First(args)
 	if !FunctionOrPrimitiveP(f) {
-		err = ProcessError(fmt.Sprintf("any needs a function as its first argument, but got %s.", String(f)), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("any needs a function as its first argument, but got %s.", String(f)), env)
 		return
 	}
 
@@ -141,7 +141,7 @@ func AnyImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	for a := Cdr(args); NotNilP(a); a = Cdr(a) {
 		col = Car(a)
 		if !ListP(col) {
-			err = ProcessError(fmt.Sprintf("any needs lists as its other arguments, but got %s.", String(col)), env)
+			err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("any needs lists as its other arguments, but got %s.", String(col)), env)
 			return
 		}
 		collections = append(collections, col)
@@ -177,7 +177,7 @@ func AnyImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 func EveryImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	f := First(args)
 	if !FunctionOrPrimitiveP(f) {
-		err = ProcessError(fmt.Sprintf("every needs a function as its first argument, but got %s.", String(f)), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("every needs a function as its first argument, but got %s.", String(f)), env)
 		return
 	}
 
@@ -187,7 +187,7 @@ func EveryImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	for a := Cdr(args); NotNilP(a); a = Cdr(a) {
 		col = Car(a)
 		if !ListP(col) {
-			err = ProcessError(fmt.Sprintf("every needs lists as its other arguments, but got %s.", String(col)), env)
+			err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("every needs lists as its other arguments, but got %s.", String(col)), env)
 			return
 		}
 		collections = append(collections, col)
@@ -247,7 +247,7 @@ func reduceFoldProc(initial *Data, col []*Data, f *Data, env *SymbolTableFrame,
 func ReduceLeftImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	f := First(args)
 	if !FunctionOrPrimitiveP(f) {
-		err = ProcessError("reduce-left needs a function as its first argument", env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, "reduce-left needs a function as its first argument", env)
 		return
 	}
 
@@ -255,7 +255,7 @@ func ReduceLeftImpl(args *Data, env *SymbolTableFrame) (result *Data, err error)
 	col := Third(args)
 
 	if !ListP(col) {
-		err = ProcessError("reduce-left needs a list as its third argument", env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, "reduce-left needs a list as its third argument", env)
 		return
 	}
 
@@ -272,7 +272,7 @@ func ReduceLeftImpl(args *Data, env *SymbolTableFrame) (result *Data, err error)
 func ReduceRightImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	f := First(args)
 	if !FunctionOrPrimitiveP(f) {
-		err = ProcessError("reduce-right needs a function as its first argument", env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, "reduce-right needs a function as its first argument", env)
 		return
 	}
 
@@ -280,7 +280,7 @@ func ReduceRightImpl(args *Data, env *SymbolTableFrame) (result *Data, err error
 	col := Third(args)
 
 	if !ListP(col) {
-		err = ProcessError("reduce-right needs a list as its third argument", env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, "reduce-right needs a list as its third argument", env)
 		return
 	}
 
@@ -297,7 +297,7 @@ func ReduceRightImpl(args *Data, env *SymbolTableFrame) (result *Data, err error
 func FoldLeftImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	f := First(args)
 	if !FunctionOrPrimitiveP(f) {
-		err = ProcessError("fold-left needs a function as its first argument", env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, "fold-left needs a function as its first argument", env)
 		return
 	}
 
@@ -305,7 +305,7 @@ func FoldLeftImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	col := Third(args)
 
 	if !ListP(col) {
-		err = ProcessError("fold-left needs a list as its third argument", env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, "fold-left needs a list as its third argument", env)
 		return
 	}
 
@@ -319,7 +319,7 @@ func FoldLeftImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 func FoldRightImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	f := First(args)
 	if !FunctionOrPrimitiveP(f) {
-		err = ProcessError("fold-right needs a function as its first argument", env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, "fold-right needs a function as its first argument", env)
 		return
 	}
 
@@ -327,7 +327,7 @@ func FoldRightImpl(args *Data, env *SymbolTableFrame) (result *Data, err error)
 	col := Third(args)
 
 	if !ListP(col) {
-		err = ProcessError("fold-right needs a list as its third argument", env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, "fold-right needs a list as its third argument", env)
 		return
 	}
 
@@ -341,13 +341,13 @@ func FoldRightImpl(args *Data, env *SymbolTableFrame) (result *Data, err error)
 func FilterImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	f := First(args)
 	if !FunctionOrPrimitiveP(f) {
-		err = ProcessError(fmt.Sprintf("filter needs a function as its first argument, but got %s.", String(f)), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("filter needs a function as its first argument, but got %s.", String(f)), env)
 		return
 	}
 
 	col := Second(args)
 	if !ListP(col) {
-		err = ProcessError(fmt.Sprintf("filter needs a list as its second argument, but got %s.", String(col)), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("filter needs a list as its second argument, but got %s.", String(col)), env)
 		return
 	}
 
@@ -359,7 +359,7 @@ func FilterImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 			return
 		}
 		if !BooleanP(v) {
-			err = ProcessError("filter needs a predicate function as its first argument.", env)
+			err = ProcessTypedError(WrongTypeArgumentCondition, "filter needs a predicate function as its first argument.", env)
 			return
 		}
 
@@ -374,13 +374,13 @@ func FilterImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 func RemoveImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	f := First(args)
 	if !FunctionOrPrimitiveP(f) {
-		err = ProcessError(fmt.Sprintf("remove needs a function as its first argument, but got %s.", String(f)), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("remove needs a function as its first argument, but got %s.", String(f)), env)
 		return
 	}
 
 	col := Second(args)
 	if !ListP(col) {
-		err = ProcessError(fmt.Sprintf("remove needs a list as its second argument, but got %s.", String(col)), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("remove needs a list as its second argument, but got %s.", String(col)), env)
 		return
 	}
 
@@ -392,7 +392,7 @@ func RemoveImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 			return
 		}
 		if !BooleanP(v) {
-			err = ProcessError("remove needs a predicate function as its first argument.", env)
+			err = ProcessTypedError(WrongTypeArgumentCondition, "remove needs a predicate function as its first argument.", env)
 			return
 		}
 
@@ -421,13 +421,13 @@ func MemqImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 func FindTailImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	f := First(args)
 	if !FunctionOrPrimitiveP(f) {
-		err = ProcessError("find-tail/memp needs a function as its first argument", env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, "find-tail/memp needs a function as its first argument", env)
 		return
 	}
 
 	l := Second(args)
 	if !ListP(l) {
-		err = ProcessError(fmt.Sprintf("find-tail needs a list as its second argument, but got %s.", String(l)), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("find-tail needs a list as its second argument, but got %s.", String(l)), env)
 		return
 	}
 
@@ -436,7 +436,7 @@ func FindTailImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 		found, err = ApplyWithoutEval(f, InternalMakeList(Car(c)), env)
 
 		if !BooleanP(found) {
-			err = ProcessError("find-tail needs a predicate function as its first argument.", env)
+			err = ProcessTypedError(WrongTypeArgumentCondition, "find-tail needs a predicate function as its first argument.", env)
 			return
 		}
 		if BooleanValue(found) {
@@ -450,13 +450,13 @@ func FindTailImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 func FindImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	f := First(args)
 	if !FunctionOrPrimitiveP(f) {
-		err = ProcessError("find needs a function as its first argument", env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, "find needs a function as its first argument", env)
 		return
 	}
 
 	l := Second(args)
 	if !ListP(l) {
-		err = ProcessError(fmt.Sprintf("find needs a list as its second argument, but got %s.", String(l)), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("find needs a list as its second argument, but got %s.", String(l)), env)
 		return
 	}
 
@@ -464,7 +464,7 @@ func FindImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	for c := l; NotNilP(c); c = Cdr(c) {
 		found, err = ApplyWithoutEval(f, InternalMakeList(Car(c)), env)
 		if !BooleanP(found) {
-			err = ProcessError("find needs a predicate function as its first argument.", env)
+			err = ProcessTypedError(WrongTypeArgumentCondition, "find needs a predicate function as its first argument.", env)
 			return
 		}
 		if BooleanValue(found) {
@@ -31,7 +31,7 @@ func RegisterListManipulationPrimitives() {
 func MakeListImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	kVal := Car(args)
 	if !IntegerP(kVal) {
-		err = ProcessError("make-list requires a integer as it's first argument.", env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, "make-list requires a integer as it's first argument.", env)
 		return
 	}
 
@@ -39,7 +39,7 @@ func MakeListImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	var element *Data
 
 	if k < 0 {
-		err = ProcessError("make-list requires a non-negative integer as it's first argument.", env)
+		err = ProcessTypedError(BadRangeArgumentCondition, "make-list requires a non-negative integer as it's first argument.", env)
 		return
 	}
 
@@ -143,7 +143,7 @@ func CopyImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 func partitionBySize(determiner *Data, l *Data, env *SymbolTableFrame) (result *Data, err error) {
 	size := int(IntegerValue(determiner))
 	if size < 1 {
-		err = ProcessError("partition requires a non negative clump size.", env)
+		err = ProcessTypedError(BadRangeArgumentCondition, "partition requires a non negative clump size.", env)
 		return
 	}
 
@@ -190,13 +190,13 @@ func partitionByPredicate(determiner *Data, l *Data, env *SymbolTableFrame) (res
 func PartitionImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	determiner := Car(args)
 	if !IntegerP(determiner) && !FunctionOrPrimitiveP(determiner) {
-		err = ProcessError("partition requires an integer or function as it's first argument.", env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, "partition requires an integer or function as it's first argument.", env)
 		return
 	}
 
 	l := Cadr(args)
 	if !ListP(l) {
-		err = ProcessError("partition requires a list as it's second argument.", env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, "partition requires a list as it's second argument.", env)
 		return
 	}
 
@@ -210,31 +210,31 @@ func PartitionImpl(args *Data, env *SymbolTableFrame) (result *Data, err error)
 func SublistImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	l := Car(args)
 	if !ListP(l) {
-		err = ProcessError("sublist requires a list as it's first argument.", env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, "sublist requires a list as it's first argument.", env)
 		return
 	}
 
 	n := Cadr(args)
 	if !IntegerP(n) {
-		err = ProcessError("sublist requires a number as it's second argument (start).", env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, "sublist requires a number as it's second argument (start).", env)
 		return
 	}
 	first := int(IntegerValue(n))
 
 	if first <= 0 {
-		err = ProcessError("sublist requires positive indecies.", env)
+		err = ProcessTypedError(BadRangeArgumentCondition, "sublist requires positive indecies.", env)
 		return
 	}
 
 	n = Caddr(args)
 	if !IntegerP(n) {
-		err = ProcessError("sublist requires a number as it's third argument (end).", env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, "sublist requires a number as it's third argument (end).", env)
 		return
 	}
 	last := int(IntegerValue(n))
 
 	if last <= 0 {
-		err = ProcessError("sublist requires positive indecies.", env)
+		err = ProcessTypedError(BadRangeArgumentCondition, "sublist requires positive indecies.", env)
 		return
 	}
 
@@ -257,16 +257,9 @@ func SublistImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 
 func sortCompare(a *Data, b *Data, proc *Data, env *SymbolTableFrame) (result bool, err error) {
 	var procRet *Data
-	if FunctionP(proc) {
-		procRet, err = FunctionValue(proc).ApplyWithoutEval(InternalMakeList(a, b), env)
-		if err == nil {
-			result = BooleanValue(procRet)
-		}
-	} else {
-		procRet, err = PrimitiveValue(proc).ApplyWithoutEval(InternalMakeList(a, b), env)
-		if err == nil {
-			result = BooleanValue(procRet)
-		}
+	procRet, err = ApplyWithoutEval(proc, InternalMakeList(a, b), env)
+	if err == nil {
+		result = BooleanValue(procRet)
 	}
 	return
 }
@@ -274,13 +267,13 @@ func sortCompare(a *Data, b *Data, proc *Data, env *SymbolTableFrame) (result bo
 func SortImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	coll := Car(args)
 	if !ListP(coll) {
-		err = ProcessError("sort requires a list as it's first argument.", env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, "sort requires a list as it's first argument.", env)
 		return
 	}
 
 	proc := Cadr(args)
 	if !FunctionOrPrimitiveP(proc) {
-		err = ProcessError("sort requires a function or primitive as it's second argument.", env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, "sort requires a function or primitive as it's second argument.", env)
 		return
 	}
 
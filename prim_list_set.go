@@ -31,7 +31,7 @@ func UnionImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	for a := args; NotNilP(a); a = Cdr(a) {
 		col = Car(a)
 		if !ListP(col) {
-			err = ProcessError(fmt.Sprintf("union needs lists as its arguments, but got %s.", String(col)), env)
+			err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("union needs lists as its arguments, but got %s.", String(col)), env)
 			return
 		}
 		for cell := col; NotNilP(cell); cell = Cdr(cell) {
@@ -48,7 +48,7 @@ func IntersectionImpl(args *Data, env *SymbolTableFrame) (result *Data, err erro
 	firstList := Car(args)
 
 	if !ListP(firstList) {
-		err = ProcessError(fmt.Sprintf("intersection needs lists as its arguments, but got %s.", String(firstList)), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("intersection needs lists as its arguments, but got %s.", String(firstList)), env)
 		return
 	}
 
@@ -56,7 +56,7 @@ func IntersectionImpl(args *Data, env *SymbolTableFrame) (result *Data, err erro
 	for a := Cdr(args); NotNilP(a); a = Cdr(a) {
 		col = Car(a)
 		if !ListP(col) {
-			err = ProcessError(fmt.Sprintf("intersection needs lists as its arguments, but got %s.", String(col)), env)
+			err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("intersection needs lists as its arguments, but got %s.", String(col)), env)
 			return
 		}
 		for cell := result; NotNilP(cell); cell = Cdr(cell) {
@@ -73,7 +73,7 @@ func ComplementImpl(args *Data, env *SymbolTableFrame) (result *Data, err error)
 	firstList := Car(args)
 
 	if !ListP(firstList) {
-		err = ProcessError(fmt.Sprintf("complement needs lists as its arguments, but got %s.", String(firstList)), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("complement needs lists as its arguments, but got %s.", String(firstList)), env)
 		return
 	}
 
@@ -82,7 +82,7 @@ func ComplementImpl(args *Data, env *SymbolTableFrame) (result *Data, err error)
 	for a := Cdr(args); NotNilP(a); a = Cdr(a) {
 		col = Car(a)
 		if !ListP(col) {
-			err = ProcessError(fmt.Sprintf("complement needs lists as its arguments, but got %s.", String(col)), env)
+			err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("complement needs lists as its arguments, but got %s.", String(col)), env)
 			return
 		}
 		for cell := result; NotNilP(cell); cell = Cdr(cell) {
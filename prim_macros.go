@@ -17,6 +17,13 @@ func RegisterMacroPrimitives() {
 	MakeSpecialForm("unquote", "1", UnquoteImpl)
 	MakeSpecialForm("unquote-splicing", "1", UnquoteSplicingImpl)
 	MakeSpecialForm("expand", ">=1", ExpandImpl)
+	MakePrimitiveFunction("macroexpand-1", "1", MacroExpand1Impl)
+	MakePrimitiveFunction("macroexpand", "1", MacroExpandImpl)
+	MakePrimitiveFunction("macroexpand-all", "1", MacroExpandAllImpl)
+	MakeSpecialForm("syntax-rules", ">=1", SyntaxRulesImpl)
+	MakeSpecialForm("define-syntax", "2", DefineSyntaxImpl)
+	MakeSpecialForm("let-syntax", ">=1", LetSyntaxImpl)
+	MakeSpecialForm("letrec-syntax", ">=1", LetrecSyntaxImpl)
 }
 
 func QuoteImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
@@ -28,7 +35,13 @@ func QuoteImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 }
 
 func processQuasiquoted(sexpr *Data, level int, env *SymbolTableFrame) (result *Data, err error) {
-	if !ListP(sexpr) {
+	if VectorP(sexpr) {
+		processed, err := processQuasiquoted(ArrayToList(VectorValue(sexpr)), level, env)
+		if err != nil {
+			return nil, err
+		}
+		return Cons(VectorWithValue(ToArray(Car(processed))), nil), nil
+	} else if !ListP(sexpr) {
 		return Cons(sexpr, nil), nil
 	} else if SymbolP(Car(sexpr)) && StringValue(Car(sexpr)) == "quasiquote" {
 		processed, err := processQuasiquoted(Cadr(sexpr), level+1, env)
@@ -104,12 +117,12 @@ func QuasiquoteImpl(args *Data, env *SymbolTableFrame) (result *Data, err error)
 }
 
 func UnquoteImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
-	err = ProcessError("unquote should not be used outside of a quasiquoted expression.", env)
+	err = ProcessTypedError(SyntaxErrorCondition, "unquote should not be used outside of a quasiquoted expression.", env)
 	return
 }
 
 func UnquoteSplicingImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
-	err = ProcessError("unquote-splicing should not be used outside of a quasiquoted expression.", env)
+	err = ProcessTypedError(SyntaxErrorCondition, "unquote-splicing should not be used outside of a quasiquoted expression.", env)
 	return
 }
 
@@ -119,8 +132,109 @@ func ExpandImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 		return
 	}
 	if !MacroP(n) {
-		err = ProcessError(fmt.Sprintf("expand expected a macro, received %s", String(n)), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("expand expected a macro, received %s", String(n)), env)
 		return
 	}
 	return MacroValue(n).Expand(Cdr(args), env)
 }
+
+func MacroExpand1Impl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	return MacroExpand1(Car(args), env)
+}
+
+func MacroExpandImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	return MacroExpand(Car(args), env)
+}
+
+func MacroExpandAllImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	return MacroExpandAll(Car(args), env)
+}
+
+func SyntaxRulesImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	ellipsis := "..."
+	if SymbolP(Car(args)) {
+		ellipsis = StringValue(Car(args))
+		args = Cdr(args)
+	}
+
+	literals := Car(args)
+	if !ListP(literals) {
+		err = ProcessTypedError(SyntaxErrorCondition, fmt.Sprintf("syntax-rules requires a list of literals, but was given %s.", String(literals)), env)
+		return
+	}
+
+	rules, err := MakeSyntaxRules(ellipsis, literals, Cdr(args), env)
+	if err != nil {
+		return
+	}
+	return MacroWithValue(MakeSyntaxRulesMacro("syntax-rules", rules, env)), nil
+}
+
+func evalSyntaxTransformer(name *Data, spec *Data, env *SymbolTableFrame) (transformer *Data, err error) {
+	if !SymbolP(name) {
+		err = ProcessTypedError(SyntaxErrorCondition, fmt.Sprintf("Syntax keywords must be symbols, but was given %s.", String(name)), env)
+		return
+	}
+
+	transformer, err = Eval(spec, env)
+	if err != nil {
+		return
+	}
+	if !MacroP(transformer) {
+		err = ProcessTypedError(SyntaxErrorCondition, fmt.Sprintf("The syntax bound to %s must be a macro transformer, but was %s.", StringValue(name), String(transformer)), env)
+		return
+	}
+
+	macro := MacroValue(transformer)
+	if macro.Rules != nil {
+		transformer = MacroWithValue(MakeSyntaxRulesMacro(StringValue(name), macro.Rules, macro.Env))
+	}
+	return
+}
+
+func DefineSyntaxImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	transformer, err := evalSyntaxTransformer(Car(args), Cadr(args), env)
+	if err != nil {
+		return
+	}
+	_, err = env.BindLocallyTo(Car(args), transformer)
+	return transformer, err
+}
+
+func letSyntaxCommon(args *Data, env *SymbolTableFrame, rec bool) (result *Data, err error) {
+	bindings := Car(args)
+	if !ListP(bindings) {
+		err = ProcessTypedError(SyntaxErrorCondition, "let-syntax requires a list of bindings as it's first argument", env)
+		return
+	}
+
+	localEnv := NewSymbolTableFrameBelow(env, "let-syntax")
+	specEnv := env
+	if rec {
+		specEnv = localEnv
+	}
+
+	var transformer *Data
+	for b := bindings; NotNilP(b); b = Cdr(b) {
+		binding := Car(b)
+		if !PairP(binding) || Length(binding) != 2 {
+			err = ProcessTypedError(SyntaxErrorCondition, fmt.Sprintf("let-syntax bindings must be (keyword transformer) pairs, but was given %s.", String(binding)), env)
+			return
+		}
+		transformer, err = evalSyntaxTransformer(Car(binding), Cadr(binding), specEnv)
+		if err != nil {
+			return
+		}
+		localEnv.BindLocallyTo(Car(binding), transformer)
+	}
+
+	return evaluateBody(Cdr(args), localEnv)
+}
+
+func LetSyntaxImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	return letSyntaxCommon(args, env, false)
+}
+
+func LetrecSyntaxImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	return letSyntaxCommon(args, env, true)
+}
@@ -10,6 +10,7 @@ package golisp
 import (
 	"fmt"
 	"math"
+	"math/big"
 	"math/rand"
 )
 
@@ -17,7 +18,7 @@ func RegisterMathPrimitives() {
 	MakePrimitiveFunction("+", "*", AddImpl)
 	MakePrimitiveFunction("-", "*", SubtractImpl)
 	MakePrimitiveFunction("*", "*", MultiplyImpl)
-	MakePrimitiveFunction("/", "*", QuotientImpl)
+	MakePrimitiveFunction("/", "*", DivideImpl)
 	MakePrimitiveFunction("succ", "1", IncrementImpl)
 	MakePrimitiveFunction("pred", "1", DecrementImpl)
 	MakePrimitiveFunction("quotient", "*", QuotientImpl)
@@ -41,10 +42,18 @@ func RegisterMathPrimitives() {
 	MakePrimitiveFunction("odd?", "1", OddImpl)
 	MakePrimitiveFunction("sign", "1", SignImpl)
 	MakePrimitiveFunction("pow", "2", PowImpl)
+	MakePrimitiveFunction("numerator", "1", NumeratorImpl)
+	MakePrimitiveFunction("denominator", "1", DenominatorImpl)
+	MakePrimitiveFunction("exact->inexact", "1", ExactToInexactImpl)
+	MakePrimitiveFunction("inexact->exact", "1", InexactToExactImpl)
+	MakePrimitiveFunction("exact?", "1", IsExactImpl)
+	MakePrimitiveFunction("inexact?", "1", IsInexactImpl)
 	MakePrimitiveFunction("inf?", "1", IsInfImpl)
 	MakePrimitiveFunction("nan?", "1", IsNaNImpl)
 	MakePrimitiveFunction("float->bits", "1", FloatToBitsImpl)
 	MakePrimitiveFunction("bits->float", "1", BitsToFloatImpl)
+	MakePrimitiveFunction("float->bits32", "1", FloatToBits32Impl)
+	MakePrimitiveFunction("bits32->float", "1", Bits32ToFloatImpl)
 
 	makeUnaryFloatFunction("acos", math.Acos)
 	makeUnaryFloatFunction("acosh", math.Acosh)
@@ -76,20 +85,20 @@ func RegisterMathPrimitives() {
 	makeUnaryFloatFunction("y0", math.Y0)
 	makeUnaryFloatFunction("y1", math.Y1)
 
-	Global.BindToProtected(Intern("pi"), FloatWithValue(float32(math.Pi)))
-	Global.BindToProtected(Intern("e"), FloatWithValue(float32(math.E)))
-	Global.BindToProtected(Intern("phi"), FloatWithValue(float32(math.Phi)))
-	Global.BindToProtected(Intern("sqrt2"), FloatWithValue(float32(math.Sqrt2)))
-	Global.BindToProtected(Intern("sqrte"), FloatWithValue(float32(math.SqrtE)))
-	Global.BindToProtected(Intern("sqrtpi"), FloatWithValue(float32(math.SqrtPi)))
-	Global.BindToProtected(Intern("sqrtphi"), FloatWithValue(float32(math.SqrtPhi)))
-	Global.BindToProtected(Intern("ln2"), FloatWithValue(float32(math.Ln2)))
-	Global.BindToProtected(Intern("log2e"), FloatWithValue(float32(math.Log2E)))
-	Global.BindToProtected(Intern("ln10"), FloatWithValue(float32(math.Ln10)))
-	Global.BindToProtected(Intern("log10e"), FloatWithValue(float32(math.Log10E)))
-	Global.BindToProtected(Intern("nan"), FloatWithValue(float32(math.NaN())))
-	Global.BindToProtected(Intern("+inf"), FloatWithValue(float32(math.Inf(1))))
-	Global.BindToProtected(Intern("-inf"), FloatWithValue(float32(math.Inf(-1))))
+	Global.BindToProtected(Intern("pi"), FloatWithValue(math.Pi))
+	Global.BindToProtected(Intern("e"), FloatWithValue(math.E))
+	Global.BindToProtected(Intern("phi"), FloatWithValue(math.Phi))
+	Global.BindToProtected(Intern("sqrt2"), FloatWithValue(math.Sqrt2))
+	Global.BindToProtected(Intern("sqrte"), FloatWithValue(math.SqrtE))
+	Global.BindToProtected(Intern("sqrtpi"), FloatWithValue(math.SqrtPi))
+	Global.BindToProtected(Intern("sqrtphi"), FloatWithValue(math.SqrtPhi))
+	Global.BindToProtected(Intern("ln2"), FloatWithValue(math.Ln2))
+	Global.BindToProtected(Intern("log2e"), FloatWithValue(math.Log2E))
+	Global.BindToProtected(Intern("ln10"), FloatWithValue(math.Ln10))
+	Global.BindToProtected(Intern("log10e"), FloatWithValue(math.Log10E))
+	Global.BindToProtected(Intern("nan"), FloatWithValue(math.NaN()))
+	Global.BindToProtected(Intern("+inf"), FloatWithValue(math.Inf(1)))
+	Global.BindToProtected(Intern("-inf"), FloatWithValue(math.Inf(-1)))
 }
 
 func makeUnaryFloatFunction(name string, f func(float64) float64) {
@@ -97,21 +106,17 @@ func makeUnaryFloatFunction(name string, f func(float64) float64) {
 		valObj := Car(args)
 
 		if !NumberP(valObj) {
-			err = ProcessError(fmt.Sprintf("%s expects a number as a parameter, got %s", name, String(valObj)), env)
+			err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("%s expects a number as a parameter, got %s", name, String(valObj)), env)
 			return
 		}
 
-		val := FloatValue(valObj)
-
-		ret := f(float64(val))
-
-		return FloatWithValue(float32(ret)), nil
+		return FloatWithValue(f(FloatValue(valObj))), nil
 	}
 
 	MakePrimitiveFunction(name, "1", primFunc)
 }
 
-func sgn(a float32) int64 {
+func sgn(a float64) int64 {
 	switch {
 	case a < 0:
 		return -1
@@ -122,49 +127,31 @@ func sgn(a float32) int64 {
 }
 
 func intSgn(a int64) int64 {
-	return sgn(float32(a))
+	return sgn(float64(a))
 }
 
 func IncrementImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
-	if !IntegerP(Car(args)) {
-		err = ProcessError("1+ requires an integer argument", env)
+	if !ExactIntegerP(Car(args)) {
+		err = ProcessTypedError(WrongTypeArgumentCondition, "1+ requires an integer argument", env)
 		return
 	}
 
-	val := IntegerValue(Car(args))
-	return IntegerWithValue(val + 1), nil
+	return combineNumbers(addOperation, Car(args), IntegerWithValue(1)), nil
 }
 
 func DecrementImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
-	if !IntegerP(Car(args)) {
-		err = ProcessError("1- requires an integer argument", env)
+	if !ExactIntegerP(Car(args)) {
+		err = ProcessTypedError(WrongTypeArgumentCondition, "1- requires an integer argument", env)
 		return
 	}
 
-	val := IntegerValue(Car(args))
-	return IntegerWithValue(val - 1), nil
-}
-
-func addFloats(args *Data, env *SymbolTableFrame) (result *Data, err error) {
-	var acc float32 = 0
-	for c := args; NotNilP(c); c = Cdr(c) {
-		acc += FloatValue(Car(c))
-	}
-	return FloatWithValue(acc), nil
-}
-
-func addInts(args *Data, env *SymbolTableFrame) (result *Data, err error) {
-	var acc int64 = 0
-	for c := args; NotNilP(c); c = Cdr(c) {
-		acc += IntegerValue(Car(c))
-	}
-	return IntegerWithValue(acc), nil
+	return combineNumbers(subtractOperation, Car(args), IntegerWithValue(1)), nil
 }
 
 func anyFloats(args *Data, env *SymbolTableFrame) (result bool, err error) {
 	for c := args; NotNilP(c); c = Cdr(c) {
 		if !NumberP(Car(c)) {
-			err = ProcessError(fmt.Sprintf("Number expected, received %s", String(Car(c))), env)
+			err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("Number expected, received %s", String(Car(c))), env)
 			return
 		}
 		if FloatP(Car(c)) {
@@ -174,129 +161,86 @@ func anyFloats(args *Data, env *SymbolTableFrame) (result bool, err error) {
 	return false, nil
 }
 
-func AddImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
-	areFloats, err := anyFloats(args, env)
+// Combines the numbers in args from left to right, starting with acc.
+func foldNumbers(operation int, acc *Data, args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	_, err = anyFloats(args, env)
 	if err != nil {
 		return
 	}
-	if areFloats {
-		return addFloats(args, env)
-	} else {
-		return addInts(args, env)
-	}
-}
-
-func subtractInts(args *Data, env *SymbolTableFrame) (result *Data, err error) {
-	acc := IntegerValue(Car(args))
-	for c := Cdr(args); NotNilP(c); c = Cdr(c) {
-		acc -= IntegerValue(Car(c))
+	for c := args; NotNilP(c); c = Cdr(c) {
+		acc = combineNumbers(operation, acc, Car(c))
 	}
-	return IntegerWithValue(acc), nil
+	return acc, nil
 }
 
-func subtractFloats(args *Data, env *SymbolTableFrame) (result *Data, err error) {
-	acc := FloatValue(Car(args))
-	for c := Cdr(args); NotNilP(c); c = Cdr(c) {
-		acc -= FloatValue(Car(c))
-	}
-	return FloatWithValue(acc), nil
+func AddImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	return foldNumbers(addOperation, IntegerWithValue(0), args, env)
 }
 
 func SubtractImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
-	areFloats, err := anyFloats(args, env)
-	if err != nil {
-		return
+	if NilP(args) {
+		return IntegerWithValue(0), nil
 	}
-	if areFloats {
-		return subtractFloats(args, env)
-	} else {
-		return subtractInts(args, env)
+	if !NumberP(Car(args)) {
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("Number expected, received %s", String(Car(args))), env)
+		return
 	}
+	return foldNumbers(subtractOperation, Car(args), Cdr(args), env)
 }
 
-func multiplyInts(args *Data, env *SymbolTableFrame) (result *Data, err error) {
-	var acc int64 = 1
-	for c := args; NotNilP(c); c = Cdr(c) {
-		acc *= IntegerValue(Car(c))
-	}
-	return IntegerWithValue(acc), nil
+func MultiplyImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	return foldNumbers(multiplyOperation, IntegerWithValue(1), args, env)
 }
 
-func multiplyFloats(args *Data, env *SymbolTableFrame) (result *Data, err error) {
-	var acc float32 = 1.0
-	for c := args; NotNilP(c); c = Cdr(c) {
-		acc *= FloatValue(Car(c))
+// Divides the first of args by the rest in turn, using divide to divide a
+// pair of numbers.
+func divideNumbers(args *Data, env *SymbolTableFrame, divide func(*Data, *Data) *Data) (result *Data, err error) {
+	if NilP(args) {
+		return IntegerWithValue(0), nil
 	}
-	return FloatWithValue(acc), nil
-}
-
-func MultiplyImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
-	areFloats, err := anyFloats(args, env)
+	_, err = anyFloats(args, env)
 	if err != nil {
 		return
 	}
-	if areFloats {
-		return multiplyFloats(args, env)
-	} else {
-		return multiplyInts(args, env)
-	}
-}
 
-func quotientInts(args *Data, env *SymbolTableFrame) (result *Data, err error) {
-	acc := IntegerValue(Car(args))
+	acc := Car(args)
 	for c := Cdr(args); NotNilP(c); c = Cdr(c) {
-		v := IntegerValue(Car(c))
-		if v == 0 {
-			err = ProcessError(fmt.Sprintf("Quotent: %s -> Divide by zero.", String(args)), env)
+		if isZeroNumber(Car(c)) {
+			err = ProcessTypedError(DivideByZeroCondition, fmt.Sprintf("Quotent: %s -> Divide by zero.", String(args)), env)
 			return
-		} else {
-			acc /= v
 		}
+		acc = divide(acc, Car(c))
 	}
-	return IntegerWithValue(acc), nil
+	return acc, nil
 }
 
-func quotientFloats(args *Data, env *SymbolTableFrame) (result *Data, err error) {
-	var acc float32 = FloatValue(Car(args))
-	for c := Cdr(args); NotNilP(c); c = Cdr(c) {
-		v := FloatValue(Car(c))
-		if v == 0 {
-			err = ProcessError(fmt.Sprintf("Quotent: %s -> Divide by zero.", String(args)), env)
-			return
-		} else {
-			acc /= v
-		}
-	}
-	return FloatWithValue(acc), nil
+func DivideImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	return divideNumbers(args, env, func(a *Data, b *Data) *Data { return combineNumbers(divideOperation, a, b) })
 }
 
 func QuotientImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
-	areFloats, err := anyFloats(args, env)
-	if err != nil {
-		return
-	}
-	if areFloats {
-		return quotientFloats(args, env)
-	} else {
-		return quotientInts(args, env)
-	}
+	return divideNumbers(args, env, quotientNumbers)
 }
 
 func RemainderImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	dividend := Car(args)
-	if !IntegerP(dividend) {
-		err = ProcessError(fmt.Sprintf("%%/modulo expected an integer first arg, received %s", String(dividend)), env)
+	if !ExactIntegerP(dividend) {
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("%%/modulo expected an integer first arg, received %s", String(dividend)), env)
 		return
 	}
 
 	divisor := Cadr(args)
-	if !IntegerP(divisor) {
-		err = ProcessError(fmt.Sprintf("%%/modulo expected an integer second arg, received %s", String(divisor)), env)
+	if !ExactIntegerP(divisor) {
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("%%/modulo expected an integer second arg, received %s", String(divisor)), env)
 		return
 	}
 
-	val := IntegerValue(dividend) % IntegerValue(divisor)
-	return IntegerWithValue(val), nil
+	if isZeroNumber(divisor) {
+		err = ProcessTypedError(DivideByZeroCondition, fmt.Sprintf("%%/modulo: %s -> Divide by zero.", String(args)), env)
+		return
+	}
+
+	return remainderNumbers(dividend, divisor), nil
 }
 
 // Not tested since it just wraps rand.Int()
@@ -312,6 +256,10 @@ func IntervalImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	var end int64
 
 	startObj := Car(args)
+	if !IntegerP(startObj) {
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("interval bounds must be integers, received %s", String(startObj)), env)
+		return
+	}
 	start := IntegerValue(startObj)
 
 	if Length(args) == 1 {
@@ -322,6 +270,10 @@ func IntervalImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	} else {
 
 		endObj := Cadr(args)
+		if !IntegerP(endObj) {
+			err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("interval bounds must be integers, received %s", String(endObj)), env)
+			return
+		}
 		end = IntegerValue(endObj)
 
 		if start > end {
@@ -330,7 +282,7 @@ func IntervalImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 
 		if Length(args) == 3 {
 			if !IntegerP(Caddr(args)) {
-				err = ProcessError(fmt.Sprintf("interval step must be an integer, received %s", String(Caddr(args))), env)
+				err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("interval step must be an integer, received %s", String(Caddr(args))), env)
 				return
 			}
 			step = IntegerValue(Caddr(args))
@@ -360,17 +312,20 @@ func IntervalImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 func ToIntImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	n := Car(args)
 	if !NumberP(n) {
-		err = ProcessError(fmt.Sprintf("integer expected an number, received %s", String(n)), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("integer expected an number, received %s", String(n)), env)
 		return
 	}
 
+	if ExactP(n) {
+		return truncateExact(n), nil
+	}
 	return IntegerWithValue(IntegerValue(n)), nil
 }
 
 func ToFloatImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	n := Car(args)
 	if !NumberP(n) {
-		err = ProcessError(fmt.Sprintf("float expected a number, received %s", String(n)), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("float expected a number, received %s", String(n)), env)
 		return
 	}
 
@@ -379,7 +334,6 @@ func ToFloatImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 
 func NumberToStringImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	valObj := First(args)
-	val := IntegerValue(valObj)
 	var base int64
 	if Length(args) == 2 {
 		baseObj := Second(args)
@@ -388,21 +342,16 @@ func NumberToStringImpl(args *Data, env *SymbolTableFrame) (result *Data, err er
 		base = 10
 	}
 
-	var format string
 	switch base {
-	case 2:
-		format = "%b"
-	case 8:
-		format = "%o"
-	case 10:
-		format = "%d"
-	case 16:
-		format = "%x"
+	case 2, 8, 10, 16:
 	default:
-		format = "Unsupported base: %d"
-		val = base
+		return StringWithValue(fmt.Sprintf("Unsupported base: %d", base)), nil
+	}
+
+	if !ExactP(valObj) {
+		valObj = IntegerWithValue(IntegerValue(valObj))
 	}
-	return StringWithValue(fmt.Sprintf(format, val)), nil
+	return StringWithValue(exactNumberText(valObj, int(base))), nil
 }
 
 func StringToNumberImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
@@ -429,239 +378,158 @@ func StringToNumberImpl(args *Data, env *SymbolTableFrame) (result *Data, err er
 	default:
 		return IntegerWithValue(0), nil
 	}
-	var val int64
-	_, err = fmt.Sscanf(str, format, &val)
-	if err != nil {
-		return
-	}
-	return IntegerWithValue(val), nil
-}
 
-func minInts(args *Data, env *SymbolTableFrame) (result *Data, err error) {
-	n := Car(args)
-	if !IntegerP(n) {
-		err = ProcessError(fmt.Sprintf("min requires numbers, received %s", String(n)), env)
+	result, err = ParseExactNumber(str, int(base))
+	if err == nil {
 		return
 	}
-	var acc int64 = IntegerValue(n)
-
-	for c := Cdr(args); NotNilP(c); c = Cdr(c) {
-		n = Car(c)
-		if !IntegerP(n) {
-			err = ProcessError(fmt.Sprintf("min requires numbers, received %s", String(n)), env)
-			return
-		}
-		if IntegerValue(n) < acc {
-			acc = IntegerValue(n)
-		}
-	}
 
-	return IntegerWithValue(acc), nil
-}
-
-func minFloats(args *Data, env *SymbolTableFrame) (result *Data, err error) {
-	n := Car(args)
-	if !NumberP(n) {
-		err = ProcessError(fmt.Sprintf("min requires numbers, received %s", String(n)), env)
+	var val int64
+	_, err = fmt.Sscanf(str, format, &val)
+	if err != nil {
 		return
 	}
-	var acc float32 = FloatValue(n)
-
-	for c := Cdr(args); NotNilP(c); c = Cdr(c) {
-		n = Car(c)
-		if !NumberP(n) {
-			err = ProcessError(fmt.Sprintf("min requires numbers, received %s", String(n)), env)
-			return
-		}
-		if FloatValue(n) < acc {
-			acc = FloatValue(n)
-		}
-	}
-
-	return FloatWithValue(acc), nil
+	return IntegerWithValue(val), nil
 }
 
-func MinImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+// The number in the list args that compares to the others as wanted, -1 for
+// the smallest and 1 for the largest. If any of them is a float the result is
+// too.
+func extremeNumber(name string, wanted int, args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	numbers := Car(args)
 	if !ListP(numbers) {
-		err = ProcessError(fmt.Sprintf("min requires a list of numbers, received %s", String(numbers)), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("%s requires a list of numbers, received %s", name, String(numbers)), env)
 		return
 	}
 	if Length(numbers) == 0 {
 		return IntegerWithValue(0), nil
 	}
 
-	areFloats, err := anyFloats(numbers, env)
-	if err != nil {
-		return
-	}
-	if areFloats {
-		return minFloats(numbers, env)
-	} else {
-		return minInts(numbers, env)
-	}
-}
-
-func maxInts(args *Data, env *SymbolTableFrame) (result *Data, err error) {
-	n := Car(args)
-	if !IntegerP(n) {
-		err = ProcessError(fmt.Sprintf("max requires numbers, received %s", String(n)), env)
-		return
-	}
-	var acc int64 = IntegerValue(n)
-
-	for c := Cdr(args); NotNilP(c); c = Cdr(c) {
-		n = Car(c)
-		if !IntegerP(n) {
-			err = ProcessError(fmt.Sprintf("max requires numbers, received %s", String(n)), env)
+	areFloats := false
+	for c := numbers; NotNilP(c); c = Cdr(c) {
+		n := Car(c)
+		if !NumberP(n) {
+			err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("%s requires numbers, received %s", name, String(n)), env)
 			return
 		}
-		if IntegerValue(n) > acc {
-			acc = IntegerValue(n)
+		areFloats = areFloats || FloatP(n)
+		if result == nil {
+			result = n
+		} else if comparison, _ := compareNumbers(n, result); comparison == wanted {
+			result = n
 		}
 	}
 
-	return IntegerWithValue(acc), nil
-}
-
-func maxFloats(args *Data, env *SymbolTableFrame) (result *Data, err error) {
-	n := Car(args)
-	if !NumberP(n) {
-		err = ProcessError(fmt.Sprintf("max requires numbers, received %s", String(n)), env)
-		return
-	}
-	var acc float32 = FloatValue(n)
-
-	for c := Cdr(args); NotNilP(c); c = Cdr(c) {
-		n = Car(c)
-		if !NumberP(n) {
-			err = ProcessError(fmt.Sprintf("max requires numbers, received %s", String(n)), env)
-			return
-		}
-		if FloatValue(n) > acc {
-			acc = FloatValue(n)
-		}
+	if areFloats {
+		return FloatWithValue(FloatValue(result)), nil
 	}
+	return
+}
 
-	return FloatWithValue(acc), nil
+func MinImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	return extremeNumber("min", -1, args, env)
 }
 
 func MaxImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
-	numbers := Car(args)
-	if !ListP(numbers) {
-		err = ProcessError(fmt.Sprintf("max requires a list of numbers, received %s", String(numbers)), env)
-		return
-	}
-
-	if Length(numbers) == 0 {
-		return IntegerWithValue(0), nil
-	}
-
-	areFloats, err := anyFloats(numbers, env)
-	if err != nil {
-		return
-	}
-	if areFloats {
-		return maxFloats(numbers, env)
-	} else {
-		return maxInts(numbers, env)
-	}
+	return extremeNumber("max", 1, args, env)
 }
 
 func FloorImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	val := Car(args)
 
 	if !NumberP(val) {
-		err = ProcessError(fmt.Sprintf("floor expected an number, received %s", String(Car(args))), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("floor expected an number, received %s", String(Car(args))), env)
 		return
 	}
 
-	return FloatWithValue(float32(math.Floor(float64(FloatValue(val))))), nil
+	if RationalP(val) {
+		return floorExact(val), nil
+	}
+	return FloatWithValue(math.Floor(FloatValue(val))), nil
 }
 
 func CeilingImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	val := Car(args)
 
 	if !NumberP(val) {
-		err = ProcessError(fmt.Sprintf("ceiling expected a number, received %s", String(Car(args))), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("ceiling expected a number, received %s", String(Car(args))), env)
 		return
 	}
 
-	return FloatWithValue(float32(math.Ceil(float64(FloatValue(val))))), nil
+	if RationalP(val) {
+		return ceilingExact(val), nil
+	}
+	return FloatWithValue(math.Ceil(FloatValue(val))), nil
 }
 
 func AbsImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	val := Car(args)
 	if !NumberP(val) {
-		err = ProcessError(fmt.Sprintf("abs expected a number, received %s", String(Car(args))), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("abs expected a number, received %s", String(Car(args))), env)
 		return
 	}
-	absval := math.Abs(float64(FloatValue(val)))
-	if IntegerP(val) {
-		result = IntegerWithValue(int64(absval))
-	} else {
-		result = FloatWithValue(float32(absval))
+	if FloatP(val) {
+		return FloatWithValue(math.Abs(FloatValue(val))), nil
 	}
-	return
+	if numberSign(val) < 0 {
+		return negateNumber(val), nil
+	}
+	return val, nil
 }
 
 func ZeroImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	val := Car(args)
 	if !NumberP(val) {
-		err = ProcessError(fmt.Sprintf("zero? expected a number, received %s", String(Car(args))), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("zero? expected a number, received %s", String(Car(args))), env)
 		return
 	}
-	return BooleanWithValue(FloatValue(val) == 0.0), nil
+	return BooleanWithValue(isZeroNumber(val)), nil
 }
 
 func PositiveImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	val := Car(args)
 	if !NumberP(val) {
-		err = ProcessError(fmt.Sprintf("positive? expected a number, received %s", String(Car(args))), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("positive? expected a number, received %s", String(Car(args))), env)
 		return
 	}
-	return BooleanWithValue(FloatValue(val) > 0.0), nil
+	return BooleanWithValue(numberSign(val) > 0), nil
 }
 
 func NegativeImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	val := Car(args)
 	if !NumberP(val) {
-		err = ProcessError(fmt.Sprintf("negative expected a number, received %s", String(Car(args))), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("negative expected a number, received %s", String(Car(args))), env)
 		return
 	}
-	return BooleanWithValue(FloatValue(val) < 0.0), nil
+	return BooleanWithValue(numberSign(val) < 0), nil
 }
 
 func EvenImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	val := Car(args)
-	if !IntegerP(val) {
-		err = ProcessError(fmt.Sprintf("even? expected an integer, received %s", String(Car(args))), env)
+	if !ExactIntegerP(val) {
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("even? expected an integer, received %s", String(Car(args))), env)
 		return
 	}
-	return BooleanWithValue(IntegerValue(val)%2 == 0), nil
+	return BooleanWithValue(bigIntValue(val).Bit(0) == 0), nil
 }
 
 func OddImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	val := Car(args)
-	if !IntegerP(val) {
-		err = ProcessError(fmt.Sprintf("odd? expected an integer, received %s", String(Car(args))), env)
+	if !ExactIntegerP(val) {
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("odd? expected an integer, received %s", String(Car(args))), env)
 		return
 	}
-	return BooleanWithValue(IntegerValue(val)%2 != 0), nil
+	return BooleanWithValue(bigIntValue(val).Bit(0) != 0), nil
 }
 
 func SignImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	val := Car(args)
 	if !NumberP(val) {
-		err = ProcessError(fmt.Sprintf("sign expected a nunber, received %s", String(Car(args))), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("sign expected a nunber, received %s", String(Car(args))), env)
 		return
 	}
 
-	if FloatP(val) {
-		return IntegerWithValue(sgn(float32(FloatValue(val)))), nil
-	} else {
-		return IntegerWithValue(intSgn(IntegerValue(val))), nil
-	}
+	return IntegerWithValue(int64(numberSign(val))), nil
 }
 
 func PowImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
@@ -673,32 +541,109 @@ func PowImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	base := Car(args)
 	exponent := Cadr(args)
 
-	if areFloats {
-		return FloatWithValue(float32(math.Pow(float64(FloatValue(base)), float64(FloatValue(exponent))))), nil
-	} else {
-		ret := int64(1)
-		b := IntegerValue(base)
-		e := IntegerValue(exponent)
-		for e > 0 {
-			if e&1 != 0 {
-				ret *= b
-			}
-			b *= b
-			e >>= 1
+	if areFloats || RationalP(exponent) {
+		return FloatWithValue(math.Pow(FloatValue(base), FloatValue(exponent))), nil
+	}
+
+	result, err = exactPower(base, exponent)
+	if err != nil {
+		err = ProcessTypedError(DivideByZeroCondition, fmt.Sprintf("pow: %s -> Divide by zero.", String(args)), env)
+	}
+	return
+}
+
+func numberArg(name string, n *Data, env *SymbolTableFrame) (err error) {
+	if !NumberP(n) {
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("%s expected a number, received %s", name, String(n)), env)
+	}
+	return
+}
+
+// Applies part to the exact value of n. A float is converted to an exact
+// number for it and the result back to a float.
+func rationalPart(name string, n *Data, part func(*big.Rat) *big.Int, env *SymbolTableFrame) (result *Data, err error) {
+	err = numberArg(name, n, env)
+	if err != nil {
+		return
+	}
+
+	exact := n
+	if FloatP(n) {
+		exact, err = floatToExact(FloatValue(n))
+		if err != nil {
+			err = ProcessTypedError(BadRangeArgumentCondition, fmt.Sprintf("%s: %s", name, err), env)
+			return
 		}
-		return IntegerWithValue(ret), nil
 	}
+
+	result = ExactIntegerWithValue(new(big.Int).Set(part(bigRatValue(exact))))
+	if FloatP(n) {
+		result = FloatWithValue(FloatValue(result))
+	}
+	return
+}
+
+func NumeratorImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	return rationalPart("numerator", Car(args), (*big.Rat).Num, env)
+}
+
+func DenominatorImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	return rationalPart("denominator", Car(args), (*big.Rat).Denom, env)
+}
+
+func ExactToInexactImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	n := Car(args)
+	err = numberArg("exact->inexact", n, env)
+	if err != nil {
+		return
+	}
+	return FloatWithValue(FloatValue(n)), nil
+}
+
+func InexactToExactImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	n := Car(args)
+	err = numberArg("inexact->exact", n, env)
+	if err != nil {
+		return
+	}
+	if ExactP(n) {
+		return n, nil
+	}
+
+	result, err = floatToExact(FloatValue(n))
+	if err != nil {
+		err = ProcessTypedError(BadRangeArgumentCondition, fmt.Sprintf("inexact->exact: %s", err), env)
+	}
+	return
+}
+
+func IsExactImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	n := Car(args)
+	err = numberArg("exact?", n, env)
+	if err != nil {
+		return
+	}
+	return BooleanWithValue(ExactP(n)), nil
+}
+
+func IsInexactImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	n := Car(args)
+	err = numberArg("inexact?", n, env)
+	if err != nil {
+		return
+	}
+	return BooleanWithValue(FloatP(n)), nil
 }
 
 func IsInfImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	val := Car(args)
 	if !NumberP(val) {
-		err = ProcessError(fmt.Sprintf("inf? expected a nunber, received %s", String(val)), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("inf? expected a nunber, received %s", String(val)), env)
 		return
 	}
 
 	if FloatP(val) {
-		return BooleanWithValue(math.IsInf(float64(FloatValue(val)), 0)), nil
+		return BooleanWithValue(math.IsInf(FloatValue(val), 0)), nil
 	} else {
 		return BooleanWithValue(false), nil
 	}
@@ -707,12 +652,12 @@ func IsInfImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 func IsNaNImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	val := Car(args)
 	if !NumberP(val) {
-		err = ProcessError(fmt.Sprintf("nan? expected a nunber, received %s", String(val)), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("nan? expected a nunber, received %s", String(val)), env)
 		return
 	}
 
 	if FloatP(val) {
-		return BooleanWithValue(math.IsNaN(float64(FloatValue(val)))), nil
+		return BooleanWithValue(math.IsNaN(FloatValue(val))), nil
 	} else {
 		return BooleanWithValue(false), nil
 	}
@@ -721,19 +666,50 @@ func IsNaNImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 func FloatToBitsImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	float := Car(args)
 	if !FloatP(float) {
-		err = ProcessError(fmt.Sprintf("float->bits expected a float, received %s", String(float)), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("float->bits expected a float, received %s", String(float)), env)
 		return
 	}
 
-	return IntegerWithValue(int64(math.Float32bits(FloatValue(float)))), nil
+	return ExactIntegerWithValue(new(big.Int).SetUint64(math.Float64bits(FloatValue(float)))), nil
 }
 
 func BitsToFloatImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	bits := Car(args)
-	if !IntegerP(bits) {
-		err = ProcessError(fmt.Sprintf("bits->float expected an integer, received %s", String(bits)), env)
+	if !ExactIntegerP(bits) {
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("bits->float expected an integer, received %s", String(bits)), env)
+		return
+	}
+	if BignumP(bits) && !BignumValue(bits).IsUint64() {
+		err = ProcessTypedError(BadRangeArgumentCondition, fmt.Sprintf("bits->float expected at most 64 bits, received %s", String(bits)), env)
+		return
+	}
+
+	if BignumP(bits) {
+		return FloatWithValue(math.Float64frombits(BignumValue(bits).Uint64())), nil
+	}
+	return FloatWithValue(math.Float64frombits(uint64(IntegerValue(bits)))), nil
+}
+
+func FloatToBits32Impl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	float := Car(args)
+	if !FloatP(float) {
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("float->bits32 expected a float, received %s", String(float)), env)
+		return
+	}
+
+	return IntegerWithValue(int64(math.Float32bits(float32(FloatValue(float))))), nil
+}
+
+func Bits32ToFloatImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	bits := Car(args)
+	if !ExactIntegerP(bits) {
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("bits32->float expected an integer, received %s", String(bits)), env)
+		return
+	}
+	if !IntegerP(bits) || IntegerValue(bits) < 0 || IntegerValue(bits) > math.MaxUint32 {
+		err = ProcessTypedError(BadRangeArgumentCondition, fmt.Sprintf("bits32->float expected an integer between 0 and %d, received %s", uint32(math.MaxUint32), String(bits)), env)
 		return
 	}
 
-	return FloatWithValue(math.Float32frombits(uint32(IntegerValue(bits)))), nil
+	return FloatWithValue(float64(math.Float32frombits(uint32(IntegerValue(bits))))), nil
 }
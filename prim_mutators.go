@@ -19,7 +19,7 @@ func RegisterMutatorPrimitives() {
 func SetVarImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	symbol := Car(args)
 	if !SymbolP(symbol) {
-		err = ProcessError("set! requires a raw (unevaluated) symbol as it's first argument.", env)
+		err = ProcessTypedError(SyntaxErrorCondition, "set! requires a raw (unevaluated) symbol as it's first argument.", env)
 	}
 	value, err := Eval(Cadr(args), env)
 	if err != nil {
@@ -31,7 +31,7 @@ func SetVarImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 func SetCarImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	pair, err := Eval(Car(args), env)
 	if !PairP(pair) {
-		err = ProcessError("set-car! requires a pair as it's first argument.", env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, "set-car! requires a pair as it's first argument.", env)
 	}
 	value, err := Eval(Cadr(args), env)
 	if err != nil {
@@ -44,7 +44,7 @@ func SetCarImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 func SetCdrImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	pair, err := Eval(Car(args), env)
 	if !PairP(pair) {
-		err = ProcessError("set-cdr! requires a pair as it's first argument.", env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, "set-cdr! requires a pair as it's first argument.", env)
 	}
 	value, err := Eval(Cadr(args), env)
 	if err != nil {
@@ -58,7 +58,7 @@ func SetCdrImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 func SetNthImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	l, err := Eval(First(args), env)
 	if !ListP(l) {
-		err = ProcessError("set-nth! requires a list as it's first argument.", env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, "set-nth! requires a list as it's first argument.", env)
 	}
 	index, err := Eval(Second(args), env)
 	if err != nil {
@@ -0,0 +1,207 @@
+// Copyright 2014 SteelSeries ApS.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// This package implements a basic LISP interpretor for embedding in a go program for scripting.
+// This file contains the record type primitive functions.
+
+package golisp
+
+import (
+	"fmt"
+)
+
+func RegisterRecordPrimitives() {
+	MakeSpecialForm("define-record-type", ">=3", DefineRecordTypeImpl)
+	MakePrimitiveFunction("record-type-descriptor", "1", RecordTypeDescriptorImpl)
+	MakePrimitiveFunction("record-type-name", "1", RecordTypeNameImpl)
+	MakePrimitiveFunction("record-type-field-names", "1", RecordTypeFieldNamesImpl)
+}
+
+// The procedures made for a record type are primitives, so that they are as
+// fast as the built in accessors of other types.
+func recordPrimitive(name string, argCount string, body func(*Data, *SymbolTableFrame) (*Data, error)) *Data {
+	f := &PrimitiveFunction{Name: name, Body: body}
+	f.parseNumArgs(argCount)
+	return PrimitiveWithNameAndFunc(name, f)
+}
+
+func recordArg(name string, descriptor *RecordDescriptor, d *Data, env *SymbolTableFrame) (record *Record, err error) {
+	if !RecordP(d) || RecordValue(d).Descriptor != descriptor {
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("%s expects a %s record, but received %s.", name, descriptor.Name, String(d)), env)
+		return
+	}
+	return RecordValue(d), nil
+}
+
+func recordConstructor(name string, descriptor *RecordDescriptor, indexes []int) *Data {
+	return recordPrimitive(name, fmt.Sprintf("%d", len(indexes)), func(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+		record := descriptor.MakeRecord()
+		c := args
+		for _, index := range indexes {
+			record.Fields[index] = Car(c)
+			c = Cdr(c)
+		}
+		return RecordWithValue(record), nil
+	})
+}
+
+func recordPredicate(name string, descriptor *RecordDescriptor) *Data {
+	return recordPrimitive(name, "1", func(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+		return BooleanWithValue(RecordP(Car(args)) && RecordValue(Car(args)).Descriptor == descriptor), nil
+	})
+}
+
+func recordAccessor(name string, descriptor *RecordDescriptor, index int) *Data {
+	return recordPrimitive(name, "1", func(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+		record, err := recordArg(name, descriptor, Car(args), env)
+		if err != nil {
+			return
+		}
+		return record.Fields[index], nil
+	})
+}
+
+func recordModifier(name string, descriptor *RecordDescriptor, index int) *Data {
+	return recordPrimitive(name, "2", func(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+		record, err := recordArg(name, descriptor, Car(args), env)
+		if err != nil {
+			return
+		}
+		record.Fields[index] = Cadr(args)
+		return Cadr(args), nil
+	})
+}
+
+// (define-record-type <type> (constructor field ...) predicate (field accessor [modifier]) ...)
+//
+// The constructor can also be a symbol, in which case it takes every field in
+// order, or #f for no constructor. Fields the constructor doesn't take start
+// out empty.
+func DefineRecordTypeImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	typeName := First(args)
+	if !SymbolP(typeName) {
+		err = ProcessTypedError(SyntaxErrorCondition, fmt.Sprintf("define-record-type expects a symbol for the type name, but received %s.", String(typeName)), env)
+		return
+	}
+	constructorSpec := Second(args)
+	predicateName := Third(args)
+	if !SymbolP(predicateName) {
+		err = ProcessTypedError(SyntaxErrorCondition, fmt.Sprintf("define-record-type expects a symbol for the predicate name, but received %s.", String(predicateName)), env)
+		return
+	}
+
+	fieldSpecs := Cdddr(args)
+	fields := make([]string, 0, Length(fieldSpecs))
+	for c := fieldSpecs; NotNilP(c); c = Cdr(c) {
+		spec := Car(c)
+		if !PairP(spec) || !SymbolP(Car(spec)) || Length(spec) > 3 || (NotNilP(Cdr(spec)) && !SymbolP(Cadr(spec))) || (Length(spec) == 3 && !SymbolP(Caddr(spec))) {
+			err = ProcessTypedError(SyntaxErrorCondition, fmt.Sprintf("define-record-type expects fields of the form (field accessor [modifier]), but received %s.", String(spec)), env)
+			return
+		}
+		field := StringValue(Car(spec))
+		for _, existing := range fields {
+			if existing == field {
+				err = ProcessTypedError(SyntaxErrorCondition, fmt.Sprintf("define-record-type was given the field %s more than once.", field), env)
+				return
+			}
+		}
+		fields = append(fields, field)
+	}
+	descriptor := MakeRecordDescriptor(StringValue(typeName), fields)
+
+	var constructor *Data
+	var constructorName *Data
+	switch {
+	case SymbolP(constructorSpec):
+		constructorName = constructorSpec
+		indexes := make([]int, len(fields))
+		for i := range indexes {
+			indexes[i] = i
+		}
+		constructor = recordConstructor(StringValue(constructorName), descriptor, indexes)
+	case PairP(constructorSpec) && SymbolP(Car(constructorSpec)):
+		constructorName = Car(constructorSpec)
+		indexes := make([]int, 0, Length(Cdr(constructorSpec)))
+		for c := Cdr(constructorSpec); NotNilP(c); c = Cdr(c) {
+			index := -1
+			if SymbolP(Car(c)) {
+				index = descriptor.FieldIndex(StringValue(Car(c)))
+			}
+			if index < 0 {
+				err = ProcessTypedError(SyntaxErrorCondition, fmt.Sprintf("define-record-type constructor %s takes %s, which isn't a field.", StringValue(constructorName), String(Car(c))), env)
+				return
+			}
+			indexes = append(indexes, index)
+		}
+		constructor = recordConstructor(StringValue(constructorName), descriptor, indexes)
+	case !BooleanP(constructorSpec) || BooleanValue(constructorSpec):
+		err = ProcessTypedError(SyntaxErrorCondition, fmt.Sprintf("define-record-type expects a constructor of the form (name field ...), but received %s.", String(constructorSpec)), env)
+		return
+	}
+
+	result = RecordDescriptorWithValue(descriptor)
+	if _, err = env.BindLocallyTo(typeName, result); err != nil {
+		return
+	}
+	if constructor != nil {
+		if _, err = env.BindLocallyTo(constructorName, constructor); err != nil {
+			return
+		}
+	}
+	if _, err = env.BindLocallyTo(predicateName, recordPredicate(StringValue(predicateName), descriptor)); err != nil {
+		return
+	}
+	index := 0
+	for c := fieldSpecs; NotNilP(c); c = Cdr(c) {
+		spec := Car(c)
+		if accessorName := Cadr(spec); NotNilP(accessorName) {
+			if _, err = env.BindLocallyTo(accessorName, recordAccessor(StringValue(accessorName), descriptor, index)); err != nil {
+				return
+			}
+		}
+		if modifierName := Caddr(spec); NotNilP(modifierName) {
+			if _, err = env.BindLocallyTo(modifierName, recordModifier(StringValue(modifierName), descriptor, index)); err != nil {
+				return
+			}
+		}
+		index++
+	}
+	return
+}
+
+func RecordTypeDescriptorImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	if !RecordP(Car(args)) {
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("record-type-descriptor expects a record, but received %s.", String(Car(args))), env)
+		return
+	}
+	return RecordDescriptorWithValue(RecordValue(Car(args)).Descriptor), nil
+}
+
+func recordDescriptorArg(name string, d *Data, env *SymbolTableFrame) (descriptor *RecordDescriptor, err error) {
+	if !RecordDescriptorP(d) {
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("%s expects a record type, but received %s.", name, String(d)), env)
+		return
+	}
+	return RecordDescriptorValue(d), nil
+}
+
+func RecordTypeNameImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	descriptor, err := recordDescriptorArg("record-type-name", Car(args), env)
+	if err != nil {
+		return
+	}
+	return Intern(descriptor.Name), nil
+}
+
+func RecordTypeFieldNamesImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	descriptor, err := recordDescriptorArg("record-type-field-names", Car(args), env)
+	if err != nil {
+		return
+	}
+	names := make([]*Data, 0, len(descriptor.Fields))
+	for _, field := range descriptor.Fields {
+		names = append(names, Intern(field))
+	}
+	return ArrayToList(names), nil
+}
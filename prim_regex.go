@@ -0,0 +1,286 @@
+// Copyright 2014 SteelSeries ApS.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// This package implements a basic LISP interpretor for embedding in a go program for scripting.
+// This file contains the regular expression primitive functions.
+
+package golisp
+
+import (
+	"fmt"
+	"regexp"
+	"unicode/utf8"
+	"unsafe"
+)
+
+func RegisterRegexPrimitives() {
+	MakePrimitiveFunction("make-regex", "1", MakeRegexImpl)
+	MakePrimitiveFunction("regex?", "1", RegexPImpl)
+	MakePrimitiveFunction("regex-match", "2", RegexMatchImpl)
+	MakePrimitiveFunction("regex-match-all", "2|3", RegexMatchAllImpl)
+	MakePrimitiveFunction("regex-match-named", "2", RegexMatchNamedImpl)
+	MakePrimitiveFunction("regex-search", "2|3", RegexSearchImpl)
+	MakePrimitiveFunction("regex-replace", "3|4", RegexReplaceImpl)
+	MakePrimitiveFunction("regex-split", "2|3", RegexSplitImpl)
+}
+
+func RegexWithValue(re *regexp.Regexp) *Data {
+	return ObjectWithTypeAndValue("Regex", unsafe.Pointer(re))
+}
+
+func RegexP(d *Data) bool {
+	return ObjectP(d) && ObjectType(d) == "Regex"
+}
+
+func RegexValue(d *Data) *regexp.Regexp {
+	return (*regexp.Regexp)(ObjectValue(d))
+}
+
+// The regex given either compiled or as a pattern string.
+func regexArg(name string, d *Data, env *SymbolTableFrame) (re *regexp.Regexp, err error) {
+	if RegexP(d) {
+		return RegexValue(d), nil
+	}
+	if !StringP(d) {
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("%s expects a regex or a pattern string, but received %s.", name, String(d)), env)
+		return
+	}
+	re, err = regexp.Compile(StringValue(d))
+	if err != nil {
+		err = ProcessError(fmt.Sprintf("%s received an invalid pattern: %s", name, err), env)
+	}
+	return
+}
+
+func regexStringArg(name string, d *Data, env *SymbolTableFrame) (s string, err error) {
+	if !StringP(d) {
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("%s expects a string, but received %s.", name, String(d)), env)
+		return
+	}
+	return StringValue(d), nil
+}
+
+// The optional limit on the number of matches, where -1 means no limit.
+func regexLimitArg(name string, args *Data, index int, env *SymbolTableFrame) (n int, err error) {
+	if Length(args) <= index {
+		return -1, nil
+	}
+	limit := Nth(args, index+1)
+	if !IntegerP(limit) {
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("%s expects its limit to be an integer, but received %s.", name, String(limit)), env)
+		return
+	}
+	return int(IntegerValue(limit)), nil
+}
+
+// The whole match followed by the submatches, with #f for groups that didn't
+// participate in the match.
+func submatchList(s string, indexes []int) *Data {
+	matches := make([]*Data, 0, len(indexes)/2)
+	for i := 0; i < len(indexes); i += 2 {
+		if indexes[i] < 0 {
+			matches = append(matches, LispFalse)
+		} else {
+			matches = append(matches, StringWithValue(s[indexes[i]:indexes[i+1]]))
+		}
+	}
+	return ArrayToList(matches)
+}
+
+// Positions are counted in characters rather than bytes.
+func submatchPositions(s string, indexes []int) *Data {
+	positions := make([]*Data, 0, len(indexes)/2)
+	for i := 0; i < len(indexes); i += 2 {
+		if indexes[i] < 0 {
+			positions = append(positions, LispFalse)
+		} else {
+			start := utf8.RuneCountInString(s[:indexes[i]])
+			end := start + utf8.RuneCountInString(s[indexes[i]:indexes[i+1]])
+			positions = append(positions, Cons(IntegerWithValue(int64(start)), IntegerWithValue(int64(end))))
+		}
+	}
+	return ArrayToList(positions)
+}
+
+func MakeRegexImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	if !StringP(Car(args)) {
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("make-regex expects a pattern string, but received %s.", String(Car(args))), env)
+		return
+	}
+	re, err := regexArg("make-regex", Car(args), env)
+	if err != nil {
+		return
+	}
+	return RegexWithValue(re), nil
+}
+
+func RegexPImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	return BooleanWithValue(RegexP(Car(args))), nil
+}
+
+// The first match as a list of the matched text and its submatches, or #f.
+func RegexMatchImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	re, err := regexArg("regex-match", First(args), env)
+	if err != nil {
+		return
+	}
+	s, err := regexStringArg("regex-match", Second(args), env)
+	if err != nil {
+		return
+	}
+
+	indexes := re.FindStringSubmatchIndex(s)
+	if indexes == nil {
+		return LispFalse, nil
+	}
+	return submatchList(s, indexes), nil
+}
+
+func RegexMatchAllImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	re, err := regexArg("regex-match-all", First(args), env)
+	if err != nil {
+		return
+	}
+	s, err := regexStringArg("regex-match-all", Second(args), env)
+	if err != nil {
+		return
+	}
+	n, err := regexLimitArg("regex-match-all", args, 2, env)
+	if err != nil {
+		return
+	}
+
+	all := re.FindAllStringSubmatchIndex(s, n)
+	matches := make([]*Data, 0, len(all))
+	for _, indexes := range all {
+		matches = append(matches, submatchList(s, indexes))
+	}
+	return ArrayToList(matches), nil
+}
+
+// The named groups of the first match as a frame, or #f when there's no
+// match. Groups that didn't participate in the match are #f.
+func RegexMatchNamedImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	re, err := regexArg("regex-match-named", First(args), env)
+	if err != nil {
+		return
+	}
+	s, err := regexStringArg("regex-match-named", Second(args), env)
+	if err != nil {
+		return
+	}
+
+	indexes := re.FindStringSubmatchIndex(s)
+	if indexes == nil {
+		return LispFalse, nil
+	}
+
+	f := FrameMap{}
+	f.Data = make(FrameMapData)
+	for i, name := range re.SubexpNames() {
+		if name == "" {
+			continue
+		}
+		if indexes[2*i] < 0 {
+			f.Data[name+":"] = LispFalse
+		} else {
+			f.Data[name+":"] = StringWithValue(s[indexes[2*i]:indexes[2*i+1]])
+		}
+	}
+	return FrameWithValue(&f), nil
+}
+
+// The positions of the first match and its submatches as (start . end)
+// pairs, or #f. The search can begin at a given character position.
+func RegexSearchImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	re, err := regexArg("regex-search", First(args), env)
+	if err != nil {
+		return
+	}
+	s, err := regexStringArg("regex-search", Second(args), env)
+	if err != nil {
+		return
+	}
+
+	offset := 0
+	if Length(args) == 3 {
+		start := Third(args)
+		if !IntegerP(start) || IntegerValue(start) < 0 || IntegerValue(start) > int64(utf8.RuneCountInString(s)) {
+			err = ProcessTypedError(BadRangeArgumentCondition, fmt.Sprintf("regex-search expects its start to be a position in the string, but received %s.", String(start)), env)
+			return
+		}
+		for i := int64(0); i < IntegerValue(start); i++ {
+			_, size := utf8.DecodeRuneInString(s[offset:])
+			offset += size
+		}
+	}
+
+	indexes := re.FindStringSubmatchIndex(s[offset:])
+	if indexes == nil {
+		return LispFalse, nil
+	}
+	for i := range indexes {
+		if indexes[i] >= 0 {
+			indexes[i] += offset
+		}
+	}
+	return submatchPositions(s, indexes), nil
+}
+
+// Replaces every match, or only the first n, expanding $1 and ${name} in
+// the replacement to submatches.
+func RegexReplaceImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	re, err := regexArg("regex-replace", First(args), env)
+	if err != nil {
+		return
+	}
+	s, err := regexStringArg("regex-replace", Second(args), env)
+	if err != nil {
+		return
+	}
+	replacement, err := regexStringArg("regex-replace", Third(args), env)
+	if err != nil {
+		return
+	}
+	n, err := regexLimitArg("regex-replace", args, 3, env)
+	if err != nil {
+		return
+	}
+
+	if n < 0 {
+		return StringWithValue(re.ReplaceAllString(s, replacement)), nil
+	}
+
+	var expanded []byte
+	last := 0
+	for _, indexes := range re.FindAllStringSubmatchIndex(s, n) {
+		expanded = append(expanded, s[last:indexes[0]]...)
+		expanded = re.ExpandString(expanded, replacement, s, indexes)
+		last = indexes[1]
+	}
+	expanded = append(expanded, s[last:]...)
+	return StringWithValue(string(expanded)), nil
+}
+
+func RegexSplitImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	re, err := regexArg("regex-split", First(args), env)
+	if err != nil {
+		return
+	}
+	s, err := regexStringArg("regex-split", Second(args), env)
+	if err != nil {
+		return
+	}
+	n, err := regexLimitArg("regex-split", args, 2, env)
+	if err != nil {
+		return
+	}
+
+	parts := re.Split(s, n)
+	strings := make([]*Data, 0, len(parts))
+	for _, part := range parts {
+		strings = append(strings, StringWithValue(part))
+	}
+	return ArrayToList(strings), nil
+}
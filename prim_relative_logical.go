@@ -14,11 +14,11 @@ import (
 func RegisterRelativePrimitives() {
 	MakePrimitiveFunction("<", "2", LessThanImpl)
 	MakePrimitiveFunction(">", "2", GreaterThanImpl)
-	MakePrimitiveFunction("==", "2", EqualToImpl)
+	MakePrimitiveFunction("==", "2", NumericEqualToImpl)
 	MakePrimitiveFunction("eqv?", "2", EqualToImpl)
 	MakePrimitiveFunction("eq?", "2", EqualToImpl)
 	MakePrimitiveFunction("equal?", "2", EqualToImpl)
-	MakePrimitiveFunction("!=", "2", NotEqualImpl)
+	MakePrimitiveFunction("!=", "2", NumericNotEqualImpl)
 	MakePrimitiveFunction("neq?", "2", NotEqualImpl)
 	MakePrimitiveFunction("<=", "2", LessThanOrEqualToImpl)
 	MakePrimitiveFunction(">=", "2", GreaterThanOrEqualToImpl)
@@ -31,35 +31,35 @@ func RegisterRelativePrimitives() {
 func LessThanImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	arg1 := Car(args)
 	if !NumberP(arg1) {
-		err = ProcessError(fmt.Sprintf("Number expected, received %s", String(arg1)), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("Number expected, received %s", String(arg1)), env)
 		return
 	}
 
 	arg2 := Cadr(args)
 	if !NumberP(arg2) {
-		err = ProcessError(fmt.Sprintf("Number expected, received %s", String(arg2)), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("Number expected, received %s", String(arg2)), env)
 		return
 	}
 
-	val := FloatValue(arg1) < FloatValue(arg2)
-	return BooleanWithValue(val), nil
+	comparison, ordered := compareNumbers(arg1, arg2)
+	return BooleanWithValue(ordered && comparison < 0), nil
 }
 
 func GreaterThanImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	arg1 := Car(args)
 	if !NumberP(arg1) {
-		err = ProcessError(fmt.Sprintf("Number expected, received %s", String(arg1)), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("Number expected, received %s", String(arg1)), env)
 		return
 	}
 
 	arg2 := Cadr(args)
 	if !NumberP(arg2) {
-		err = ProcessError(fmt.Sprintf("Number expected, received %s", String(arg2)), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("Number expected, received %s", String(arg2)), env)
 		return
 	}
 
-	val := FloatValue(arg1) > FloatValue(arg2)
-	return BooleanWithValue(val), nil
+	comparison, ordered := compareNumbers(arg1, arg2)
+	return BooleanWithValue(ordered && comparison > 0), nil
 }
 
 func EqualToImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
@@ -74,38 +74,56 @@ func NotEqualImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	return BooleanWithValue(!IsEqual(arg1, arg2)), nil
 }
 
+// Numbers are compared by value, so that exact and inexact numbers can be
+// equal. Anything else is compared as by equal?.
+func numericallyEqual(arg1 *Data, arg2 *Data) bool {
+	if NumberP(arg1) && NumberP(arg2) {
+		comparison, ordered := compareNumbers(arg1, arg2)
+		return ordered && comparison == 0
+	}
+	return IsEqual(arg1, arg2)
+}
+
+func NumericEqualToImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	return BooleanWithValue(numericallyEqual(Car(args), Cadr(args))), nil
+}
+
+func NumericNotEqualImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	return BooleanWithValue(!numericallyEqual(Car(args), Cadr(args))), nil
+}
+
 func LessThanOrEqualToImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	arg1 := Car(args)
 	if !NumberP(arg1) {
-		err = ProcessError(fmt.Sprintf("Number expected, received %s", String(arg1)), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("Number expected, received %s", String(arg1)), env)
 		return
 	}
 
 	arg2 := Cadr(args)
 	if !NumberP(arg2) {
-		err = ProcessError(fmt.Sprintf("Number expected, received %s", String(arg2)), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("Number expected, received %s", String(arg2)), env)
 		return
 	}
 
-	val := FloatValue(arg1) <= FloatValue(arg2)
-	return BooleanWithValue(val), nil
+	comparison, ordered := compareNumbers(arg1, arg2)
+	return BooleanWithValue(ordered && comparison <= 0), nil
 }
 
 func GreaterThanOrEqualToImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	arg1 := Car(args)
 	if !NumberP(arg1) {
-		err = ProcessError(fmt.Sprintf("Number expected, received %s", String(arg1)), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("Number expected, received %s", String(arg1)), env)
 		return
 	}
 
 	arg2 := Cadr(args)
 	if !NumberP(arg2) {
-		err = ProcessError(fmt.Sprintf("Number expected, received %s", String(arg2)), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("Number expected, received %s", String(arg2)), env)
 		return
 	}
 
-	val := FloatValue(arg1) >= FloatValue(arg2)
-	return BooleanWithValue(val), nil
+	comparison, ordered := compareNumbers(arg1, arg2)
+	return BooleanWithValue(ordered && comparison >= 0), nil
 }
 
 func BooleanNotImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
@@ -114,6 +132,9 @@ func BooleanNotImpl(args *Data, env *SymbolTableFrame) (result *Data, err error)
 
 func BooleanAndImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	for c := args; NotNilP(c); c = Cdr(c) {
+		if NilP(Cdr(c)) {
+			return TailCallWithExprAndEnv(Car(c), env), nil
+		}
 		result, err = Eval(Car(c), env)
 		if err != nil || !BooleanValue(result) {
 			return
@@ -124,6 +145,9 @@ func BooleanAndImpl(args *Data, env *SymbolTableFrame) (result *Data, err error)
 
 func BooleanOrImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	for c := args; NotNilP(c); c = Cdr(c) {
+		if NilP(Cdr(c)) {
+			return TailCallWithExprAndEnv(Car(c), env), nil
+		}
 		result, err = Eval(Car(c), env)
 		if err != nil || BooleanValue(result) {
 			return
@@ -41,11 +41,20 @@ func InitBuiltins() {
 	RegisterAListPrimitives()
 	RegisterSystemPrimitives()
 	RegisterBytearrayPrimitives()
+	RegisterEncodingPrimitives()
 	RegisterStringPrimitives()
+	RegisterRegexPrimitives()
 	RegisterDebugPrimitives()
 	RegisterFramePrimitives()
 	RegisterConcurrencyPrimitives()
 	RegisterEnvironmentPrimitives()
 	RegisterIOPrimitives()
+	RegisterFilePrimitives()
 	RegisterChannelPrimitives()
+	RegisterContinuationPrimitives()
+	RegisterConditionPrimitives()
+	RegisterHashTablePrimitives()
+	RegisterVectorPrimitives()
+	RegisterRecordPrimitives()
+	RegisterCharacterPrimitives()
 }
@@ -32,14 +32,21 @@ func RegisterSpecialFormPrimitives() {
 	MakeSpecialForm("definition-of", "1", DefinitionOfImpl)
 }
 
+// Evaluates all but the last expression of a body, returning the last one
+// as a tail call to be evaluated by the caller.
 func evaluateBody(sexprs *Data, env *SymbolTableFrame) (result *Data, err error) {
-	for e := sexprs; NotNilP(e); e = Cdr(e) {
-		result, err = Eval(Car(e), env)
+	if NilP(sexprs) {
+		return
+	}
+
+	e := sexprs
+	for ; NotNilP(Cdr(e)); e = Cdr(e) {
+		_, err = Eval(Car(e), env)
 		if err != nil {
 			return
 		}
 	}
-	return
+	return TailCallWithExprAndEnv(Car(e), env), nil
 }
 
 func CondImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
@@ -47,7 +54,7 @@ func CondImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	for c := args; NotNilP(c); c = Cdr(c) {
 		clause := Car(c)
 		if !PairP(clause) {
-			err = ProcessError("Cond expect a sequence of clauses that are lists", env)
+			err = ProcessTypedError(SyntaxErrorCondition, "Cond expect a sequence of clauses that are lists", env)
 			return
 		}
 		if IsEqual(Car(clause), Intern("else")) {
@@ -76,7 +83,7 @@ func CaseImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	for clauseCell := Cdr(args); NotNilP(clauseCell); clauseCell = Cdr(clauseCell) {
 		clause := Car(clauseCell)
 		if !PairP(clause) {
-			err = ProcessError("Case expectes a sequence of clauses that are lists", env)
+			err = ProcessTypedError(SyntaxErrorCondition, "Case expectes a sequence of clauses that are lists", env)
 			return
 		}
 		if IsEqual(Car(clause), Intern("else")) {
@@ -88,7 +95,7 @@ func CaseImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 				}
 			}
 		} else {
-			err = ProcessError("Case the condition part of clauses to be lists of 'else", env)
+			err = ProcessTypedError(SyntaxErrorCondition, "Case the condition part of clauses to be lists of 'else", env)
 			return
 		}
 	}
@@ -103,9 +110,9 @@ func IfImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	}
 
 	if BooleanValue(c) {
-		return Eval(Second(args), env)
+		return TailCallWithExprAndEnv(Second(args), env), nil
 	} else {
-		return Eval(Third(args), env)
+		return TailCallWithExprAndEnv(Third(args), env), nil
 	}
 }
 
@@ -116,13 +123,7 @@ func WhenImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	}
 
 	if BooleanValue(c) {
-		for cell := Cdr(args); NotNilP(cell); cell = Cdr(cell) {
-			sexpr := Car(cell)
-			result, err = Eval(sexpr, env)
-			if err != nil {
-				return
-			}
-		}
+		return evaluateBody(Cdr(args), env)
 	}
 	return
 }
@@ -134,20 +135,14 @@ func UnlessImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	}
 
 	if !BooleanValue(c) {
-		for cell := Cdr(args); NotNilP(cell); cell = Cdr(cell) {
-			sexpr := Car(cell)
-			result, err = Eval(sexpr, env)
-			if err != nil {
-				return
-			}
-		}
+		return evaluateBody(Cdr(args), env)
 	}
 	return
 }
 
 func LambdaImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	if !PairP(Car(args)) {
-		err = ProcessError("A lambda requires a parameter list", env)
+		err = ProcessTypedError(SyntaxErrorCondition, "A lambda requires a parameter list", env)
 		return
 	}
 	params := Car(args)
@@ -157,12 +152,12 @@ func LambdaImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 
 func NamedLambdaImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	if !PairP(Car(args)) {
-		err = ProcessError("A lambda requires a name/parameter list", env)
+		err = ProcessTypedError(SyntaxErrorCondition, "A lambda requires a name/parameter list", env)
 		return
 	}
 	name := Caar(args)
 	if !SymbolP(name) {
-		err = ProcessError("A named lambda requires a name that is a symbol", env)
+		err = ProcessTypedError(SyntaxErrorCondition, "A named lambda requires a name that is a symbol", env)
 		return
 	}
 	params := Cdar(args)
@@ -183,7 +178,7 @@ func DefineImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 		params := Cdr(thing)
 		thing = name
 		if !SymbolP(name) {
-			err = ProcessError("Function name has to be a symbol", env)
+			err = ProcessTypedError(SyntaxErrorCondition, "Function name has to be a symbol", env)
 			return
 		}
 		existingValueOrNil := env.ValueOf(name)
@@ -194,7 +189,7 @@ func DefineImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 		body := Cdr(args)
 		value = FunctionWithNameParamsBodyAndParent(StringValue(name), params, body, env)
 	} else {
-		err = ProcessError("Invalid definition", env)
+		err = ProcessTypedError(SyntaxErrorCondition, "Invalid definition", env)
 		return
 	}
 	_, err = env.BindLocallyTo(thing, value)
@@ -209,13 +204,13 @@ func DefmacroImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 		params := Cdr(thing)
 		thing = name
 		if !SymbolP(name) {
-			err = ProcessError("Macro name has to be a symbol", env)
+			err = ProcessTypedError(SyntaxErrorCondition, "Macro name has to be a symbol", env)
 			return
 		}
 		body := Cadr(args)
 		value = MacroWithNameParamsBodyAndParent(StringValue(name), params, body, env)
 	} else {
-		err = ProcessError("Invalid macro definition", env)
+		err = ProcessTypedError(SyntaxErrorCondition, "Invalid macro definition", env)
 		return
 	}
 	_, err = env.BindLocallyTo(thing, value)
@@ -229,12 +224,12 @@ func bindLetLocals(bindingForms *Data, rec bool, localEnv *SymbolTableFrame, eva
 	for cell := bindingForms; NotNilP(cell); cell = Cdr(cell) {
 		bindingPair := Car(cell)
 		if !PairP(bindingPair) {
-			err = ProcessError("Let requires a list of bindings (with are pairs) as it's first argument", evalEnv)
+			err = ProcessTypedError(SyntaxErrorCondition, "Let requires a list of bindings (with are pairs) as it's first argument", evalEnv)
 			return
 		}
 		name = Car(bindingPair)
 		if !SymbolP(name) {
-			err = ProcessError("First part of a let binding pair must be a symbol", evalEnv)
+			err = ProcessTypedError(SyntaxErrorCondition, "First part of a let binding pair must be a symbol", evalEnv)
 			return
 		}
 
@@ -263,7 +258,7 @@ func bindLetLocals(bindingForms *Data, rec bool, localEnv *SymbolTableFrame, eva
 
 func LetCommon(args *Data, env *SymbolTableFrame, star bool, rec bool) (result *Data, err error) {
 	if !PairP(Car(args)) {
-		err = ProcessError("Let requires a list of bindings as it's first argument", env)
+		err = ProcessTypedError(SyntaxErrorCondition, "Let requires a list of bindings as it's first argument", env)
 		return
 	}
 
@@ -280,27 +275,19 @@ func LetCommon(args *Data, env *SymbolTableFrame, star bool, rec bool) (result *
 		return
 	}
 
-	for cell := Cdr(args); NotNilP(cell); cell = Cdr(cell) {
-		sexpr := Car(cell)
-		result, err = Eval(sexpr, localEnv)
-		if err != nil {
-			return
-		}
-	}
-
-	return
+	return evaluateBody(Cdr(args), localEnv)
 }
 
 func namedLetImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	name := Car(args)
 	if !SymbolP(name) {
-		err = ProcessError("A named let requires a symbol name as its first argument", env)
+		err = ProcessTypedError(SyntaxErrorCondition, "A named let requires a symbol name as its first argument", env)
 		return
 	}
 
 	bindings := Cadr(args)
 	if !PairP(bindings) {
-		err = ProcessError("A named let requires a list of bindings as it's second argument", env)
+		err = ProcessTypedError(SyntaxErrorCondition, "A named let requires a list of bindings as it's second argument", env)
 		return
 	}
 	body := Cddr(args)
@@ -309,7 +296,7 @@ func namedLetImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	for remainingBindings := bindings; NotNilP(remainingBindings); remainingBindings = Cdr(remainingBindings) {
 		binding := Car(remainingBindings)
 		if !SymbolP(Car(binding)) {
-			err = ProcessError("The first element of a binding must be a symbol", env)
+			err = ProcessTypedError(SyntaxErrorCondition, "The first element of a binding must be a symbol", env)
 			return
 		}
 		vars = append(vars, Car(binding))
@@ -328,7 +315,7 @@ func namedLetImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	if err != nil {
 		return
 	}
-	return FunctionValue(namedLetProc).Apply(initialsList, env)
+	return TailCallWithExprAndEnv(Cons(namedLetProc, initialsList), env), nil
 }
 
 func LetImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
@@ -348,14 +335,7 @@ func LetRecImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 }
 
 func BeginImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
-	for cell := args; NotNilP(cell); cell = Cdr(cell) {
-		sexpr := Car(cell)
-		result, err = Eval(sexpr, env)
-		if err != nil {
-			return
-		}
-	}
-	return
+	return evaluateBody(args, env)
 }
 
 func rebindDoLocals(bindingForms *Data, env *SymbolTableFrame) (err error) {
@@ -391,13 +371,13 @@ func rebindDoLocals(bindingForms *Data, env *SymbolTableFrame) (err error) {
 func DoImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	bindings := Car(args)
 	if !PairP(bindings) {
-		err = ProcessError("Do requires a list of bindings as it's first argument", env)
+		err = ProcessTypedError(SyntaxErrorCondition, "Do requires a list of bindings as it's first argument", env)
 		return
 	}
 
 	testClause := Cadr(args)
 	if !PairP(testClause) {
-		err = ProcessError("Do requires a list as it's second argument", env)
+		err = ProcessTypedError(SyntaxErrorCondition, "Do requires a list as it's second argument", env)
 		return
 	}
 
@@ -419,12 +399,8 @@ func DoImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 		}
 
 		if BooleanValue(shouldExit) {
-			for cell := Cdr(testClause); NotNilP(cell); cell = Cdr(cell) {
-				sexpr := Car(cell)
-				result, err = Eval(sexpr, localEnv)
-				if err != nil {
-					return
-				}
+			if NotNilP(Cdr(testClause)) {
+				return evaluateBody(Cdr(testClause), localEnv)
 			}
 			return
 		}
@@ -437,7 +413,8 @@ func DoImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 			}
 		}
 
-		if rebindDoLocals(bindings, localEnv) != nil {
+		err = rebindDoLocals(bindings, localEnv)
+		if err != nil {
 			return
 		}
 	}
@@ -448,7 +425,7 @@ func ApplyImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	f := Car(args)
 
 	if !FunctionOrPrimitiveP(f) {
-		err = ProcessError(fmt.Sprintf("apply requires a function as it's first argument, but got %s.", String(f)), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("apply requires a function as it's first argument, but got %s.", String(f)), env)
 		return
 	}
 
@@ -461,7 +438,7 @@ func ApplyImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 			argList = ary[0]
 		}
 	} else {
-		err = ProcessError("The last argument to apply must be a list", env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, "The last argument to apply must be a list", env)
 		return
 	}
 
@@ -531,7 +508,7 @@ func DefinitionOfImpl(args *Data, env *SymbolTableFrame) (result *Data, err erro
 		return
 	}
 	if !FunctionP(f) {
-		err = ProcessError(fmt.Sprintf("code requires a function argument, but received a %s.", TypeName(TypeOf(f))), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("code requires a function argument, but received a %s.", TypeName(TypeOf(f))), env)
 		return
 	}
 
@@ -10,6 +10,10 @@ package golisp
 import (
 	"fmt"
 	"strings"
+	"unicode/utf8"
+	"unsafe"
+
+	"golang.org/x/text/unicode/norm"
 )
 
 const (
@@ -33,9 +37,27 @@ func RegisterStringPrimitives() {
 	MakePrimitiveFunction("string-length", "1", StringLengthImpl)
 	MakePrimitiveFunction("string-null?", "1", StringNullImpl)
 	MakePrimitiveFunction("substring", "3", SubstringImpl)
+	MakePrimitiveFunction("string-ref", "2", StringRefImpl)
+	MakePrimitiveFunction("string-set!", "3", StringSetImpl)
+	MakePrimitiveFunction("string->list", "1", StringToListImpl)
+	MakePrimitiveFunction("list->string", "1", ListToStringImpl)
+	MakePrimitiveFunction("string-for-each", "2", StringForEachImpl)
 	MakePrimitiveFunction("substring?", "2", SubstringpImpl)
 	MakePrimitiveFunction("string-prefix?", "2", StringPrefixpImpl)
 	MakePrimitiveFunction("string-suffix?", "2", StringSuffixpImpl)
+	MakePrimitiveFunction("string-search-forward", "3", StringSearchForwardImpl)
+	MakePrimitiveFunction("string-search-backward", "3", StringSearchBackwardImpl)
+	MakePrimitiveFunction("string-search-all", "2", StringSearchAllImpl)
+	MakePrimitiveFunction("string-index", "2", StringIndexImpl)
+	MakePrimitiveFunction("string-pad-left", "2|3", StringPadLeftImpl)
+	MakePrimitiveFunction("string-pad-right", "2|3", StringPadRightImpl)
+	MakePrimitiveFunction("string-reverse", "1", StringReverseImpl)
+	MakePrimitiveFunction("string-normalize-nfc", "1", StringNormalizeNfcImpl)
+	MakePrimitiveFunction("string-normalize-nfd", "1", StringNormalizeNfdImpl)
+	MakePrimitiveFunction("string-normalize-nfkc", "1", StringNormalizeNfkcImpl)
+	MakePrimitiveFunction("string-normalize-nfkd", "1", StringNormalizeNfkdImpl)
+	MakePrimitiveFunction("string->utf8", "1", StringToUtf8Impl)
+	MakePrimitiveFunction("utf8->string", "1", Utf8ToStringImpl)
 
 	MakePrimitiveFunction("string=?", "2", StringEqualImpl)
 	MakePrimitiveFunction("string-ci=?", "2", StringEqualCiImpl)
@@ -54,13 +76,13 @@ func RegisterStringPrimitives() {
 func StringSplitImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	theString := Car(args)
 	if !StringP(theString) {
-		err = ProcessError(fmt.Sprintf("trim requires a string but was given %s.", String(theString)), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("trim requires a string but was given %s.", String(theString)), env)
 		return
 	}
 
 	theSeparator := Cadr(args)
 	if !StringP(theSeparator) {
-		err = ProcessError(fmt.Sprintf("string-split requires a string separater but was given %s.", String(theSeparator)), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("string-split requires a string separater but was given %s.", String(theSeparator)), env)
 		return
 	}
 
@@ -75,7 +97,7 @@ func StringSplitImpl(args *Data, env *SymbolTableFrame) (result *Data, err error
 func StringJoinImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	theStrings := Car(args)
 	if !ListP(theStrings) {
-		err = ProcessError(fmt.Sprintf("string-join requires a list of strings to be joined but was given %s.", String(theStrings)), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("string-join requires a list of strings to be joined but was given %s.", String(theStrings)), env)
 		return
 	}
 
@@ -83,7 +105,7 @@ func StringJoinImpl(args *Data, env *SymbolTableFrame) (result *Data, err error)
 	separator := ""
 	if !NilP(theSeparator) {
 		if !StringP(theSeparator) {
-			err = ProcessError(fmt.Sprintf("string-join requires a string separater but was given %s.", String(theSeparator)), env)
+			err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("string-join requires a string separater but was given %s.", String(theSeparator)), env)
 			return
 		}
 		separator = StringValue(theSeparator)
@@ -93,7 +115,7 @@ func StringJoinImpl(args *Data, env *SymbolTableFrame) (result *Data, err error)
 	for c := theStrings; NotNilP(c); c = Cdr(c) {
 		val := Car(c)
 		if !StringP(val) {
-			err = ProcessError(fmt.Sprintf("string-join requires a list of strings but %s was in the list.", String(val)), env)
+			err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("string-join requires a list of strings but %s was in the list.", String(val)), env)
 			return
 		}
 		resultStrings = append(resultStrings, StringValue(val))
@@ -106,7 +128,7 @@ func doTrim(lrb int, args *Data, env *SymbolTableFrame) (result *Data, err error
 	theString := Car(args)
 
 	if !StringP(theString) {
-		err = ProcessError(fmt.Sprintf("string-trim requires a string but was given %s.", String(theString)), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("string-trim requires a string but was given %s.", String(theString)), env)
 		return
 	}
 
@@ -114,7 +136,7 @@ func doTrim(lrb int, args *Data, env *SymbolTableFrame) (result *Data, err error
 	if Length(args) == 2 {
 		theTrimSet := Cadr(args)
 		if !StringP(theTrimSet) {
-			err = ProcessError(fmt.Sprintf("string-trim requires a string set of trim characters but was given %s.", String(theTrimSet)), env)
+			err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("string-trim requires a string set of trim characters but was given %s.", String(theTrimSet)), env)
 			return
 		}
 
@@ -149,7 +171,7 @@ func StringTrimRightImpl(args *Data, env *SymbolTableFrame) (result *Data, err e
 func StringUpcaseImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	theString := Car(args)
 	if !StringP(theString) {
-		err = ProcessError(fmt.Sprintf("string-upcase requires a string but was given %s.", String(theString)), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("string-upcase requires a string but was given %s.", String(theString)), env)
 		return
 	}
 	return StringWithValue(strings.ToUpper(StringValue(theString))), nil
@@ -158,7 +180,7 @@ func StringUpcaseImpl(args *Data, env *SymbolTableFrame) (result *Data, err erro
 func StringUpcaseBangImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	theString := Car(args)
 	if !StringP(theString) {
-		err = ProcessError(fmt.Sprintf("string-upcase! requires a string but was given %s.", String(theString)), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("string-upcase! requires a string but was given %s.", String(theString)), env)
 		return
 	}
 	return SetStringValue(theString, strings.ToUpper(StringValue(theString))), nil
@@ -167,7 +189,7 @@ func StringUpcaseBangImpl(args *Data, env *SymbolTableFrame) (result *Data, err
 func StringDowncaseImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	theString := Car(args)
 	if !StringP(theString) {
-		err = ProcessError(fmt.Sprintf("string-downcase requires a string but was given %s.", String(theString)), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("string-downcase requires a string but was given %s.", String(theString)), env)
 		return
 	}
 	return StringWithValue(strings.ToLower(StringValue(theString))), nil
@@ -176,7 +198,7 @@ func StringDowncaseImpl(args *Data, env *SymbolTableFrame) (result *Data, err er
 func StringDowncaseBangImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	theString := Car(args)
 	if !StringP(theString) {
-		err = ProcessError(fmt.Sprintf("string-downcase! requires a string but was given %s.", String(theString)), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("string-downcase! requires a string but was given %s.", String(theString)), env)
 		return
 	}
 	return SetStringValue(theString, strings.ToLower(StringValue(theString))), nil
@@ -195,7 +217,7 @@ func StringCapitalizeImpl(args *Data, env *SymbolTableFrame) (result *Data, err
 	theString := Car(args)
 
 	if !StringP(theString) {
-		err = ProcessError(fmt.Sprintf("string-capitalize requires a string but was given %s.", String(theString)), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("string-capitalize requires a string but was given %s.", String(theString)), env)
 		return
 	}
 	return StringWithValue(capitalize(StringValue(theString))), nil
@@ -205,7 +227,7 @@ func StringCapitalizeBangImpl(args *Data, env *SymbolTableFrame) (result *Data,
 	theString := Car(args)
 
 	if !StringP(theString) {
-		err = ProcessError(fmt.Sprintf("string-capitalize! requires a string but was given %s.", String(theString)), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("string-capitalize! requires a string but was given %s.", String(theString)), env)
 		return
 	}
 	return SetStringValue(theString, capitalize(StringValue(theString))), nil
@@ -215,17 +237,17 @@ func StringLengthImpl(args *Data, env *SymbolTableFrame) (result *Data, err erro
 	theString := Car(args)
 
 	if !StringP(theString) {
-		err = ProcessError(fmt.Sprintf("string-length requires a string but was given %s.", String(theString)), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("string-length requires a string but was given %s.", String(theString)), env)
 		return
 	}
-	return IntegerWithValue(int64(len(StringValue(theString)))), nil
+	return IntegerWithValue(int64(utf8.RuneCountInString(StringValue(theString)))), nil
 }
 
 func StringNullImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	theString := Car(args)
 
 	if !StringP(theString) {
-		err = ProcessError(fmt.Sprintf("string-null? requires a string but was given %s.", String(theString)), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("string-null? requires a string but was given %s.", String(theString)), env)
 		return
 	}
 	return BooleanWithValue(len(StringValue(theString)) == 0), nil
@@ -234,52 +256,151 @@ func StringNullImpl(args *Data, env *SymbolTableFrame) (result *Data, err error)
 func SubstringImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	theString := Car(args)
 	if !StringP(theString) {
-		err = ProcessError(fmt.Sprintf("substring requires a string but was given %s.", String(theString)), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("substring requires a string but was given %s.", String(theString)), env)
 		return
 	}
-	stringValue := StringValue(theString)
+	chars := []rune(StringValue(theString))
 
 	startObj := Cadr(args)
 	if !IntegerP(startObj) {
-		err = ProcessError(fmt.Sprintf("substring requires integer start but was given %s.", String(startObj)), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("substring requires integer start but was given %s.", String(startObj)), env)
 		return
 	}
 	startValue := int(IntegerValue(startObj))
-	if startValue > len(stringValue) {
-		err = ProcessError(fmt.Sprintf("substring requires start < length of the string."), env)
+	if startValue < 0 || startValue > len(chars) {
+		err = ProcessTypedError(BadRangeArgumentCondition, fmt.Sprintf("substring requires start < length of the string."), env)
 		return
 	}
 
 	endObj := Caddr(args)
 	if !IntegerP(endObj) {
-		err = ProcessError(fmt.Sprintf("substring requires integer end but was given %s.", String(endObj)), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("substring requires integer end but was given %s.", String(endObj)), env)
 		return
 	}
 	endValue := int(IntegerValue(endObj))
-	if endValue > len(stringValue) {
-		err = ProcessError(fmt.Sprintf("substring requires end < length of the string."), env)
+	if endValue < 0 || endValue > len(chars) {
+		err = ProcessTypedError(BadRangeArgumentCondition, fmt.Sprintf("substring requires end < length of the string."), env)
 		return
 	}
 
 	if startValue > endValue {
-		err = ProcessError(fmt.Sprintf("substring requires start <= end."), env)
+		err = ProcessTypedError(BadRangeArgumentCondition, fmt.Sprintf("substring requires start <= end."), env)
+		return
+	}
+
+	return StringWithValue(string(chars[startValue:endValue])), nil
+}
+
+// The characters of a string argument and the index into them given as the
+// second argument.
+func stringIndexArgs(name string, args *Data, env *SymbolTableFrame) (chars []rune, index int, err error) {
+	theString := Car(args)
+	if !StringP(theString) {
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("%s requires a string but was given %s.", name, String(theString)), env)
+		return
+	}
+	chars = []rune(StringValue(theString))
+
+	indexObj := Cadr(args)
+	if !IntegerP(indexObj) {
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("%s requires an integer index but was given %s.", name, String(indexObj)), env)
+		return
+	}
+	index = int(IntegerValue(indexObj))
+	if index < 0 || index >= len(chars) {
+		err = ProcessTypedError(BadRangeArgumentCondition, fmt.Sprintf("%s requires 0 <= index < length of the string but was given %d.", name, index), env)
+	}
+	return
+}
+
+func StringRefImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	chars, index, err := stringIndexArgs("string-ref", args, env)
+	if err != nil {
+		return
+	}
+	return CharacterWithValue(chars[index]), nil
+}
+
+func StringSetImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	chars, index, err := stringIndexArgs("string-set!", args, env)
+	if err != nil {
+		return
+	}
+
+	ch := Caddr(args)
+	if !CharacterP(ch) {
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("string-set! requires a character but was given %s.", String(ch)), env)
+		return
+	}
+	chars[index] = CharacterValue(ch)
+	return SetStringValue(Car(args), string(chars)), nil
+}
+
+func StringToListImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	theString := Car(args)
+	if !StringP(theString) {
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("string->list requires a string but was given %s.", String(theString)), env)
+		return
+	}
+
+	chars := make([]*Data, 0, len(StringValue(theString)))
+	for _, ch := range StringValue(theString) {
+		chars = append(chars, CharacterWithValue(ch))
+	}
+	return ArrayToList(chars), nil
+}
+
+func ListToStringImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	theList := Car(args)
+	if !ListP(theList) {
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("list->string requires a list of characters but was given %s.", String(theList)), env)
+		return
+	}
+
+	chars := make([]rune, 0, Length(theList))
+	for c := theList; NotNilP(c); c = Cdr(c) {
+		if !CharacterP(Car(c)) {
+			err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("list->string requires a list of characters but %s was in the list.", String(Car(c))), env)
+			return
+		}
+		chars = append(chars, CharacterValue(Car(c)))
+	}
+	return StringWithValue(string(chars)), nil
+}
+
+func StringForEachImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	f := Car(args)
+	if !FunctionOrPrimitiveP(f) {
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("string-for-each requires a function but was given %s.", String(f)), env)
 		return
 	}
 
-	return StringWithValue(stringValue[startValue:endValue]), nil
+	theString := Cadr(args)
+	if !StringP(theString) {
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("string-for-each requires a string but was given %s.", String(theString)), env)
+		return
+	}
+
+	for _, ch := range StringValue(theString) {
+		_, err = ApplyWithoutEval(f, InternalMakeList(CharacterWithValue(ch)), env)
+		if err != nil {
+			return
+		}
+	}
+	return
 }
 
 func SubstringpImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	substringObj := Car(args)
 	if !StringP(substringObj) {
-		err = ProcessError(fmt.Sprintf("substring? requires strings but was given %s.", String(substringObj)), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("substring? requires strings but was given %s.", String(substringObj)), env)
 		return
 	}
 	substringValue := StringValue(substringObj)
 
 	theString := Cadr(args)
 	if !StringP(theString) {
-		err = ProcessError(fmt.Sprintf("substring? requires strings but was given %s.", String(theString)), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("substring? requires strings but was given %s.", String(theString)), env)
 		return
 	}
 	stringValue := StringValue(theString)
@@ -290,14 +411,14 @@ func SubstringpImpl(args *Data, env *SymbolTableFrame) (result *Data, err error)
 func StringPrefixpImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	prefixObj := Car(args)
 	if !StringP(prefixObj) {
-		err = ProcessError(fmt.Sprintf("string-prefix? requires a string but was given %s.", String(prefixObj)), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("string-prefix? requires a string but was given %s.", String(prefixObj)), env)
 		return
 	}
 	prefixValue := StringValue(prefixObj)
 
 	theString := Cadr(args)
 	if !StringP(theString) {
-		err = ProcessError(fmt.Sprintf("string-prefix? requires a string but was given %s.", String(theString)), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("string-prefix? requires a string but was given %s.", String(theString)), env)
 		return
 	}
 	stringValue := StringValue(theString)
@@ -308,14 +429,14 @@ func StringPrefixpImpl(args *Data, env *SymbolTableFrame) (result *Data, err err
 func StringSuffixpImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	suffixObj := Car(args)
 	if !StringP(suffixObj) {
-		err = ProcessError(fmt.Sprintf("string-suffix? requires a string but was given %s.", String(suffixObj)), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("string-suffix? requires a string but was given %s.", String(suffixObj)), env)
 		return
 	}
 	suffixValue := StringValue(suffixObj)
 
 	theString := Cadr(args)
 	if !StringP(theString) {
-		err = ProcessError(fmt.Sprintf("string-suffix? requires a string but was given %s.", String(theString)), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("string-suffix? requires a string but was given %s.", String(theString)), env)
 		return
 	}
 	stringValue := StringValue(theString)
@@ -323,10 +444,248 @@ func StringSuffixpImpl(args *Data, env *SymbolTableFrame) (result *Data, err err
 	return BooleanWithValue(strings.HasSuffix(stringValue, suffixValue)), nil
 }
 
+// The pattern, the characters of the string it is searched for in and the
+// position given as the third argument.
+func stringSearchArgs(name string, args *Data, env *SymbolTableFrame) (pattern []rune, chars []rune, position int, err error) {
+	patternObj := Car(args)
+	if !StringP(patternObj) {
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("%s requires a string pattern but was given %s.", name, String(patternObj)), env)
+		return
+	}
+	pattern = []rune(StringValue(patternObj))
+
+	theString := Cadr(args)
+	if !StringP(theString) {
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("%s requires a string but was given %s.", name, String(theString)), env)
+		return
+	}
+	chars = []rune(StringValue(theString))
+
+	if Length(args) < 3 {
+		return
+	}
+	positionObj := Caddr(args)
+	if !IntegerP(positionObj) {
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("%s requires an integer position but was given %s.", name, String(positionObj)), env)
+		return
+	}
+	position = int(IntegerValue(positionObj))
+	if position < 0 || position > len(chars) {
+		err = ProcessTypedError(BadRangeArgumentCondition, fmt.Sprintf("%s requires 0 <= position <= length of the string but was given %d.", name, position), env)
+	}
+	return
+}
+
+func runesMatchAt(pattern []rune, chars []rune, index int) bool {
+	if index+len(pattern) > len(chars) {
+		return false
+	}
+	for i, ch := range pattern {
+		if chars[index+i] != ch {
+			return false
+		}
+	}
+	return true
+}
+
+// The index of the first match of the pattern at or after start, or #f.
+func StringSearchForwardImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	pattern, chars, start, err := stringSearchArgs("string-search-forward", args, env)
+	if err != nil {
+		return
+	}
+
+	for i := start; i+len(pattern) <= len(chars); i++ {
+		if runesMatchAt(pattern, chars, i) {
+			return IntegerWithValue(int64(i)), nil
+		}
+	}
+	return LispFalse, nil
+}
+
+// The index after the last match of the pattern that ends at or before end,
+// or #f.
+func StringSearchBackwardImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	pattern, chars, end, err := stringSearchArgs("string-search-backward", args, env)
+	if err != nil {
+		return
+	}
+
+	for i := end - len(pattern); i >= 0; i-- {
+		if runesMatchAt(pattern, chars, i) {
+			return IntegerWithValue(int64(i + len(pattern))), nil
+		}
+	}
+	return LispFalse, nil
+}
+
+// The indexes of every match of the pattern, including overlapping ones.
+func StringSearchAllImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	pattern, chars, _, err := stringSearchArgs("string-search-all", args, env)
+	if err != nil {
+		return
+	}
+
+	indexes := make([]*Data, 0)
+	for i := 0; i+len(pattern) <= len(chars); i++ {
+		if runesMatchAt(pattern, chars, i) {
+			indexes = append(indexes, IntegerWithValue(int64(i)))
+		}
+	}
+	return ArrayToList(indexes), nil
+}
+
+// The index of the first character that is the given character or satisfies
+// the given predicate, or #f.
+func StringIndexImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	theString := Car(args)
+	if !StringP(theString) {
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("string-index requires a string but was given %s.", String(theString)), env)
+		return
+	}
+
+	matcher := Cadr(args)
+	if !CharacterP(matcher) && !FunctionOrPrimitiveP(matcher) {
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("string-index requires a character or predicate but was given %s.", String(matcher)), env)
+		return
+	}
+
+	for i, ch := range []rune(StringValue(theString)) {
+		var found bool
+		if CharacterP(matcher) {
+			found = ch == CharacterValue(matcher)
+		} else {
+			var matched *Data
+			matched, err = ApplyWithoutEval(matcher, InternalMakeList(CharacterWithValue(ch)), env)
+			if err != nil {
+				return
+			}
+			found = BooleanValue(matched)
+		}
+		if found {
+			return IntegerWithValue(int64(i)), nil
+		}
+	}
+	return LispFalse, nil
+}
+
+// Pads a string to n characters, or truncates it to n characters, on the
+// left or the right. Truncating on the left keeps the end of the string.
+func padString(name string, left bool, args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	theString := Car(args)
+	if !StringP(theString) {
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("%s requires a string but was given %s.", name, String(theString)), env)
+		return
+	}
+	chars := []rune(StringValue(theString))
+
+	nObj := Cadr(args)
+	if !IntegerP(nObj) || IntegerValue(nObj) < 0 {
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("%s requires a non-negative integer length but was given %s.", name, String(nObj)), env)
+		return
+	}
+	n := int(IntegerValue(nObj))
+
+	padChar := ' '
+	if Length(args) == 3 {
+		if !CharacterP(Caddr(args)) {
+			err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("%s requires a character but was given %s.", name, String(Caddr(args))), env)
+			return
+		}
+		padChar = CharacterValue(Caddr(args))
+	}
+
+	if n <= len(chars) {
+		if left {
+			return StringWithValue(string(chars[len(chars)-n:])), nil
+		}
+		return StringWithValue(string(chars[:n])), nil
+	}
+
+	padding := strings.Repeat(string(padChar), n-len(chars))
+	if left {
+		return StringWithValue(padding + string(chars)), nil
+	}
+	return StringWithValue(string(chars) + padding), nil
+}
+
+func StringPadLeftImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	return padString("string-pad-left", true, args, env)
+}
+
+func StringPadRightImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	return padString("string-pad-right", false, args, env)
+}
+
+func StringReverseImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	theString := Car(args)
+	if !StringP(theString) {
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("string-reverse requires a string but was given %s.", String(theString)), env)
+		return
+	}
+
+	chars := []rune(StringValue(theString))
+	for i, j := 0, len(chars)-1; i < j; i, j = i+1, j-1 {
+		chars[i], chars[j] = chars[j], chars[i]
+	}
+	return StringWithValue(string(chars)), nil
+}
+
+func normalizeString(name string, form norm.Form, args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	theString := Car(args)
+	if !StringP(theString) {
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("%s requires a string but was given %s.", name, String(theString)), env)
+		return
+	}
+	return StringWithValue(form.String(StringValue(theString))), nil
+}
+
+func StringNormalizeNfcImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	return normalizeString("string-normalize-nfc", norm.NFC, args, env)
+}
+
+func StringNormalizeNfdImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	return normalizeString("string-normalize-nfd", norm.NFD, args, env)
+}
+
+func StringNormalizeNfkcImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	return normalizeString("string-normalize-nfkc", norm.NFKC, args, env)
+}
+
+func StringNormalizeNfkdImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	return normalizeString("string-normalize-nfkd", norm.NFKD, args, env)
+}
+
+func StringToUtf8Impl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	theString := Car(args)
+	if !StringP(theString) {
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("string->utf8 requires a string but was given %s.", String(theString)), env)
+		return
+	}
+
+	bytes := []byte(StringValue(theString))
+	return ObjectWithTypeAndValue("[]byte", unsafe.Pointer(&bytes)), nil
+}
+
+func Utf8ToStringImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	bytes := Car(args)
+	if !ObjectP(bytes) || ObjectType(bytes) != "[]byte" {
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("utf8->string requires a bytearray but was given %s.", String(bytes)), env)
+		return
+	}
+
+	value := *(*[]byte)(ObjectValue(bytes))
+	if !utf8.Valid(value) {
+		err = ProcessError(fmt.Sprintf("utf8->string was given bytes that aren't valid UTF-8: %s.", String(bytes)), env)
+		return
+	}
+	return StringWithValue(string(value)), nil
+}
+
 func stringProcessArgs(name string, caseInsensitive bool, args *Data, env *SymbolTableFrame) (string1 string, string2 string, err error) {
 	string1Obj := Car(args)
 	if !StringP(string1Obj) {
-		err = ProcessError(fmt.Sprintf("%s requires a string but was given %s.", name, String(string1Obj)), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("%s requires a string but was given %s.", name, String(string1Obj)), env)
 		return
 	}
 	if caseInsensitive {
@@ -337,7 +696,7 @@ func stringProcessArgs(name string, caseInsensitive bool, args *Data, env *Symbo
 
 	string2Obj := Cadr(args)
 	if !StringP(string2Obj) {
-		err = ProcessError(fmt.Sprintf("%s requires a string but was given %s.", name, String(string2Obj)), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("%s requires a string but was given %s.", name, String(string2Obj)), env)
 		return
 	}
 
@@ -11,7 +11,6 @@ import (
 	"fmt"
 	"math/rand"
 	"os"
-	"os/exec"
 	"strings"
 	"sync"
 	"time"
@@ -35,19 +34,23 @@ func RegisterSystemPrimitives() {
 	MakeRestrictedPrimitiveFunction("load", "1", LoadFileImpl)
 	MakeRestrictedPrimitiveFunction("global-eval", "1", GlobalEvalImpl)
 	MakeRestrictedPrimitiveFunction("panic!", "1", PanicImpl)
-	MakePrimitiveFunction("error", "1", ErrorImpl)
+	MakePrimitiveFunction("error", ">=1", ErrorImpl)
 	MakeSpecialForm("on-error", "2|3", OnErrorImpl)
 
 	MakeSpecialForm("time", "1", TimeImpl)
 	MakeSpecialForm("profile", "1|2", ProfileImpl)
 
 	MakeRestrictedPrimitiveFunction("exec", ">=1", ExecImpl)
+	MakeRestrictedPrimitiveFunction("exec-wait", ">=1", ExecWaitImpl)
+	MakeRestrictedPrimitiveFunction("run-shell-command", "1|2", RunShellCommandImpl)
+	MakeRestrictedPrimitiveFunction("process-wait", "1", ProcessWaitImpl)
+	MakeRestrictedPrimitiveFunction("process-kill", "1", ProcessKillImpl)
 }
 
 func LoadFileImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	filename := Car(args)
 	if !StringP(filename) {
-		err = ProcessError("Filename must be a string", env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, "Filename must be a string", env)
 		return
 	}
 
@@ -75,11 +78,14 @@ func PanicImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 }
 
 func ErrorImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
-	return nil, ProcessError(String(Car(args)), env)
+	return nil, ProcessCondition(MakeCondition(SimpleErrorCondition, conditionMessage(Car(args)), Cdr(args)), env)
 }
 
 func OnErrorImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
-	result, errThrown := Eval(Car(args), env)
+	result, errThrown := evaluateGuarded(InternalMakeList(Car(args)), env)
+	if IsContinuationInvocation(errThrown) {
+		return nil, errThrown
+	}
 	if errThrown == nil {
 		if Length(args) == 3 {
 			f, err := Eval(Caddr(args), env)
@@ -87,7 +93,7 @@ func OnErrorImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 				return nil, err
 			}
 			if !FunctionP(f) {
-				return nil, ProcessError("on-error requires a function as it's third argument", env)
+				return nil, ProcessTypedError(WrongTypeArgumentCondition, "on-error requires a function as it's third argument", env)
 			}
 			noErrHandler := FunctionValue(f)
 			return noErrHandler.Apply(nil, env)
@@ -103,12 +109,15 @@ func OnErrorImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	}
 
 	if !FunctionP(f) {
-		err = ProcessError("on-error requires a function as it's second argument", env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, "on-error requires a function as it's second argument", env)
 		return
 	}
 	handler := FunctionValue(f)
-	errString := StringWithValue(errThrown.Error())
-	return handler.Apply(InternalMakeList(errString), env)
+	condition := ConditionObject(errThrown)
+	if handler.RequiredArgCount == 2 {
+		return handler.ApplyWithoutEval(InternalMakeList(condition, BacktraceOf(errThrown).ToLisp()), env)
+	}
+	return handler.ApplyWithoutEval(InternalMakeList(condition), env)
 }
 
 func QuitImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
@@ -124,7 +133,7 @@ func QuitImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 func SleepImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	n := Car(args)
 	if !IntegerP(n) {
-		err = ProcessError(fmt.Sprintf("Number expected, received %s", String(n)), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("Number expected, received %s", String(n)), env)
 		return
 	}
 	millis := IntegerValue(n)
@@ -149,12 +158,14 @@ func concatStringForms(args *Data) (str string) {
 }
 
 func WriteLineImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
-	println(concatStringForms(args))
+	_, err = PortValue(CurrentOutputPort(env)).WriteString(concatStringForms(args) + "\n")
 	return
 }
 
 func WriteLogImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
-	LogPrintf("%s\r\n", concatStringForms(args))
+	line := concatStringForms(args)
+	_, err = PortValue(CurrentOutputPort(env)).WriteString(line + "\r\n")
+	logToLoggers("%s\r\n", line)
 	return
 }
 
@@ -181,7 +192,7 @@ func TimeImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 func InternImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	sym := Car(args)
 	if !StringP(sym) {
-		err = ProcessError(fmt.Sprintf("intern expects a string, but received %s.", String(sym)), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("intern expects a string, but received %s.", String(sym)), env)
 		return
 	}
 
@@ -190,7 +201,7 @@ func InternImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 
 func gensymHelper(primitiveName string, args *Data, env *SymbolTableFrame) (prefix string, count int, err error) {
 	if Length(args) > 1 {
-		err = ProcessError(fmt.Sprintf("%s expects 0 or 1 argument, but received %d.", primitiveName, Length(args)), env)
+		err = ProcessTypedError(WrongNumberOfArgumentsCondition, fmt.Sprintf("%s expects 0 or 1 argument, but received %d.", primitiveName, Length(args)), env)
 		return
 	}
 
@@ -199,12 +210,17 @@ func gensymHelper(primitiveName string, args *Data, env *SymbolTableFrame) (pref
 	} else {
 		arg := Car(args)
 		if !StringP(arg) && !SymbolP(arg) {
-			err = ProcessError(fmt.Sprintf("%s expects a string or symbol, but recieved %s.", primitiveName, String(arg)), env)
+			err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("%s expects a string or symbol, but recieved %s.", primitiveName, String(arg)), env)
 			return
 		}
 		prefix = StringValue(arg)
 	}
 
+	count = nextSymbolCount(prefix)
+	return
+}
+
+func nextSymbolCount(prefix string) (count int) {
 	symbolCountsMutex.Lock()
 
 	count = symbolCounts[prefix]
@@ -214,6 +230,11 @@ func gensymHelper(primitiveName string, args *Data, env *SymbolTableFrame) (pref
 	return
 }
 
+// An uninterned symbol named after prefix, as gensym makes.
+func gensymNamed(prefix string) *Data {
+	return SymbolWithName(fmt.Sprintf("%s-%d", prefix, nextSymbolCount(prefix)))
+}
+
 func GensymImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	prefix, count, err := gensymHelper("gensym", args, env)
 	if err != nil {
@@ -237,7 +258,7 @@ func EvalImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	sexpr := Car(args)
 	if Length(args) == 2 {
 		if !EnvironmentP(Cadr(args)) {
-			err = ProcessError(fmt.Sprintf("eval expects an environment as it's second argument, but recieved %s.", String(Cadr(args))), env)
+			err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("eval expects an environment as it's second argument, but recieved %s.", String(Cadr(args))), env)
 			return
 		}
 		evalEnv = EnvironmentValue(Cadr(args))
@@ -254,7 +275,7 @@ func GlobalEvalImpl(args *Data, env *SymbolTableFrame) (result *Data, err error)
 func ProfileImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	if Length(args) == 2 {
 		if !StringP(Cadr(args)) {
-			err = ProcessError(fmt.Sprintf("profile requires a string filename, but received %s.", String(Cadr(args))), env)
+			err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("profile requires a string filename, but received %s.", String(Cadr(args))), env)
 		}
 		StartProfiling(StringValue(Cadr(args)))
 	} else {
@@ -268,29 +289,155 @@ func ProfileImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	return
 }
 
-func ExecImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+// Reads the options frame that may end the arguments of exec, exec-wait and
+// run-shell-command: stdin: is a string or bytearray, env: an alist of
+// variables added to the environment, dir: the working directory, timeout: a
+// time limit in milliseconds and capture: whether exec keeps the output.
+func childProcessOptions(name string, f *Data, env *SymbolTableFrame) (options ChildProcessOptions, err error) {
+	frame := FrameValue(f)
+
+	if stdin := frame.Get("stdin:"); stdin != nil {
+		if StringP(stdin) {
+			options.Stdin = []byte(StringValue(stdin))
+		} else if ObjectP(stdin) && ObjectType(stdin) == "[]byte" {
+			options.Stdin = *(*[]byte)(ObjectValue(stdin))
+		} else {
+			err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("%s expects stdin: to be a string or bytearray, but was %s.", name, String(stdin)), env)
+			return
+		}
+	}
+
+	if vars := frame.Get("env:"); vars != nil {
+		options.Env = make([]string, 0, Length(vars))
+		for c := vars; NotNilP(c); c = Cdr(c) {
+			pair := Car(c)
+			if !PairP(pair) && !DottedPairP(pair) {
+				err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("%s expects env: to be an alist, but was %s.", name, String(vars)), env)
+				return
+			}
+			options.Env = append(options.Env, fmt.Sprintf("%s=%s", commandArgument(Car(pair)), commandArgument(Cdr(pair))))
+		}
+	}
+
+	if dir := frame.Get("dir:"); dir != nil {
+		if !StringP(dir) {
+			err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("%s expects dir: to be a string, but was %s.", name, String(dir)), env)
+			return
+		}
+		options.Dir = StringValue(dir)
+	}
+
+	if timeout := frame.Get("timeout:"); timeout != nil {
+		if !IntegerP(timeout) || IntegerValue(timeout) <= 0 {
+			err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("%s expects timeout: to be a positive number of milliseconds, but was %s.", name, String(timeout)), env)
+			return
+		}
+		options.Timeout = time.Duration(IntegerValue(timeout)) * time.Millisecond
+	}
+
+	if capture := frame.Get("capture:"); capture != nil {
+		options.Capture = BooleanValue(capture)
+	}
+	return
+}
+
+func commandArgument(d *Data) string {
+	if StringP(d) || SymbolP(d) {
+		return StringValue(d)
+	}
+	return String(d)
+}
+
+// Starts the command given by args, which may end with an options frame.
+// Output is captured if capture is set or the options ask for it, and the
+// command gets its own process group if it can be killed with process-kill.
+func startCommand(name string, args *Data, capture bool, killable bool, env *SymbolTableFrame) (child *ChildProcess, err error) {
 	if !StringP(First(args)) {
-		err = ProcessError(fmt.Sprintf("exec requires a string command, but received %s.", String(First(args))), env)
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("%s requires a string command, but received %s.", name, String(First(args))), env)
+		return
 	}
 	cmdString := StringValue(First(args))
 
+	options := ChildProcessOptions{Capture: capture, KillGroup: killable}
 	cmdArgs := make([]string, 0, Length(args)-1)
+	for cell := Cdr(args); !NilP(cell); cell = Cdr(cell) {
+		value := Car(cell)
+		if FrameP(value) && NilP(Cdr(cell)) {
+			options, err = childProcessOptions(name, value, env)
+			if err != nil {
+				return
+			}
+			options.Capture = options.Capture || capture
+			options.KillGroup = killable
+		} else {
+			cmdArgs = append(cmdArgs, commandArgument(value))
+		}
+	}
 
-	var cmd *exec.Cmd
+	return StartChildProcess(cmdString, cmdArgs, options)
+}
 
-	if Length(args) > 1 {
-		for cell := Cdr(args); !NilP(cell); cell = Cdr(cell) {
-			value := Car(cell)
-			if StringP(value) || SymbolP(value) {
-				cmdArgs = append(cmdArgs, StringValue(value))
-			} else {
-				cmdArgs = append(cmdArgs, String(value))
-			}
+// Starts a command without waiting for it, returning a process handle for
+// process-wait and process-kill. Its output is discarded unless the options
+// set capture:, since a long running command could produce any amount of it.
+func ExecImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	child, err := startCommand("exec", args, false, true, env)
+	if err != nil {
+		return
+	}
+	return ChildProcessWithValue(child), nil
+}
+
+func ExecWaitImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	child, err := startCommand("exec-wait", args, true, false, env)
+	if err != nil {
+		return
+	}
+	return child.Wait(), nil
+}
+
+func RunShellCommandImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	command := First(args)
+	if !StringP(command) {
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("run-shell-command requires a string command, but received %s.", String(command)), env)
+		return
+	}
+
+	var options ChildProcessOptions
+	if Length(args) == 2 {
+		if !FrameP(Second(args)) {
+			err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("run-shell-command expects its options to be a frame, but received %s.", String(Second(args))), env)
+			return
 		}
-		cmd = exec.Command(cmdString, cmdArgs...)
-	} else {
-		cmd = exec.Command(cmdString)
+		options, err = childProcessOptions("run-shell-command", Second(args), env)
+		if err != nil {
+			return
+		}
+	}
+	options.Capture = true
+
+	child, err := StartShellCommand(StringValue(command), options)
+	if err != nil {
+		return
+	}
+	return child.Wait(), nil
+}
+
+func ProcessWaitImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	p := First(args)
+	if !ChildProcessP(p) {
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("process-wait requires a process from exec, but received %s.", String(p)), env)
+		return
+	}
+	return ChildProcessValue(p).Wait(), nil
+}
+
+func ProcessKillImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	p := First(args)
+	if !ChildProcessP(p) {
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("process-kill requires a process from exec, but received %s.", String(p)), env)
+		return
 	}
-	err = cmd.Start()
+	err = ChildProcessValue(p).Kill()
 	return
 }
@@ -21,17 +21,20 @@ func RegisterTypePredicatePrimitives() {
 	MakePrimitiveFunction("integer?", "1", IsIntegerImpl)
 	MakePrimitiveFunction("number?", "1", IsNumberImpl)
 	MakePrimitiveFunction("float?", "1", IsFloatImpl)
+	MakePrimitiveFunction("rational?", "1", IsRationalImpl)
 	MakePrimitiveFunction("function?", "1", IsFunctionImpl)
 	MakePrimitiveFunction("macro?", "1", IsMacroImpl)
 	MakePrimitiveFunction("frame?", "1", IsFrameImpl)
 	MakePrimitiveFunction("bytearray?", "1", IsByteArrayImpl)
 	MakePrimitiveFunction("port?", "1", IsPortImpl)
 	MakePrimitiveFunction("boolean?", "1", IsBooleanImpl)
+	MakePrimitiveFunction("record?", "1", IsRecordImpl)
+	MakePrimitiveFunction("record-type?", "1", IsRecordTypeImpl)
 }
 
 func IsAtomImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	val := Car(args)
-	return BooleanWithValue(NumberP(val) || SymbolP(val) || StringP(val) || BooleanP(val)), nil
+	return BooleanWithValue(NumberP(val) || SymbolP(val) || StringP(val) || BooleanP(val) || CharacterP(val)), nil
 }
 
 func IsPairImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
@@ -59,13 +62,17 @@ func IsStringImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 }
 
 func IsIntegerImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
-	return BooleanWithValue(IntegerP(Car(args))), nil
+	return BooleanWithValue(ExactIntegerP(Car(args))), nil
 }
 
 func IsNumberImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	return BooleanWithValue(NumberP(Car(args))), nil
 }
 
+func IsRationalImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	return BooleanWithValue(ExactP(Car(args))), nil
+}
+
 func IsFloatImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	return BooleanWithValue(FloatP(Car(args))), nil
 }
@@ -93,3 +100,11 @@ func IsPortImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 func IsBooleanImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
 	return BooleanWithValue(BooleanP(Car(args))), nil
 }
+
+func IsRecordImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	return BooleanWithValue(RecordP(Car(args))), nil
+}
+
+func IsRecordTypeImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	return BooleanWithValue(RecordDescriptorP(Car(args))), nil
+}
@@ -0,0 +1,261 @@
+// Copyright 2014 SteelSeries ApS.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// This package implements a basic LISP interpretor for embedding in a go program for scripting.
+// This file contains the vector primitive functions.
+
+package golisp
+
+import (
+	"fmt"
+)
+
+func RegisterVectorPrimitives() {
+	MakePrimitiveFunction("vector", "*", VectorImpl)
+	MakePrimitiveFunction("make-vector", "1|2", MakeVectorImpl)
+	MakePrimitiveFunction("vector?", "1", IsVectorImpl)
+	MakePrimitiveFunction("vector-length", "1", VectorLengthImpl)
+	MakePrimitiveFunction("vector-ref", "2", VectorRefImpl)
+	MakePrimitiveFunction("vector-set!", "3", VectorSetImpl)
+	MakePrimitiveFunction("vector-map", ">=2", VectorMapImpl)
+	MakePrimitiveFunction("vector-for-each", ">=2", VectorForEachImpl)
+	MakePrimitiveFunction("vector-fill!", "2|3|4", VectorFillImpl)
+	MakePrimitiveFunction("subvector", "3", SubvectorImpl)
+	MakePrimitiveFunction("vector-grow", "2", VectorGrowImpl)
+	MakePrimitiveFunction("vector->list", "1|2|3", VectorToListImpl)
+	MakePrimitiveFunction("list->vector", "1", ListToVectorImpl)
+}
+
+func vectorArg(name string, v *Data, env *SymbolTableFrame) (elements []*Data, err error) {
+	if !VectorP(v) {
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("%s requires a vector as it's first argument, but was given %s.", name, String(v)), env)
+		return
+	}
+	return VectorValue(v), nil
+}
+
+// An index argument, which must be in [0, limit].
+func vectorIndexArg(name string, index *Data, limit int, env *SymbolTableFrame) (i int, err error) {
+	if !IntegerP(index) {
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("%s requires an integer index, but was given %s.", name, String(index)), env)
+		return
+	}
+	if IntegerValue(index) < 0 || IntegerValue(index) > int64(limit) {
+		err = ProcessTypedError(BadRangeArgumentCondition, fmt.Sprintf("%s index %d is out of range.", name, IntegerValue(index)), env)
+		return
+	}
+	return int(IntegerValue(index)), nil
+}
+
+// The start and end arguments found from position first of args on, each
+// optional, checked against the vector's length.
+func vectorRangeArgs(name string, args *Data, first int, length int, env *SymbolTableFrame) (start int, end int, err error) {
+	start, end = 0, length
+	if Length(args) > first {
+		start, err = vectorIndexArg(name, Nth(args, first+1), length, env)
+		if err != nil {
+			return
+		}
+	}
+	if Length(args) > first+1 {
+		end, err = vectorIndexArg(name, Nth(args, first+2), length, env)
+		if err != nil {
+			return
+		}
+	}
+	if start > end {
+		err = ProcessTypedError(BadRangeArgumentCondition, fmt.Sprintf("%s requires start (%d) to be no greater than end (%d).", name, start, end), env)
+	}
+	return
+}
+
+func VectorImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	return VectorWithValue(ToArray(args)), nil
+}
+
+func MakeVectorImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	k := Car(args)
+	if !IntegerP(k) || IntegerValue(k) < 0 {
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("make-vector requires a non-negative integer size, but was given %s.", String(k)), env)
+		return
+	}
+
+	fill := Cadr(args)
+	elements := make([]*Data, IntegerValue(k))
+	for i := range elements {
+		elements[i] = fill
+	}
+	return VectorWithValue(elements), nil
+}
+
+func IsVectorImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	return BooleanWithValue(VectorP(Car(args))), nil
+}
+
+func VectorLengthImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	elements, err := vectorArg("vector-length", Car(args), env)
+	if err != nil {
+		return
+	}
+	return IntegerWithValue(int64(len(elements))), nil
+}
+
+func VectorRefImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	elements, err := vectorArg("vector-ref", Car(args), env)
+	if err != nil {
+		return
+	}
+
+	i, err := vectorIndexArg("vector-ref", Cadr(args), len(elements)-1, env)
+	if err != nil {
+		return
+	}
+	return elements[i], nil
+}
+
+func VectorSetImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	elements, err := vectorArg("vector-set!", Car(args), env)
+	if err != nil {
+		return
+	}
+
+	i, err := vectorIndexArg("vector-set!", Cadr(args), len(elements)-1, env)
+	if err != nil {
+		return
+	}
+	elements[i] = Caddr(args)
+	return Caddr(args), nil
+}
+
+// Applies f to the elements of the vectors at each index, stopping at the end
+// of the shortest vector.
+func mapOverVectors(name string, args *Data, env *SymbolTableFrame, collect bool) (results []*Data, err error) {
+	f := Car(args)
+	if !FunctionOrPrimitiveP(f) {
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("%s needs a function as its first argument, but got %s.", name, String(f)), env)
+		return
+	}
+
+	vectors := make([][]*Data, 0, Length(args)-1)
+	count := -1
+	for a := Cdr(args); NotNilP(a); a = Cdr(a) {
+		if !VectorP(Car(a)) {
+			err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("%s needs vectors as its other arguments, but got %s.", name, String(Car(a))), env)
+			return
+		}
+		elements := VectorValue(Car(a))
+		vectors = append(vectors, elements)
+		if count == -1 || len(elements) < count {
+			count = len(elements)
+		}
+	}
+
+	if collect {
+		results = make([]*Data, 0, count)
+	}
+	var v *Data
+	for i := 0; i < count; i++ {
+		fArgs := make([]*Data, 0, len(vectors))
+		for _, elements := range vectors {
+			fArgs = append(fArgs, elements[i])
+		}
+		v, err = ApplyWithoutEval(f, ArrayToList(fArgs), env)
+		if err != nil {
+			return
+		}
+		if collect {
+			results = append(results, v)
+		}
+	}
+	return
+}
+
+func VectorMapImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	results, err := mapOverVectors("vector-map", args, env, true)
+	if err != nil {
+		return
+	}
+	return VectorWithValue(results), nil
+}
+
+func VectorForEachImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	_, err = mapOverVectors("vector-for-each", args, env, false)
+	return
+}
+
+func VectorFillImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	elements, err := vectorArg("vector-fill!", Car(args), env)
+	if err != nil {
+		return
+	}
+
+	start, end, err := vectorRangeArgs("vector-fill!", args, 2, len(elements), env)
+	if err != nil {
+		return
+	}
+
+	fill := Cadr(args)
+	for i := start; i < end; i++ {
+		elements[i] = fill
+	}
+	return Car(args), nil
+}
+
+func SubvectorImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	elements, err := vectorArg("subvector", Car(args), env)
+	if err != nil {
+		return
+	}
+
+	start, end, err := vectorRangeArgs("subvector", args, 1, len(elements), env)
+	if err != nil {
+		return
+	}
+
+	return VectorWithValue(append([]*Data{}, elements[start:end]...)), nil
+}
+
+func VectorGrowImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	elements, err := vectorArg("vector-grow", Car(args), env)
+	if err != nil {
+		return
+	}
+
+	k := Cadr(args)
+	if !IntegerP(k) {
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("vector-grow requires an integer size, but was given %s.", String(k)), env)
+		return
+	}
+	if IntegerValue(k) < int64(len(elements)) {
+		err = ProcessTypedError(BadRangeArgumentCondition, fmt.Sprintf("vector-grow requires a size of at least %d, but was given %d.", len(elements), IntegerValue(k)), env)
+		return
+	}
+
+	grown := make([]*Data, IntegerValue(k))
+	copy(grown, elements)
+	return VectorWithValue(grown), nil
+}
+
+func VectorToListImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	elements, err := vectorArg("vector->list", Car(args), env)
+	if err != nil {
+		return
+	}
+
+	start, end, err := vectorRangeArgs("vector->list", args, 1, len(elements), env)
+	if err != nil {
+		return
+	}
+
+	return ArrayToList(elements[start:end]), nil
+}
+
+func ListToVectorImpl(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	l := Car(args)
+	if !ListP(l) {
+		err = ProcessTypedError(WrongTypeArgumentCondition, fmt.Sprintf("list->vector requires a list, but was given %s.", String(l)), env)
+		return
+	}
+	return VectorWithValue(ToArray(l)), nil
+}
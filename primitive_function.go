@@ -145,14 +145,39 @@ func (self *PrimitiveFunction) checkArgumentCount(argCount int) bool {
 	return false
 }
 
-func (self *PrimitiveFunction) Apply(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+func (self *PrimitiveFunction) checkApplication(argCount int, env *SymbolTableFrame) (err error) {
 	if self.IsRestricted && env.IsRestricted {
-		err = fmt.Errorf("The %s primitive is restricted from execution in this environment\n", self.Name)
-		return
+		return fmt.Errorf("The %s primitive is restricted from execution in this environment\n", self.Name)
+	}
+
+	if !self.checkArgumentCount(argCount) {
+		return ProcessTypedError(WrongNumberOfArgumentsCondition, fmt.Sprintf("Wrong number of args to %s, expected %s but got %d.", self.Name, self.argsString(), argCount), env)
+	}
+	return nil
+}
+
+func (self *PrimitiveFunction) call(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	localGuid := atomic.AddInt64(&ProfileGUID, 1) - 1
+
+	fType := "prim"
+	if self.Special {
+		fType = "form"
 	}
 
-	if !self.checkArgumentCount(Length(args)) {
-		err = ProcessError(fmt.Sprintf("Wrong number of args to %s, expected %s but got %d.", self.Name, self.argsString(), Length(args)), env)
+	ProfileEnter(fType, self.Name, localGuid)
+
+	result, err = (self.Body)(args, env)
+
+	ProfileExit(fType, self.Name, localGuid)
+
+	return
+}
+
+// Applies the primitive, leaving any tail call made by a special form for
+// the caller to evaluate.
+func (self *PrimitiveFunction) internalApply(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	err = self.checkApplication(Length(args), env)
+	if err != nil {
 		return
 	}
 
@@ -171,19 +196,15 @@ func (self *PrimitiveFunction) Apply(args *Data, env *SymbolTableFrame) (result
 		argArray = append(argArray, argValue)
 	}
 
-	localGuid := atomic.AddInt64(&ProfileGUID, 1) - 1
+	return self.call(ArrayToList(argArray), env)
+}
 
-	fType := "prim"
-	if self.Special {
-		fType = "form"
+func (self *PrimitiveFunction) Apply(args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	result, err = self.internalApply(args, env)
+	if err == nil && TailCallP(result) {
+		tailCall := TailCallValue(result)
+		result, err = Eval(tailCall.Expr, tailCall.Env)
 	}
-
-	ProfileEnter(fType, self.Name, localGuid)
-
-	result, err = (self.Body)(ArrayToList(argArray), env)
-
-	ProfileExit(fType, self.Name, localGuid)
-
 	return
 }
 
@@ -37,6 +37,13 @@ func (s *PrintingSuite) TestString(c *C) {
 	c.Assert(String(sexpr), Equals, `"hello, world"`)
 }
 
+func (s *PrintingSuite) TestCharacter(c *C) {
+	c.Assert(String(CharacterWithValue('a')), Equals, `#\a`)
+	c.Assert(String(CharacterWithValue(' ')), Equals, `#\space`)
+	c.Assert(String(CharacterWithValue(1)), Equals, `#\x1`)
+	c.Assert(PrintString(CharacterWithValue('a')), Equals, "a")
+}
+
 func (s *PrintingSuite) TestSymbol(c *C) {
 	sexpr := SymbolWithName("function")
 	c.Assert(String(sexpr), Equals, "function")
@@ -0,0 +1,80 @@
+// Copyright 2014 SteelSeries ApS.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// This package implements a basic LISP interpretor for embedding in a go program for scripting.
+// This file implements record types made by define-record-type.
+
+package golisp
+
+import (
+	"fmt"
+	"strings"
+)
+
+// A record type: its name and the names of its fields, in order. Each
+// define-record-type makes a new descriptor, so records of different types
+// are never equal even if the types have the same name.
+type RecordDescriptor struct {
+	Name   string
+	Fields []string
+}
+
+// A record holds the values of its type's fields in the same order as the
+// type's field names.
+type Record struct {
+	Descriptor *RecordDescriptor
+	Fields     []*Data
+}
+
+func MakeRecordDescriptor(name string, fields []string) *RecordDescriptor {
+	// the conventional <name> is printed as just name
+	if len(name) > 2 && strings.HasPrefix(name, "<") && strings.HasSuffix(name, ">") {
+		name = name[1 : len(name)-1]
+	}
+	return &RecordDescriptor{Name: name, Fields: fields}
+}
+
+// The index of a field, or -1 if the type doesn't have it.
+func (self *RecordDescriptor) FieldIndex(name string) int {
+	for i, field := range self.Fields {
+		if field == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// A new record with every field empty.
+func (self *RecordDescriptor) MakeRecord() *Record {
+	return &Record{Descriptor: self, Fields: make([]*Data, len(self.Fields))}
+}
+
+func (self *Record) Copy() *Record {
+	copied := &Record{Descriptor: self.Descriptor, Fields: make([]*Data, len(self.Fields))}
+	for i, field := range self.Fields {
+		copied.Fields[i] = Copy(field)
+	}
+	return copied
+}
+
+func (self *Record) IsEqual(other *Record) bool {
+	if self.Descriptor != other.Descriptor {
+		return false
+	}
+	for i := range self.Fields {
+		if !IsEqual(self.Fields[i], other.Fields[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func (self *Record) String() string {
+	contents := make([]string, 0, len(self.Fields)+1)
+	contents = append(contents, self.Descriptor.Name)
+	for i, field := range self.Fields {
+		contents = append(contents, fmt.Sprintf("%s: %s", self.Descriptor.Fields[i], String(field)))
+	}
+	return fmt.Sprintf("<%s>", strings.Join(contents, " "))
+}
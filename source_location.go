@@ -0,0 +1,106 @@
+// Copyright 2014 SteelSeries ApS.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// This package implements a basic LISP interpretor for embedding in a go program for scripting.
+// This file implements source locations for parsed code and errors.
+
+package golisp
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Where a list was read from. File is empty for code parsed from a string.
+type SourceLocation struct {
+	File   string
+	Line   int
+	Column int
+}
+
+func (self *SourceLocation) String() string {
+	if self.File == "" {
+		return fmt.Sprintf("%d:%d", self.Line, self.Column)
+	}
+	return fmt.Sprintf("%s:%d:%d", self.File, self.Line, self.Column)
+}
+
+// Only lists built by the parser have a location.
+func SourceLocationOf(d *Data) *SourceLocation {
+	if d == nil || TypeOf(d) != ConsCellType {
+		return nil
+	}
+	if info := (*ConsCell)(d.Value).loadInfo(); info != nil {
+		return info.Location
+	}
+	return nil
+}
+
+func SetSourceLocation(d *Data, loc *SourceLocation) {
+	if d != nil && TypeOf(d) == ConsCellType {
+		(*ConsCell)(d.Value).storeInfo(&consInfo{Location: loc})
+	}
+}
+
+// An error reported against a location in a file: a parse error where the
+// parser stopped, or an error from evaluating a file, located at the innermost
+// form that was being evaluated. It reads as the location and the root error,
+// followed by the "Evaling ..." chain.
+type LocatedError struct {
+	Location *SourceLocation
+	Err      error
+}
+
+func locatedError(loc *SourceLocation, err error) *LocatedError {
+	return &LocatedError{Location: loc, Err: err}
+}
+
+func (self *LocatedError) Error() string {
+	return fmt.Sprintf("%s: %s%s", self.Location, RootError(self.Err), self.chain())
+}
+
+// The "Evaling ..." chain without the root error. A located error within the
+// chain contributes only its own chain, since the location and root error are
+// already reported.
+func (self *LocatedError) chain() string {
+	text := self.Err.Error()
+	var inner *LocatedError
+	if errors.As(self.Err, &inner) {
+		text = strings.Replace(text, inner.Error(), inner.chain(), 1)
+	}
+	return strings.TrimSuffix(text, RootError(self.Err).Error())
+}
+
+func (self *LocatedError) Unwrap() error {
+	return self.Err
+}
+
+// The location of the most deeply nested located expression being evaluated
+// when err occurred, or where parsing stopped, or nil if none of them came
+// from the parser.
+func InnermostSourceLocation(err error) (loc *SourceLocation) {
+	for ; err != nil; err = errors.Unwrap(err) {
+		switch located := err.(type) {
+		case *EvalError:
+			if located.Location != nil {
+				loc = located.Location
+			}
+		case *LocatedError:
+			loc = located.Location
+		}
+	}
+	return
+}
+
+// The error at the bottom of the chain, without the "Evaling ..." context.
+func RootError(err error) error {
+	for {
+		next := errors.Unwrap(err)
+		if next == nil {
+			return err
+		}
+		err = next
+	}
+}
@@ -9,7 +9,6 @@ package golisp
 
 import (
 	"container/list"
-	"errors"
 	"fmt"
 	"sync"
 	"sync/atomic"
@@ -29,6 +28,14 @@ type SymbolTableFrame struct {
 	Mutex        sync.RWMutex
 	CurrentCode  *list.List
 	IsRestricted bool
+
+	// Frames made by compiled code keep the bindings the compiler knows about
+	// in slots, so they can be reached by index. Bindings holds the rest.
+	Locals     []*Binding
+	localNames []*Data
+	extended   bool
+
+	dynamic *dynamicExtent
 }
 
 type symbolsTable struct {
@@ -44,6 +51,10 @@ type environmentsTable struct {
 var Global *SymbolTableFrame
 var TopLevelEnvironments environmentsTable = environmentsTable{make(map[string]*SymbolTableFrame, 5), sync.RWMutex{}}
 
+// Counts removals, so anything holding on to a binding can tell that it may
+// have gone.
+var deletedBindings int64
+
 var internedSymbols symbolsTable = symbolsTable{make(map[string]*Data, 256), sync.RWMutex{}}
 
 func Intern(name string) (sym *Data) {
@@ -77,9 +88,7 @@ func (self *SymbolTableFrame) CurrentCodeString() string {
 
 func (self *SymbolTableFrame) InternalDump(frameNumber int) {
 	fmt.Printf("Frame %d: %s\n", frameNumber, self.CurrentCodeString())
-	self.Mutex.RLock()
-	defer self.Mutex.RUnlock()
-	for _, b := range self.Bindings {
+	for _, b := range self.AllBindings() {
 		if b.Val == nil || TypeOf(b.Val) != PrimitiveType {
 			b.Dump()
 		}
@@ -98,9 +107,7 @@ func (self *SymbolTableFrame) Dump() {
 func (self *SymbolTableFrame) DumpSingleFrame(frameNumber int) {
 	if frameNumber == 0 {
 		fmt.Printf("%s\n", self.CurrentCodeString())
-		self.Mutex.RLock()
-		defer self.Mutex.RUnlock()
-		for _, b := range self.Bindings {
+		for _, b := range self.AllBindings() {
 			if b.Val == nil || TypeOf(b.Val) != PrimitiveType {
 				b.Dump()
 			}
@@ -158,29 +165,84 @@ func NewSymbolTableFrameBelowWithFrame(p *SymbolTableFrame, f *FrameMap, name st
 	return env
 }
 
+func newSymbolTableFrameWithLocals(p *SymbolTableFrame, f *FrameMap, name string, localNames []*Data) *SymbolTableFrame {
+	if f == nil {
+		f = p.Frame
+	}
+	env := &SymbolTableFrame{Name: name, Parent: p, Frame: f, CurrentCode: list.New(), IsRestricted: p.IsRestricted, Locals: make([]*Binding, len(localNames)), localNames: localNames}
+	if p == Global {
+		TopLevelEnvironments.Mutex.Lock()
+		TopLevelEnvironments.Environments[name] = env
+		TopLevelEnvironments.Mutex.Unlock()
+	}
+	return env
+}
+
 func (self *SymbolTableFrame) HasFrame() bool {
 	return self.Frame != nil
 }
 
+func (self *SymbolTableFrame) localIndex(name string) int {
+	for i, sym := range self.localNames {
+		if StringValue(sym) == name {
+			return i
+		}
+	}
+	return -1
+}
+
 func (self *SymbolTableFrame) BindingNamed(name string) (b *Binding, present bool) {
 	self.Mutex.RLock()
-	b, present = self.Bindings[name]
+	if i := self.localIndex(name); i >= 0 {
+		b = self.Locals[i]
+		present = b != nil
+	} else {
+		b, present = self.Bindings[name]
+	}
 	self.Mutex.RUnlock()
 	return
 }
 
 func (self *SymbolTableFrame) SetBindingAt(name string, b *Binding) {
 	self.Mutex.Lock()
-	self.Bindings[name] = b
+	if i := self.localIndex(name); i >= 0 {
+		self.Locals[i] = b
+	} else {
+		if self.Bindings == nil {
+			self.Bindings = make(map[string]*Binding)
+		}
+		self.Bindings[name] = b
+		self.extended = true
+	}
 	self.Mutex.Unlock()
 }
 
 func (self *SymbolTableFrame) DeleteBinding(name string) {
+	atomic.AddInt64(&deletedBindings, 1)
 	self.Mutex.Lock()
-	delete(self.Bindings, name)
+	if i := self.localIndex(name); i >= 0 {
+		self.Locals[i] = nil
+	} else {
+		delete(self.Bindings, name)
+	}
 	self.Mutex.Unlock()
 }
 
+func (self *SymbolTableFrame) AllBindings() []*Binding {
+	self.Mutex.RLock()
+	defer self.Mutex.RUnlock()
+	bindings := make([]*Binding, 0, len(self.Locals)+len(self.Bindings))
+	for _, b := range self.Locals {
+		if b != nil {
+			bindings = append(bindings, b)
+		}
+	}
+	for _, b := range self.Bindings {
+		bindings = append(bindings, b)
+	}
+	return bindings
+}
+
 func (self *SymbolTableFrame) findSymbol(name string) (symbol *Data, found bool) {
 	binding, found := self.BindingNamed(name)
 	if found {
@@ -257,7 +319,7 @@ func (self *SymbolTableFrame) SetTo(symbol *Data, value *Data) (result *Data, er
 		}
 	}
 
-	return nil, errors.New(fmt.Sprintf("%s is undefined", StringValue(symbol)))
+	return nil, MakeCondition(UnboundVariableCondition, fmt.Sprintf("%s is undefined", StringValue(symbol)), nil)
 }
 
 func (self *SymbolTableFrame) findBindingInLocalFrameFor(symbol *Data) (b *Binding, found bool) {
@@ -265,6 +327,9 @@ func (self *SymbolTableFrame) findBindingInLocalFrameFor(symbol *Data) (b *Bindi
 }
 
 func (self *SymbolTableFrame) BindLocallyTo(symbol *Data, value *Data) (*Data, error) {
+	if self.dynamic != nil && self.Parent != nil {
+		return self.Parent.BindLocallyTo(symbol, value)
+	}
 	binding, found := self.findBindingInLocalFrameFor(symbol)
 	if found {
 		if binding.Protected {
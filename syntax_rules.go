@@ -0,0 +1,487 @@
+// Copyright 2014 SteelSeries ApS.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// This package implements a basic LISP interpretor for embedding in a go program for scripting.
+// This file implements syntax-rules macro transformers.
+
+package golisp
+
+import (
+	"fmt"
+	"sync"
+)
+
+type syntaxRule struct {
+	Pattern  *Data
+	Template *Data
+}
+
+type SyntaxRules struct {
+	Ellipsis string
+	Literals []string
+	Rules    []syntaxRule
+	Env      *SymbolTableFrame
+
+	// Global names that refer to definition environment bindings which
+	// are shadowed where the macro is used.
+	referencesMutex sync.Mutex
+	references      map[*Binding]*Data
+}
+
+// A pattern variable is bound to a form, or, under an ellipsis, to one
+// binding per repetition.
+type patternBinding struct {
+	Value    *Data
+	Sequence []*patternBinding
+}
+
+type patternBindings map[string]*patternBinding
+
+func MakeSyntaxRules(ellipsis string, literals *Data, rules *Data, env *SymbolTableFrame) (transformer *SyntaxRules, err error) {
+	transformer = &SyntaxRules{Ellipsis: ellipsis, Env: env}
+	for l := literals; NotNilP(l); l = Cdr(l) {
+		if !SymbolP(Car(l)) {
+			err = ProcessTypedError(SyntaxErrorCondition, fmt.Sprintf("syntax-rules literals must be symbols, but was given %s.", String(Car(l))), env)
+			return
+		}
+		transformer.Literals = append(transformer.Literals, StringValue(Car(l)))
+	}
+	for r := rules; NotNilP(r); r = Cdr(r) {
+		rule := Car(r)
+		if !PairP(rule) || Length(rule) != 2 || !PairP(Car(rule)) {
+			err = ProcessTypedError(SyntaxErrorCondition, fmt.Sprintf("syntax-rules requires rules of the form (pattern template), but was given %s.", String(rule)), env)
+			return
+		}
+		transformer.Rules = append(transformer.Rules, syntaxRule{Pattern: Car(rule), Template: Cadr(rule)})
+	}
+	return
+}
+
+func (self *SyntaxRules) isEllipsis(d *Data) bool {
+	return SymbolP(d) && StringValue(d) == self.Ellipsis
+}
+
+func (self *SyntaxRules) isLiteral(d *Data) bool {
+	name := StringValue(d)
+	for _, literal := range self.Literals {
+		if literal == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Splits a possibly improper list into its elements and its tail.
+func listParts(d *Data) (elements []*Data, tail *Data) {
+	c := d
+	for ; PairP(c) && NotNilP(c); c = Cdr(c) {
+		elements = append(elements, Car(c))
+	}
+	if NotNilP(c) {
+		tail = c
+	}
+	return
+}
+
+// Expands a use of the macro, given the forms following the keyword.
+func (self *SyntaxRules) Expand(name string, args *Data, env *SymbolTableFrame) (result *Data, err error) {
+	for _, rule := range self.Rules {
+		bindings := make(patternBindings)
+		if self.match(Cdr(rule.Pattern), args, bindings) {
+			expansion := &syntaxExpansion{rules: self, aliases: make(map[string]*Data), introduced: make(map[*Data]bool), built: make(map[*Data]bool)}
+			result, err = expansion.instantiate(rule.Template, bindings, false, env)
+			if err != nil {
+				return
+			}
+			return expansion.rename(result, env), nil
+		}
+	}
+	err = ProcessTypedError(SyntaxErrorCondition, fmt.Sprintf("Ill-formed special form: %s", String(Cons(Intern(name), args))), env)
+	return
+}
+
+func (self *SyntaxRules) match(pattern *Data, form *Data, bindings patternBindings) bool {
+	if SymbolP(pattern) {
+		if self.isLiteral(pattern) {
+			return SymbolP(form) && StringValue(form) == StringValue(pattern)
+		}
+		if StringValue(pattern) != "_" {
+			bindings[StringValue(pattern)] = &patternBinding{Value: form}
+		}
+		return true
+	}
+
+	if VectorP(pattern) {
+		return VectorP(form) && self.match(ArrayToList(VectorValue(pattern)), ArrayToList(VectorValue(form)), bindings)
+	}
+
+	if !PairP(pattern) {
+		return IsEqual(pattern, form)
+	}
+
+	if NilP(pattern) {
+		return NilP(form)
+	}
+
+	if !PairP(form) {
+		return false
+	}
+
+	patterns, patternTail := listParts(pattern)
+	forms, formTail := listParts(form)
+
+	for i := 0; i < len(patterns)-1; i++ {
+		if !self.isEllipsis(patterns[i+1]) {
+			continue
+		}
+		before, repeated, after := patterns[:i], patterns[i], patterns[i+2:]
+		repeats := len(forms) - len(before) - len(after)
+		if repeats < 0 || (patternTail == nil && formTail != nil) {
+			return false
+		}
+		for j, p := range before {
+			if !self.match(p, forms[j], bindings) {
+				return false
+			}
+		}
+		sequences := make(map[string][]*patternBinding)
+		for _, v := range self.patternVariables(repeated, nil) {
+			sequences[v] = make([]*patternBinding, 0, repeats)
+		}
+		for j := len(before); j < len(before)+repeats; j++ {
+			repeatBindings := make(patternBindings)
+			if !self.match(repeated, forms[j], repeatBindings) {
+				return false
+			}
+			for v := range sequences {
+				sequences[v] = append(sequences[v], repeatBindings[v])
+			}
+		}
+		for v, sequence := range sequences {
+			bindings[v] = &patternBinding{Sequence: sequence}
+		}
+		for j, p := range after {
+			if !self.match(p, forms[len(before)+repeats+j], bindings) {
+				return false
+			}
+		}
+		if patternTail != nil {
+			return self.match(patternTail, formTail, bindings)
+		}
+		return true
+	}
+
+	if patternTail == nil {
+		if len(forms) != len(patterns) || formTail != nil {
+			return false
+		}
+	} else if len(forms) < len(patterns) {
+		return false
+	}
+	for j, p := range patterns {
+		if !self.match(p, forms[j], bindings) {
+			return false
+		}
+	}
+	if patternTail != nil {
+		return self.match(patternTail, ArrayToListWithTail(forms[len(patterns):], formTail), bindings)
+	}
+	return true
+}
+
+func (self *SyntaxRules) patternVariables(pattern *Data, vars []string) []string {
+	if SymbolP(pattern) {
+		if !self.isLiteral(pattern) && !self.isEllipsis(pattern) && StringValue(pattern) != "_" {
+			vars = append(vars, StringValue(pattern))
+		}
+		return vars
+	}
+	if VectorP(pattern) {
+		return self.patternVariables(ArrayToList(VectorValue(pattern)), vars)
+	}
+	if PairP(pattern) && NotNilP(pattern) {
+		elements, tail := listParts(pattern)
+		for _, element := range elements {
+			vars = self.patternVariables(element, vars)
+		}
+		if tail != nil {
+			vars = self.patternVariables(tail, vars)
+		}
+	}
+	return vars
+}
+
+// The state of a single expansion. Symbols the template introduces are
+// represented by one alias per name until the expansion is complete, so that
+// they can be told apart from identical symbols that came from the macro use.
+type syntaxExpansion struct {
+	rules      *SyntaxRules
+	aliases    map[string]*Data
+	introduced map[*Data]bool
+	built      map[*Data]bool
+}
+
+func (self *syntaxExpansion) alias(symbol *Data) *Data {
+	name := StringValue(symbol)
+	a, found := self.aliases[name]
+	if !found {
+		a = SymbolWithName(name)
+		self.aliases[name] = a
+		self.introduced[a] = true
+	}
+	return a
+}
+
+func (self *syntaxExpansion) cons(car *Data, cdr *Data) *Data {
+	if car == nil {
+		car = EmptyCons()
+	}
+	cell := Cons(car, cdr)
+	self.built[cell] = true
+	return cell
+}
+
+func (self *syntaxExpansion) instantiate(template *Data, bindings patternBindings, escaped bool, env *SymbolTableFrame) (result *Data, err error) {
+	if SymbolP(template) {
+		binding, found := bindings[StringValue(template)]
+		if !found {
+			return self.alias(template), nil
+		}
+		if binding.Sequence != nil {
+			err = ProcessTypedError(SyntaxErrorCondition, fmt.Sprintf("syntax-rules pattern variable %s is used without an ellipsis.", StringValue(template)), env)
+			return
+		}
+		return binding.Value, nil
+	}
+
+	if VectorP(template) {
+		var elements *Data
+		elements, err = self.instantiate(ArrayToList(VectorValue(template)), bindings, escaped, env)
+		if err != nil {
+			return
+		}
+		result = VectorWithValue(ToArray(elements))
+		self.built[result] = true
+		return
+	}
+
+	if !PairP(template) || NilP(template) {
+		return template, nil
+	}
+
+	elements, tail := listParts(template)
+
+	if !escaped && len(elements) == 2 && tail == nil && self.rules.isEllipsis(elements[0]) {
+		return self.instantiate(elements[1], bindings, true, env)
+	}
+
+	expanded := make([]*Data, 0, len(elements))
+	for i := 0; i < len(elements); i++ {
+		depth := 0
+		for !escaped && i+depth+1 < len(elements) && self.rules.isEllipsis(elements[i+depth+1]) {
+			depth++
+		}
+		var items []*Data
+		items, err = self.instantiateRepeated(elements[i], bindings, depth, escaped, env)
+		if err != nil {
+			return
+		}
+		expanded = append(expanded, items...)
+		i += depth
+	}
+
+	if tail != nil {
+		result, err = self.instantiate(tail, bindings, escaped, env)
+		if err != nil {
+			return
+		}
+	}
+	for i := len(expanded) - 1; i >= 0; i-- {
+		result = self.cons(expanded[i], result)
+	}
+	return
+}
+
+func (self *syntaxExpansion) instantiateRepeated(template *Data, bindings patternBindings, depth int, escaped bool, env *SymbolTableFrame) (items []*Data, err error) {
+	if depth == 0 {
+		var item *Data
+		item, err = self.instantiate(template, bindings, escaped, env)
+		return []*Data{item}, err
+	}
+
+	repeats := -1
+	var controlling []string
+	for _, v := range self.rules.patternVariables(template, nil) {
+		binding, found := bindings[v]
+		if !found || binding.Sequence == nil {
+			continue
+		}
+		if repeats >= 0 && len(binding.Sequence) != repeats {
+			err = ProcessTypedError(SyntaxErrorCondition, fmt.Sprintf("syntax-rules pattern variables under an ellipsis matched different numbers of forms in %s.", String(template)), env)
+			return
+		}
+		repeats = len(binding.Sequence)
+		controlling = append(controlling, v)
+	}
+	if controlling == nil {
+		err = ProcessTypedError(SyntaxErrorCondition, fmt.Sprintf("syntax-rules template %s is followed by an ellipsis but contains no pattern variables that can repeat.", String(template)), env)
+		return
+	}
+
+	for i := 0; i < repeats; i++ {
+		repeatBindings := make(patternBindings, len(bindings))
+		for k, v := range bindings {
+			repeatBindings[k] = v
+		}
+		for _, v := range controlling {
+			repeatBindings[v] = bindings[v].Sequence[i]
+		}
+		var repeated []*Data
+		repeated, err = self.instantiateRepeated(template, repeatBindings, depth-1, escaped, env)
+		if err != nil {
+			return
+		}
+		items = append(items, repeated...)
+	}
+	return
+}
+
+// Introduced symbols that the expansion binds are replaced by fresh symbols
+// so they can't capture, or be captured by, symbols from the macro use. All
+// other introduced symbols are free references to the macro's definition
+// environment; see freeReference.
+func (self *syntaxExpansion) rename(form *Data, env *SymbolTableFrame) *Data {
+	bound := make(map[*Data]bool)
+	self.collectBinders(form, bound)
+
+	renamed := make(map[*Data]*Data)
+	for a := range bound {
+		renamed[a] = gensymNamed(StringValue(a))
+	}
+	return self.replaceAliases(form, renamed, false, env)
+}
+
+// A symbol referring to what the introduced symbol means where the macro was
+// defined. That is the ordinary symbol unless the use environment binds the
+// name differently, in which case it is a fresh global name sharing the
+// definition environment's binding. Names unbound where the macro was defined
+// can't be told apart and keep the ordinary symbol.
+func (self *SyntaxRules) freeReference(symbol *Data, env *SymbolTableFrame) *Data {
+	name := StringValue(symbol)
+	if self.Env == nil {
+		return Intern(name)
+	}
+	definition, found := self.Env.FindBindingFor(symbol)
+	if !found {
+		return Intern(name)
+	}
+	if use, found := env.FindBindingFor(symbol); found && use == definition {
+		return Intern(name)
+	}
+
+	self.referencesMutex.Lock()
+	defer self.referencesMutex.Unlock()
+	reference, found := self.references[definition]
+	if !found {
+		if self.references == nil {
+			self.references = make(map[*Binding]*Data)
+		}
+		reference = gensymNamed(name)
+		Global.SetBindingAt(StringValue(reference), definition)
+		self.references[definition] = reference
+	}
+	return reference
+}
+
+func (self *syntaxExpansion) addBinder(d *Data, bound map[*Data]bool) {
+	if self.introduced[d] {
+		bound[d] = true
+	}
+}
+
+func (self *syntaxExpansion) addParameters(params *Data, bound map[*Data]bool) {
+	elements, tail := listParts(params)
+	for _, p := range elements {
+		self.addBinder(p, bound)
+	}
+	self.addBinder(tail, bound)
+	if SymbolP(params) {
+		self.addBinder(params, bound)
+	}
+}
+
+func (self *syntaxExpansion) collectBinders(form *Data, bound map[*Data]bool) {
+	if !self.built[form] || VectorP(form) {
+		return
+	}
+
+	if SymbolP(Car(form)) {
+		switch StringValue(Car(form)) {
+		case "quote":
+			return
+		case "lambda", "named-lambda":
+			self.addParameters(Cadr(form), bound)
+		case "define":
+			self.addParameters(Cadr(form), bound)
+		case "let", "let*", "letrec", "letrec*":
+			bindingForms := Cadr(form)
+			if SymbolP(bindingForms) {
+				self.addBinder(bindingForms, bound)
+				bindingForms = Caddr(form)
+			}
+			for b := bindingForms; PairP(b) && NotNilP(b); b = Cdr(b) {
+				if PairP(Car(b)) {
+					self.addBinder(Car(Car(b)), bound)
+				} else {
+					self.addBinder(Car(b), bound)
+				}
+			}
+		case "do":
+			for b := Cadr(form); PairP(b) && NotNilP(b); b = Cdr(b) {
+				self.addBinder(Car(Car(b)), bound)
+			}
+		}
+	}
+
+	for c := form; self.built[c]; c = Cdr(c) {
+		self.collectBinders(Car(c), bound)
+	}
+}
+
+func (self *syntaxExpansion) replaceAliases(form *Data, renamed map[*Data]*Data, quoted bool, env *SymbolTableFrame) *Data {
+	if self.introduced[form] {
+		if quoted {
+			return Intern(StringValue(form))
+		}
+		if r, found := renamed[form]; found {
+			return r
+		}
+		return self.rules.freeReference(form, env)
+	}
+
+	if !self.built[form] {
+		return form
+	}
+
+	// Vectors are constants, so what they contain is quoted.
+	if VectorP(form) {
+		elements := VectorValue(form)
+		for i, element := range elements {
+			elements[i] = self.replaceAliases(element, renamed, true, env)
+		}
+		return form
+	}
+
+	if SymbolP(Car(form)) && StringValue(Car(form)) == "quote" {
+		quoted = true
+	}
+	for c := form; self.built[c]; c = Cdr(c) {
+		cell := (*ConsCell)(c.Value)
+		cell.Car = self.replaceAliases(cell.Car, renamed, quoted, env)
+		if !self.built[cell.Cdr] {
+			cell.Cdr = self.replaceAliases(cell.Cdr, renamed, quoted, env)
+		}
+	}
+	return form
+}
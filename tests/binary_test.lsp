@@ -71,4 +71,81 @@
              (assert-error (right-shift '(a b) 2))
              (assert-error (right-shift 2 'a))
              (assert-error (right-shift 2 '(a b))))
+
+         (it "can xor"
+             (assert-eq (binary-xor 0x0a 0x0f)
+                        0x05)
+             (assert-eq (binary-xor 0xaa 0xaa)
+                        0x00)
+
+             (assert-error (binary-xor 'a 2))
+             (assert-error (binary-xor 2 '(a b))))
+
+         (it "can combine any number of integers"
+             (assert-eq (bitwise-and 0xff 0x0f 0x3c) 0x0c)
+             (assert-eq (bitwise-and) -1)
+             (assert-eq (bitwise-and -1 0xa5) 0xa5)
+             (assert-eq (bitwise-or 0x01 0x02 0x04) 0x07)
+             (assert-eq (bitwise-or) 0)
+             (assert-eq (bitwise-xor 0x0f 0xff 0x01) 0xf1)
+             (assert-eq (bitwise-or 18446744073709551616 1) 18446744073709551617)
+             (assert-eq (bitwise-not 0) -1)
+             (assert-eq (bitwise-not 0x0f) -16)
+
+             (assert-error (bitwise-and 1 'a))
+             (assert-error (bitwise-or 1.5)))
+
+         (it "can shift arithmetically"
+             (assert-eq (arithmetic-shift 0x05 2) 0x14)
+             (assert-eq (arithmetic-shift 0x14 -2) 0x05)
+             (assert-eq (arithmetic-shift -8 -1) -4)
+             (assert-eq (arithmetic-shift -1 -10) -1)
+             (assert-eq (arithmetic-shift 5 -100) 0)
+             (assert-eq (arithmetic-shift 1 64) 18446744073709551616)
+
+             (assert-error (arithmetic-shift 1 'a)))
+
+         (it "can count bits"
+             (assert-eq (bit-count 0) 0)
+             (assert-eq (bit-count 0xf0f0) 8)
+             (assert-eq (bit-count -1) 0)
+             (assert-eq (bit-count -2) 1)
+             (assert-eq (integer-length 0) 0)
+             (assert-eq (integer-length 0xff) 8)
+             (assert-eq (integer-length 0x100) 9)
+             (assert-eq (integer-length -1) 0)
+             (assert-eq (integer-length -129) 8))
+
+         (it "can test and change single bits"
+             (assert-true (bit-set? 0x04 2))
+             (assert-false (bit-set? 0x04 1))
+             (assert-true (bit-set? -1 100))
+             (assert-eq (set-bit 0x01 3) 0x09)
+             (assert-eq (set-bit 0x09 3) 0x09)
+             (assert-eq (clear-bit 0x0f 0) 0x0e)
+             (assert-eq (clear-bit -1 0) -2)
+
+             (assert-error (bit-set? 1 -1))
+             (assert-error (set-bit 'a 1)))
+
+         (it "can extract and insert bit fields"
+             (assert-eq (bit-field 0xabcd 4 12) 0xbc)
+             (assert-eq (bit-field 0xabcd 0 4) 0x0d)
+             (assert-eq (bit-field 0xabcd 4 4) 0)
+             (assert-eq (bit-field -1 0 8) 0xff)
+             (assert-eq (bit-field-insert 0xabcd 0x12 4 12) 0xa12d)
+             (assert-eq (bit-field-insert 0x0000 0xfff 4 8) 0xf0)
+
+             (assert-error (bit-field 0xff 8 4))
+             (assert-error (bit-field-insert 0xff 1 8 4)))
+
+         (it "can sign and zero extend fields"
+             (assert-eq (sign-extend 0xff 8) -1)
+             (assert-eq (sign-extend 0x7f 8) 127)
+             (assert-eq (sign-extend 0x0800 12) -2048)
+             (assert-eq (sign-extend 0x1ff 8) -1)
+             (assert-eq (sign-extend 5 0) 0)
+             (assert-eq (zero-extend -1 8) 0xff)
+             (assert-eq (zero-extend -2048 12) 0x800)
+             (assert-eq (sign-extend (bit-field 0x0f00 8 12) 4) -1))
 )
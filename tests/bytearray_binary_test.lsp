@@ -0,0 +1,121 @@
+;;; -*- mode: Scheme -*-
+
+(context "bytearray numbers"
+
+         ()
+
+         (it "reads integers"
+             (let ((b [1 2 3 4 5 6 7 8 255 255]))
+               (assert-eq (bytearray-u8-ref b 8) 255)
+               (assert-eq (bytearray-s8-ref b 8) -1)
+               (assert-eq (bytearray-u16-ref b 0) 513)
+               (assert-eq (bytearray-u16-le-ref b 0) 513)
+               (assert-eq (bytearray-u16-be-ref b 0) 258)
+               (assert-eq (bytearray-u16-ref b 0 'big) 258)
+               (assert-eq (bytearray-s16-ref b 8) -1)
+               (assert-eq (bytearray-u16-ref b 8) 65535)
+               (assert-eq (bytearray-u32-be-ref b 0) 16909060)
+               (assert-eq (bytearray-s32-le-ref [255 255 255 255] 0) -1)
+               (assert-eq (bytearray-u64-be-ref b 0) 72623859790382856)
+               (assert-eq (bytearray-u64-ref [255 255 255 255 255 255 255 255] 0) 18446744073709551615)
+               (assert-eq (bytearray-s64-ref [255 255 255 255 255 255 255 255] 0) -1)))
+
+         (it "writes integers"
+             (let ((b (list->bytearray '(0 0 0 0 0 0 0 0))))
+               (assert-eq (bytearray-u16-be-set! b 0 258) [1 2 0 0 0 0 0 0])
+               (bytearray-s32-set! b 2 -2)
+               (assert-eq b [1 2 254 255 255 255 0 0])
+               (bytearray-s32-set! b 2 -2 'big)
+               (assert-eq b [1 2 255 255 255 254 0 0])
+               (bytearray-u64-le-set! b 0 18446744073709551615)
+               (assert-eq b [255 255 255 255 255 255 255 255])
+               (bytearray-u8-set! b 0 7)
+               (assert-eq (bytearray-u8-ref b 0) 7)))
+
+         (it "reads and writes floats"
+             (let ((b (list->bytearray '(0 0 0 0 0 0 0 0))))
+               (bytearray-f32-set! b 0 1.5)
+               (assert-eq (bytearray-f32-ref b 0) 1.5)
+               (assert-eq b [0 0 192 63 0 0 0 0])
+               (bytearray-f32-be-set! b 4 -2)
+               (assert-eq (bytearray-f32-be-ref b 4) -2.0)
+               (bytearray-f64-set! b 0 0.1)
+               (assert-eq (bytearray-f64-ref b 0) 0.1)
+               (bytearray-f64-be-set! b 0 1/4)
+               (assert-eq (bytearray-f64-be-ref b 0) 0.25)))
+
+         (it "checks ranges"
+             (let ((b [0 0 0 0]))
+               (assert-error (bytearray-u16-ref b 3))
+               (assert-error (bytearray-u32-ref b 1))
+               (assert-error (bytearray-u8-ref b -1))
+               (assert-error (bytearray-u16-set! b 0 65536))
+               (assert-error (bytearray-s16-set! b 0 32768))
+               (assert-error (bytearray-u8-set! b 0 -1))
+               (assert-error (bytearray-u16-set! b 0 1.5))
+               (assert-error (bytearray-u16-ref b 0 'middle))
+               (assert-error (bytearray-u16-ref '(1 2) 0)))))
+
+(context "pack and unpack"
+
+         ()
+
+         (it "packs values"
+             (assert-eq (pack "<HI" '(1 2)) [1 0 2 0 0 0])
+             (assert-eq (pack ">HI" '(1 2)) [0 1 0 0 0 2])
+             (assert-eq (pack "!bB?x" '(-1 255 #t)) [255 255 1 0])
+             (assert-eq (pack "2H 3s" '(1 2 "ab")) [1 0 2 0 97 98 0])
+             (assert-eq (pack ">f" '(1.5)) [63 192 0 0])
+             (assert-eq (pack "<q" '(-2)) [254 255 255 255 255 255 255 255]))
+
+         (it "unpacks values"
+             (assert-eq (unpack "<HI" [1 0 2 0 0 0]) '(1 2))
+             (assert-eq (unpack ">hb?" [255 254 128 1]) '(-2 -128 #t))
+             (assert-eq (unpack "2x2s" [0 0 97 98]) (list [97 98]))
+             (assert-eq (unpack "<d" (pack "<d" '(2.5))) '(2.5))
+             (assert-eq (unpack ">H" [9 0 1 2] 2) '(258))
+             (assert-eq (unpack "B" [1 2 3]) '(1)))
+
+         (it "measures formats"
+             (assert-eq (pack-size "<HIq") 14)
+             (assert-eq (pack-size "3x2s") 5)
+             (assert-eq (pack-size "") 0)
+             (assert-eq (pack-size "1000000q") 8000000)
+             (assert-eq (pack-size "3?2h") 7))
+
+         (it "repeats codes with counts"
+             (assert-eq (pack "<3B2?" '(1 2 3 #t #f)) [1 2 3 1 0])
+             (assert-eq (unpack "<3B2?" [1 2 3 1 0]) '(1 2 3 #t #f))
+             (assert-eq (pack "0q" '()) [])
+             (assert-error (pack "3B" '(1 2))))
+
+         (it "limits the size of formats"
+             (assert-error (pack "99999999999999x" '()))
+             (assert-error (pack-size "1000000000q"))
+             (assert-error (pack-size "99999999999999999999x"))
+             (assert-error (pack-size "16777216x16777216x")))
+
+         (it "reports errors"
+             (assert-error (pack "<H" '()))
+             (assert-error (pack "<H" '(1 2)))
+             (assert-error (pack "<H" '(70000)))
+             (assert-error (pack "<Z" '(1)))
+             (assert-error (pack "<3" '(1)))
+             (assert-error (pack "s" '(1)))
+             (assert-error (unpack "<I" [1 2]))
+             (assert-error (unpack "<H" [1 2] 3))
+             (assert-error (pack-size 1))
+             (assert-error (pack "@H" '(1)))
+             (assert-error (pack-size "@H")))
+
+         (it "names the primitive once in its errors"
+             (assert-eq (condition/report-string (ignore-errors (pack "<H" '())))
+                        "pack: fewer values than the format needs.")
+             (assert-eq (condition/report-string (ignore-errors (pack "<H" '(1 2))))
+                        "pack: more values than the format needs.")
+             (assert-eq (condition/report-string (ignore-errors (pack "2s" '(1))))
+                        "pack: 2s needs a string or bytearray, but was given 1.")
+             (assert-eq (condition/report-string (ignore-errors (unpack "<I" [1 2])))
+                        "unpack: the format needs 4 bytes but was given 2.")
+             (assert-eq (condition/report-string (ignore-errors (pack-size "<Z")))
+                        "pack-size: unknown format code 'Z'.")))
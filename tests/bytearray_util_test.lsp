@@ -0,0 +1,79 @@
+;;; -*- mode: Scheme -*-
+
+(context "bytearray utilities"
+
+         ()
+
+         (it "makes bytearrays"
+             (assert-eq (make-bytearray 3) [0 0 0])
+             (assert-eq (make-bytearray 2 255) [255 255])
+             (assert-eq (make-bytearray 0) [])
+             (assert-error (make-bytearray -1))
+             (assert-error (make-bytearray 2 256)))
+
+         (it "measures bytearrays"
+             (assert-eq (bytearray-length [1 2 3]) 3)
+             (assert-eq (bytearray-length []) 0)
+             (assert-error (bytearray-length '(1 2))))
+
+         (it "finds bytes and patterns"
+             (let ((b [1 2 170 85 3 170 85 4]))
+               (assert-eq (bytearray-index-of b 170) 2)
+               (assert-eq (bytearray-index-of b [170 85]) 2)
+               (assert-eq (bytearray-index-of b [170 85] 3) 5)
+               (assert-eq (bytearray-index-of b 170 6) #f)
+               (assert-eq (bytearray-index-of b [85 170]) #f)
+               (assert-eq (bytearray-index-of b [] 4) 4)
+               (assert-error (bytearray-index-of b 170 9))
+               (assert-error (bytearray-index-of b 300))))
+
+         (it "compares bytearrays"
+             (assert-eq (bytearray-compare [1 2 3] [1 2 3]) 0)
+             (assert-eq (bytearray-compare [1 2] [1 2 3]) -1)
+             (assert-eq (bytearray-compare [1 3] [1 2 3]) 1)
+             (assert-eq (bytearray-compare [] []) 0))
+
+         (it "fills bytearrays"
+             (let ((b (make-bytearray 5)))
+               (assert-eq (bytearray-fill! b 7) [7 7 7 7 7])
+               (bytearray-fill! b 1 2)
+               (assert-eq b [7 7 1 1 1])
+               (bytearray-fill! b 0 1 3)
+               (assert-eq b [7 0 0 1 1])
+               (assert-error (bytearray-fill! b 0 3 1))
+               (assert-error (bytearray-fill! b 0 0 6))))
+
+         (it "copies between bytearrays"
+             (let ((b (make-bytearray 5)))
+               (assert-eq (bytearray-copy! b 1 [1 2 3]) [0 1 2 3 0])
+               (bytearray-copy! b 0 [9 8 7 6] 2)
+               (assert-eq b [7 6 2 3 0])
+               (bytearray-copy! b 4 [9 8 7 6] 1 2)
+               (assert-eq b [7 6 2 3 8])
+               (assert-error (bytearray-copy! b 3 [1 2 3]))
+               (assert-error (bytearray-copy! b 0 [1 2 3] 2 1))))
+
+         (it "copies overlapping ranges"
+             (let ((b (list->bytearray '(1 2 3 4 5))))
+               (bytearray-copy! b 1 b 0 4)
+               (assert-eq b [1 1 2 3 4])
+               (bytearray-copy! b 0 b 1)
+               (assert-eq b [1 2 3 4 4])))
+
+         (it "slices bytearrays"
+             (let ((b [1 2 3 4 5]))
+               (assert-eq (bytearray-slice b 1 3) [2 3])
+               (assert-eq (bytearray-slice b 3) [4 5])
+               (assert-eq (bytearray-slice b 5) [])
+               (assert-error (bytearray-slice b 6))
+               (assert-error (bytearray-slice b 3 2))))
+
+         (it "shares bytes with slices"
+             (let* ((b (list->bytearray '(1 2 3 4 5)))
+                    (s (bytearray-slice b 1 4)))
+               (bytearray-fill! s 0)
+               (assert-eq b [1 0 0 0 5])
+               (replace-byte! b 2 9)
+               (assert-eq s [0 9 0])
+               (append-bytes! s [6])
+               (assert-eq b [1 0 9 0 5]))))
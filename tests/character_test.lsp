@@ -0,0 +1,90 @@
+;;; -*- mode: Scheme -*-
+
+(context "characters"
+
+         ()
+
+         (it "literals"
+             (assert-true (char? #\a))
+             (assert-false (char? "a"))
+             (assert-eq (char->integer #\a) 97)
+             (assert-eq (char->integer #\space) 32)
+             (assert-eq (char->integer #\newline) 10)
+             (assert-eq (char->integer #\tab) 9)
+             (assert-eq (char->integer #\x41) 65)
+             (assert-eq (char->integer #\() 40)
+             (assert-eq (list #\a #\b) (string->list "ab")))
+
+         (it "printing"
+             (assert-eq (str '(#\a)) "(#\\a)")
+             (assert-eq (str '(#\space #\newline #\x7)) "(#\\space #\\newline #\\alarm)")
+             (assert-eq (format #f "~A~S" #\a #\b) "a#\\b"))
+
+         (it "conversion"
+             (assert-eq (integer->char 65) #\A)
+             (assert-error (integer->char -1))
+             (assert-error (integer->char 'a))
+             (assert-error (char->integer "a"))
+             (assert-eq (char-upcase #\a) #\A)
+             (assert-eq (char-downcase #\A) #\a)
+             (assert-eq (char-upcase #\1) #\1)
+             (assert-eq (char->digit #\7) 7)
+             (assert-eq (char->digit #\f 16) 15)
+             (assert-false (char->digit #\a))
+             (assert-eq (digit->char 7) #\7)
+             (assert-eq (digit->char 11 16) #\b))
+
+         (it "classification"
+             (assert-true (char-alphabetic? #\a))
+             (assert-false (char-alphabetic? #\1))
+             (assert-true (char-numeric? #\1))
+             (assert-false (char-numeric? #\a))
+             (assert-true (char-whitespace? #\space))
+             (assert-true (char-whitespace? #\tab))
+             (assert-false (char-whitespace? #\a))
+             (assert-true (char-upper-case? #\A))
+             (assert-true (char-lower-case? #\a))
+             (assert-error (char-alphabetic? "a")))
+
+         (it "comparison"
+             (assert-true (char=? #\a #\a))
+             (assert-false (char=? #\a #\b))
+             (assert-true (char<? #\a #\b #\c))
+             (assert-false (char<? #\a #\c #\b))
+             (assert-true (char>? #\b #\a))
+             (assert-true (char<=? #\a #\a #\b))
+             (assert-true (char>=? #\b #\b #\a))
+             (assert-true (char-ci=? #\a #\A))
+             (assert-true (equal? #\a #\a))
+             (assert-false (equal? #\a "a"))))
+
+(context "characters in strings"
+
+         ()
+
+         (it "string-ref"
+             (assert-eq (string-ref "abc" 1) #\b)
+             (assert-eq (string-ref "héllo" 1) #\é)
+             (assert-error (string-ref "abc" 3))
+             (assert-error (string-ref "abc" -1))
+             (assert-error (string-ref 'abc 0)))
+
+         (it "string-set!"
+             (let ((s (str "abc")))
+               (string-set! s 1 #\x)
+               (assert-eq s "axc")
+               (assert-error (string-set! s 0 "y"))
+               (assert-error (string-set! s 5 #\y))))
+
+         (it "string->list and list->string"
+             (assert-eq (string->list "abc") '(#\a #\b #\c))
+             (assert-eq (string->list "") '())
+             (assert-eq (list->string '(#\a #\b)) "ab")
+             (assert-eq (list->string '()) "")
+             (assert-error (list->string '(#\a 1))))
+
+         (it "string-for-each"
+             (let ((count 0))
+               (string-for-each (lambda (c) (if (char-alphabetic? c) (set! count (+ count 1)))) "a1b2c")
+               (assert-eq count 3))
+             (assert-error (string-for-each 1 "a"))))
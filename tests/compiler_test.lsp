@@ -0,0 +1,176 @@
+;;; -*- mode: Scheme -*-
+
+(define (make-counter)
+  (let ((count 0))
+    (lambda ()
+      (set! count (+ count 1))
+      count)))
+
+(define (internal-defines x)
+  (define y (* x 2))
+  (define (add-y z) (+ y z))
+  (add-y x))
+
+(define (shadowing x)
+  (let ((x (+ x 1))
+        (y x))
+    (let* ((x (* x 10))
+           (y (+ x y)))
+      (list x y))))
+
+(define (letrec-parity n)
+  (letrec ((ev? (lambda (n) (if (zero? n) #t (od? (- n 1)))))
+           (od? (lambda (n) (if (zero? n) #f (ev? (- n 1))))))
+    (ev? n)))
+
+(define (sum-to n)
+  (let loop ((i 0) (acc 0))
+    (if (> i n)
+        acc
+        (loop (+ i 1) (+ acc i)))))
+
+(define (reverse-with-do l)
+  (do ((l l (cdr l))
+       (acc '() (cons (car l) acc)))
+      ((nil? l) acc)))
+
+(define (rest-args a . rest)
+  (list a rest))
+
+(define (only-rest . rest)
+  rest)
+
+(defmacro (my-swap! a b)
+  (let ((tmp (gensym)))
+    `(let ((,tmp ,a))
+       (set! ,a ,b)
+       (set! ,b ,tmp))))
+
+(define (swapped a b)
+  (my-swap! a b)
+  (list a b))
+
+(define (adds-binding-at-runtime)
+  (eval '(define late 5))
+  late)
+
+(define (count-down-compiled n)
+  (if (zero? n)
+      'done
+      (count-down-compiled (- n 1))))
+
+(define (failing-inner x)
+  (car x 1))
+
+(define (failing-outer y)
+  (+ 1 (failing-inner (* y 2))))
+
+(define (failure-message thunk)
+  (on-error (thunk) (lambda (err) (condition/report-string err))))
+
+(define (with-compilation enabled thunk)
+  (let ((was (compile-functions)))
+    (compile-functions enabled)
+    (let ((result (thunk)))
+      (compile-functions was)
+      result)))
+
+(context "compiled functions"
+
+         ()
+
+         (it "closures share captured variables"
+             (let ((c (make-counter)))
+               (c)
+               (c)
+               (assert-eq (c) 3)))
+
+         (it "internal defines"
+             (assert-eq (internal-defines 3) 9))
+
+         (it "let and let* shadowing"
+             (assert-eq (shadowing 1) '(20 21)))
+
+         (it "letrec"
+             (assert-true (letrec-parity 100))
+             (assert-false (letrec-parity 7)))
+
+         (it "named let"
+             (assert-eq (sum-to 100) 5050))
+
+         (it "do"
+             (assert-eq (reverse-with-do '(1 2 3)) '(3 2 1)))
+
+         (it "rest arguments"
+             (assert-eq (rest-args 1 2 3) '(1 (2 3)))
+             (assert-eq (rest-args 1) '(1 ()))
+             (assert-eq (only-rest 1 2 3) '(1 2 3))
+             (assert-eq (only-rest) '()))
+
+         (it "macros"
+             (assert-eq (swapped 1 2) '(2 1)))
+
+         (it "bindings added at runtime"
+             (assert-eq (adds-binding-at-runtime) 5))
+
+         (it "redefined globals are seen"
+             (define (compiled-helper) 1)
+             (define (calls-helper) (compiled-helper))
+             (assert-eq (calls-helper) 1)
+             (define (compiled-helper) 2)
+             (assert-eq (calls-helper) 2))
+
+         (it "deep tail recursion"
+             (assert-eq (count-down-compiled 1000000) 'done))
+
+         (it "frame slot functions"
+             (let ((f {a: 5
+                       b: 2
+                       foo: (lambda (x)
+                              (+ x a))
+                       bar: (lambda ()
+                              (set! b 3)
+                              (foo b))}))
+               (assert-eq (send f bar:) 8)
+               (assert-eq (get-slot f b:) 3)))
+
+         (it "arity errors"
+             (assert-error (make-counter 1))
+             (assert-error (rest-args))))
+
+(context "compiled and interpreted functions agree"
+
+         ()
+
+         (it "results"
+             (assert-eq (with-compilation #f (lambda () (list (shadowing 1) (sum-to 10) (swapped 1 2))))
+                        (with-compilation #t (lambda () (list (shadowing 1) (sum-to 10) (swapped 1 2))))))
+
+         (it "function shapes"
+             (let ((shapes (lambda ()
+                             (let ((c (make-counter)))
+                               (c)
+                               (list (c)
+                                     (internal-defines 3)
+                                     (shadowing 1)
+                                     (letrec-parity 7)
+                                     (sum-to 10)
+                                     (reverse-with-do '(1 2 3))
+                                     (rest-args 1 2 3)
+                                     (rest-args 1)
+                                     (only-rest 1 2 3)
+                                     (only-rest)
+                                     (swapped 1 2))))))
+               (assert-eq (with-compilation #f shapes)
+                          (with-compilation #t shapes))))
+
+         (it "error messages"
+             (assert-eq (with-compilation #f (lambda () (failure-message (lambda () (failing-outer 21)))))
+                        (with-compilation #t (lambda () (failure-message (lambda () (failing-outer 21)))))))
+
+         (it "backtraces"
+             (let ((backtrace-of (lambda ()
+                                   (map (lambda (f) (list (function: f) (args: f) (expr: f)))
+                                        (on-error (failing-outer 21) (lambda (err bt) bt))))))
+               (assert-eq (with-compilation #f backtrace-of)
+                          (with-compilation #t backtrace-of)))))
@@ -0,0 +1,177 @@
+;;; -*- mode: Scheme -*-
+
+(define (condition-type-of thunk)
+  (condition/type (ignore-errors (thunk))))
+
+(context "error"
+
+         ()
+
+         (it "signals a simple-error with a message and irritants"
+             (let ((c (ignore-errors (error "Value out of bounds:" 42 "high"))))
+               (assert-true (condition? c))
+               (assert-eq (condition/type c) 'simple-error)
+               (assert-eq (access-condition c 'message) "Value out of bounds:")
+               (assert-eq (access-condition c 'irritants) '(42 "high"))
+               (assert-eq (condition/report-string c) "Value out of bounds: 42 \"high\"")))
+
+         (it "accepts a non-string message"
+             (assert-eq (condition/report-string (ignore-errors (error 'oops))) "oops"))
+
+         (it "is caught by on-error as a condition"
+             (assert-eq (on-error (error "bad" 1) (lambda (e) (condition/report-string e))) "bad 1")
+             (assert-eq (on-error (car 1 2) (lambda (e) (condition/type e))) 'wrong-number-of-arguments)
+             (assert-eq (on-error (raise 'boom) (lambda (e) e)) 'boom)))
+
+(context "condition types"
+
+         ()
+
+         (it "distinguishes wrong-type arguments"
+             (assert-eq (condition-type-of (lambda () (+ 1 'a))) 'wrong-type-argument))
+
+         (it "distinguishes unbound variables"
+             (assert-eq (condition-type-of (lambda () (no-such-function 1))) 'unbound-variable))
+
+         (it "distinguishes inapplicable objects"
+             (assert-eq (condition-type-of (lambda () (5 1))) 'inapplicable-object))
+
+         (it "distinguishes wrong numbers of arguments"
+             (assert-eq (condition-type-of (lambda () (car 1 2))) 'wrong-number-of-arguments)
+             (assert-eq (condition-type-of (lambda () ((lambda (x) x)))) 'wrong-number-of-arguments))
+
+         (it "distinguishes bad range arguments"
+             (assert-eq (condition-type-of (lambda () (substring "abc" 5 6))) 'bad-range-argument))
+
+         (it "distinguishes division by zero"
+             (assert-eq (condition-type-of (lambda () (quotient 1 0))) 'divide-by-zero))
+
+         (it "treats every condition as an error"
+             (let ((c (ignore-errors (car 1 2))))
+               (assert-true (condition/type? c 'error))
+               (assert-true (condition/type? c 'wrong-number-of-arguments))
+               (assert-false (condition/type? c 'unbound-variable))))
+
+         (it "rejects unknown condition types"
+             (assert-error (condition/type? (make-condition 'simple-error "x") 'no-such-type))))
+
+(context "raise"
+
+         ()
+
+         (it "raises conditions"
+             (let ((c (make-condition 'bad-range-argument "index too big" '(10))))
+               (assert-eq (ignore-errors (raise c)) c)))
+
+         (it "raises arbitrary objects"
+             (assert-eq (guard (e (#t e)) (raise 'boom)) 'boom)
+             (assert-eq (guard (e ((number? e) (* e 2))) (raise 21)) 42)))
+
+(context "with-exception-handler"
+
+         ()
+
+         (it "returns the thunk's value when nothing is raised"
+             (assert-eq (with-exception-handler (lambda (e) 'handled) (lambda () 'normal)) 'normal))
+
+         (it "passes the condition to the handler"
+             (assert-eq (call/cc (lambda (k)
+                                   (with-exception-handler (lambda (e) (k (condition/type e)))
+                                                           (lambda () (+ 1 "2")))))
+                        'wrong-type-argument)
+             (assert-eq (call/cc (lambda (k)
+                                   (with-exception-handler (lambda (e) (k (condition/type e)))
+                                                           (lambda () (car 1 2)))))
+                        'wrong-number-of-arguments))
+
+         (it "calls the handler before unwinding"
+             (let ((events '()))
+               (call/cc (lambda (k)
+                          (with-exception-handler (lambda (e)
+                                                    (set! events (cons 'handler events))
+                                                    (k e))
+                                                  (lambda ()
+                                                    (dynamic-wind (lambda () #f)
+                                                                  (lambda () (raise 'oops))
+                                                                  (lambda () (set! events (cons 'after events))))))))
+               (assert-eq (reverse events) '(handler after))))
+
+         (it "signals an error when a handler returns from raise"
+             (assert-error (with-exception-handler (lambda (e) 'ignored) (lambda () (raise 'oops))))
+             (assert-error (with-exception-handler (lambda (e) 'ignored) (lambda () (error "oops"))))
+             (assert-eq (guard (e (#t (error-object-irritants e)))
+                          (with-exception-handler (lambda (e) 'ignored) (lambda () (raise 'oops))))
+                        '(oops)))
+
+         (it "returns the handler's value from raise-continuable"
+             (assert-eq (with-exception-handler (lambda (e) (* e 2))
+                                                (lambda () (+ 1 (raise-continuable 21))))
+                        43))
+
+         (it "runs the handler with the outer handler installed"
+             (assert-eq (with-exception-handler (lambda (e) (list 'outer e))
+                                                (lambda ()
+                                                  (with-exception-handler (lambda (e) (raise-continuable (list 'inner e)))
+                                                                          (lambda () (raise-continuable 'x)))))
+                        '(outer (inner x))))
+
+         (it "leaves errors caught inside the thunk alone"
+             (assert-eq (with-exception-handler (lambda (e) 'handler)
+                                                (lambda () (guard (e (#t (list 'guard e))) (raise 'x))))
+                        '(guard x))
+             (assert-eq (with-exception-handler (lambda (e) 'handler)
+                                                (lambda () (condition/type (ignore-errors (car 1 2)))))
+                        'wrong-number-of-arguments))
+
+         (it "can escape through a continuation"
+             (assert-eq (call/cc (lambda (k)
+                                   (with-exception-handler (lambda (e) (k (error-object-message e)))
+                                                           (lambda () (error "escaped")))))
+                        "escaped"))
+
+         (it "lets continuations pass through"
+             (assert-eq (call/cc (lambda (k)
+                                   (with-exception-handler (lambda (e) 'handled)
+                                                           (lambda () (k 'jumped)))))
+                        'jumped)))
+
+(context "guard"
+
+         ()
+
+         (it "returns the body's value when nothing is raised"
+             (assert-eq (guard (e (#t 'handled)) 1 2 3) 3))
+
+         (it "selects a clause by condition type"
+             (define (classify thunk)
+               (guard (e ((condition/type? e 'wrong-type-argument) 'wrong-type)
+                         ((condition/type? e 'unbound-variable) 'unbound)
+                         (else 'other))
+                      (thunk)))
+             (assert-eq (classify (lambda () (car 1 2))) 'other)
+             (assert-eq (classify (lambda () (string-length 5))) 'wrong-type)
+             (assert-eq (classify (lambda () (undefined-thing))) 'unbound))
+
+         (it "returns the test value for clauses without a body"
+             (assert-eq (guard (e ((error-irritants e))) (error "x" 1 2)) '(1 2)))
+
+         (it "passes the test value to the receiver of => clauses"
+             (assert-eq (guard (e ((assq 'a e) => cdr) ((assq 'b e))) (raise (list (cons 'a 42)))) 42)
+             (assert-eq (guard (e ((assq 'a e) => cdr) ((assq 'b e))) (raise (list (cons 'b 23)))) '(b . 23))
+             (assert-eq (guard (e ((error-irritants e) => (lambda (irritants) (apply + irritants)))) (error "x" 1 2)) 3)
+             (assert-error (guard (e (#t => 'not-a-function)) (raise 1)))
+             (assert-error (guard (e (#t => car cdr)) (raise 1))))
+
+         (it "re-raises when no clause matches"
+             (assert-eq (condition/type (ignore-errors (guard (e ((string? e) 'string)) (quotient 1 0))))
+                        'divide-by-zero)))
+
+(context "ignore-errors"
+
+         ()
+
+         (it "returns the value of the body"
+             (assert-eq (ignore-errors 1 2) 2))
+
+         (it "returns the condition in place of an error"
+             (assert-true (error? (ignore-errors (error "x"))))))
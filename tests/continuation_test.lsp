@@ -0,0 +1,133 @@
+;;; -*- mode: Scheme -*-
+
+(define wind-log '())
+
+(define (note x)
+  (set! wind-log (cons x wind-log)))
+
+(define (find-first pred l)
+  (call/cc (lambda (return)
+             (for-each (lambda (x)
+                         (when (pred x)
+                           (return x)))
+                       l)
+             #f)))
+
+(define saved-k nil)
+
+(context "call/cc"
+
+         ()
+
+         (it "returns normally when not invoked"
+             (assert-eq (call/cc (lambda (k) 42)) 42)
+             (assert-eq (call-with-current-continuation (lambda (k) 'a)) 'a))
+
+         (it "escapes with a value"
+             (assert-eq (+ 1 (call/cc (lambda (k) (+ 10 (k 5))))) 6)
+             (assert-eq (call-with-escape-continuation (lambda (k) (k 'out) 'not-reached)) 'out))
+
+         (it "escapes from user functions"
+             (assert-eq (find-first even? '(1 3 4 5 6)) 4)
+             (assert-false (find-first even? '(1 3 5))))
+
+         (it "escapes from special forms"
+             (assert-eq (call/cc (lambda (k)
+                                   (let loop ((i 0))
+                                     (if (== i 10)
+                                         (k i)
+                                         (loop (+ i 1))))))
+                        10)
+             (assert-eq (call/cc (lambda (k)
+                                   (do ((i 0 (+ i 1)))
+                                       ((== i 100) 'finished)
+                                     (when (== i 3)
+                                       (k i)))))
+                        3)
+             (assert-eq (call/cc (lambda (k)
+                                   (cond ((k 'from-cond) 1)
+                                         (else 2))))
+                        'from-cond))
+
+         (it "escapes from primitives that call back into lisp"
+             (assert-eq (call/cc (lambda (k) (map (lambda (x) (if (> x 1) (k x) x)) '(1 2 3)))) 2)
+             (assert-eq (call/cc (lambda (k) (sort '(3 1 2) (lambda (a b) (k 'sorted))))) 'sorted)
+             (assert-eq (call/cc (lambda (k) (reduce (lambda (a b) (k b)) 0 '(1 2 3)))) 2))
+
+         (it "escapes through on-error"
+             (assert-eq (call/cc (lambda (k)
+                                   (on-error (k 'escaped)
+                                             (lambda (err) 'caught))))
+                        'escaped))
+
+         (it "nested continuations"
+             (assert-eq (call/cc (lambda (outer)
+                                   (+ 1 (call/cc (lambda (inner)
+                                                   (outer 10))))))
+                        10)
+             (assert-eq (call/cc (lambda (outer)
+                                   (+ 1 (call/cc (lambda (inner)
+                                                   (inner 10))))))
+                        11))
+
+         (it "multiple values are passed as a list"
+             (assert-eq (call/cc (lambda (k) (k 1 2 3))) '(1 2 3)))
+
+         (it "can't be re-entered"
+             (call/cc (lambda (k) (set! saved-k k)))
+             (assert-true (continuation? saved-k))
+             (assert-error (saved-k 1)))
+
+         (it "continuation?"
+             (assert-false (continuation? car))
+             (assert-true (call/cc (lambda (k) (continuation? k)))))
+
+         (it "within-continuation"
+             (assert-eq (+ 1 (call/cc (lambda (k)
+                                        (within-continuation k (lambda () 41)))))
+                        42)
+             (set! wind-log '())
+             (assert-eq (call/cc (lambda (k)
+                                   (dynamic-wind (lambda () (note 'before))
+                                                 (lambda () (within-continuation k (lambda () (note 'thunk) 'done)))
+                                                 (lambda () (note 'after)))))
+                        'done)
+             (assert-eq wind-log '(thunk after before))))
+
+(context "dynamic-wind"
+
+         ((set! wind-log '()))
+
+         (it "runs before, thunk, and after in order"
+             (assert-eq (dynamic-wind (lambda () (note 'before))
+                                      (lambda () (note 'during) 'result)
+                                      (lambda () (note 'after)))
+                        'result)
+             (assert-eq wind-log '(after during before)))
+
+         (it "runs after when escaping"
+             (assert-eq (call/cc (lambda (k)
+                                   (dynamic-wind (lambda () (note 'before))
+                                                 (lambda () (k 'escaped) (note 'during))
+                                                 (lambda () (note 'after)))))
+                        'escaped)
+             (assert-eq wind-log '(after before)))
+
+         (it "runs after when unwinding through several levels"
+             (call/cc (lambda (k)
+                        (dynamic-wind (lambda () (note 'outer-before))
+                                      (lambda ()
+                                        (dynamic-wind (lambda () (note 'inner-before))
+                                                      (lambda () (k 'escaped))
+                                                      (lambda () (note 'inner-after))))
+                                      (lambda () (note 'outer-after)))))
+             (assert-eq wind-log '(outer-after inner-after inner-before outer-before)))
+
+         (it "runs after on error"
+             (assert-error (dynamic-wind (lambda () (note 'before))
+                                         (lambda () (error "oops"))
+                                         (lambda () (note 'after))))
+             (assert-eq wind-log '(after before)))
+
+         (it "requires functions"
+             (assert-error (dynamic-wind 1 (lambda () 1) (lambda () 2)))))
@@ -0,0 +1,41 @@
+;;; -*- mode: Scheme -*-
+
+(context "bytearray encodings"
+
+         ()
+
+         (it "converts to and from hex"
+             (assert-eq (bytearray->hex [0 1 171 255]) "0001abff")
+             (assert-eq (hex->bytearray "0001abff") [0 1 171 255])
+             (assert-eq (hex->bytearray "0001ABFF") [0 1 171 255])
+             (assert-eq (bytearray->hex []) "")
+             (assert-error (hex->bytearray "abc"))
+             (assert-error (hex->bytearray "zz"))
+             (assert-error (bytearray->hex "00")))
+
+         (it "converts to and from base64"
+             (assert-eq (bytearray->base64 (string->utf8 "hello")) "aGVsbG8=")
+             (assert-eq (utf8->string (base64->bytearray "aGVsbG8=")) "hello")
+             (assert-eq (base64->bytearray (bytearray->base64 [0 255 128])) [0 255 128])
+             (assert-error (base64->bytearray "!!"))
+             (assert-error (bytearray->base64 1))))
+
+(context "checksums and digests"
+
+         ()
+
+         (it "computes checksums"
+             (assert-eq (crc32 "123456789") 3421780262)
+             (assert-eq (crc32 (string->utf8 "123456789")) 3421780262)
+             (assert-eq (crc32 "56789" (crc32 "1234")) 3421780262)
+             (assert-eq (crc16-ccitt "123456789") 10673)
+             (assert-eq (crc16-ccitt "56789" (crc16-ccitt "1234")) 10673)
+             (assert-eq (adler32 "123456789") 152961502)
+             (assert-error (crc32 1))
+             (assert-error (crc16-ccitt "a" 65536)))
+
+         (it "computes digests"
+             (assert-eq (bytearray->hex (md5 "abc")) "900150983cd24fb0d6963f7d28e17f72")
+             (assert-eq (bytearray->hex (sha1 "abc")) "a9993e364706816aba3e25717850c26c9cd0d89d")
+             (assert-eq (bytearray->hex (sha256 [97 98 99])) "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad")
+             (assert-error (sha256 'abc))))
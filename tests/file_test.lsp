@@ -0,0 +1,87 @@
+;;; -*- mode: Scheme -*-
+
+(define test-dir (let ((tmp (temporary-file-name)))
+                   (delete-file tmp)
+                   (path-join (path-directory tmp) "golisp-file-test")))
+
+(define (remove-test-dir)
+  (for-each delete-file (list-directory test-dir))
+  (delete-file test-dir))
+
+(context "filesystem"
+
+         ((if (file-exists? test-dir) (remove-test-dir))
+          (make-directory test-dir))
+
+         (it "creates and inspects files"
+             (let ((name (path-join test-dir "a.txt")))
+               (assert-false (file-exists? name))
+               (with-output-to-file name (lambda () (write-string "hello")))
+               (assert-true (file-exists? name))
+               (assert-false (file-directory? name))
+               (assert-true (file-directory? test-dir))
+               (assert-eq (file-length name) 5)
+               (assert-true (> (file-modification-time name) 1500000000))
+               (remove-test-dir)))
+
+         (it "renames, copies and deletes files"
+             (let ((a (path-join test-dir "a.txt"))
+                   (b (path-join test-dir "b.txt"))
+                   (c (path-join test-dir "c.txt")))
+               (with-output-to-file a (lambda () (write-string "contents")))
+               (rename-file a b)
+               (assert-false (file-exists? a))
+               (copy-file b c)
+               (assert-eq (with-input-from-file c read-line) "contents")
+               (delete-file b)
+               (assert-false (file-exists? b))
+               (assert-error (delete-file b))
+               (assert-error (file-length b))
+               (remove-test-dir)))
+
+         (it "makes nested directories"
+             (let ((nested (path-join test-dir "x" "y")))
+               (assert-error (make-directory nested))
+               (make-directory nested #t)
+               (assert-true (file-directory? nested))
+               (delete-file nested)
+               (delete-file (path-join test-dir "x"))
+               (remove-test-dir)))
+
+         (it "changes the working directory"
+             (let ((original (pwd)))
+               (set-working-directory! test-dir)
+               (assert-eq (path-basename (pwd)) "golisp-file-test")
+               (set-working-directory! original)
+               (assert-eq (pwd) original)
+               (assert-error (set-working-directory! (path-join test-dir "missing")))
+               (remove-test-dir)))
+
+         (it "makes temporary files"
+             (let ((name (temporary-file-name "golisp-test")))
+               (assert-true (file-exists? name))
+               (assert-eq (file-length name) 0)
+               (delete-file name)
+               (remove-test-dir)))
+
+         (it "is restricted"
+             (let ()
+               (restrict-environment)
+               (assert-error (file-exists? test-dir))
+               (assert-error (pwd)))
+             (remove-test-dir)))
+
+(context "paths"
+
+         ()
+
+         (it "joins paths"
+             (assert-eq (path-join "a" "b" "c.txt") "a/b/c.txt")
+             (assert-eq (path-join "/a/" "b") "/a/b")
+             (assert-error (path-join "a" 1)))
+
+         (it "splits paths"
+             (assert-eq (path-basename "/a/b/c.txt") "c.txt")
+             (assert-eq (path-directory "/a/b/c.txt") "/a/b")
+             (assert-eq (path-extension "/a/b/c.txt") ".txt")
+             (assert-eq (path-extension "/a/b/c") "")))
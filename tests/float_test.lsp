@@ -58,3 +58,48 @@
          (it "rejects converting a string to integer"
              (assert-error (integer "5.3"))))
 
+
+(context precision
+
+         ()
+
+         (it "keeps double precision"
+             (assert-eq (+ 0.1 0.2)
+                        0.30000000000000004)
+             (assert-eq (- 16777217.0 16777216.0)
+                        1.0)
+             (assert-eq (inexact->exact 0.1)
+                        3602879701896397/36028797018963968)))
+
+(context bits
+
+         ()
+
+         (it "converts to and from 64-bit patterns"
+             (assert-eq (float->bits 1.0)
+                        4607182418800017408)
+             (assert-eq (float->bits -2.0)
+                        13835058055282163712)
+             (assert-eq (bits->float 4607182418800017408)
+                        1.0)
+             (assert-eq (bits->float 13835058055282163712)
+                        -2.0)
+             (assert-eq (bits->float (float->bits 0.1))
+                        0.1)
+             (assert-error (bits->float 1.0))
+             (assert-error (bits->float 18446744073709551616)))
+
+         (it "converts to and from 32-bit patterns"
+             (assert-eq (float->bits32 1.0)
+                        1065353216)
+             (assert-eq (bits32->float 1065353216)
+                        1.0)
+             (assert-eq (bits32->float (float->bits32 -2.5))
+                        -2.5)
+             (assert-true (!= (bits32->float (float->bits32 0.1)) 0.1))
+             (assert-error (float->bits32 1))
+             (assert-true (float? (bits32->float 4294967295)))
+             (assert-error (bits32->float 1.0))
+             (assert-error (bits32->float 4294967296))
+             (assert-error (bits32->float -1))
+             (assert-eq (condition/type (ignore-errors (bits32->float -1))) 'bad-range-argument)))
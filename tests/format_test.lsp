@@ -0,0 +1,237 @@
+;;; -*- mode: Scheme -*-
+
+(context "format basics"
+
+         ()
+
+         (it "substitutes objects"
+             (assert-eq (format #f "~A and ~S" "a" "b")
+                        "a and \"b\"")
+             (assert-eq (format #f "~5A|" 'ab)
+                        "ab   |")
+             (assert-eq (format #f "~5@A|" 'ab)
+                        "   ab|")
+             (assert-eq (format #f "~VA|" 4 1)
+                        "1   |")
+             (assert-eq (format #f "~5,,,'.A" "ab")
+                        "ab..."))
+
+         (it "handles newlines and tildes"
+             (assert-eq (format #f "a~%b~2%c")
+                        "a\nb\n\nc")
+             (assert-eq (format #f "~~ ~3~")
+                        "~ ~~~")
+             (assert-eq (format #f "a~
+                                    b")
+                        "ab")
+             (assert-eq (format #f "a~@
+                                    b")
+                        "a\nb"))
+
+         (it "checks its arguments"
+             (assert-error (format #f "~A"))
+             (assert-error (format #f "~A" 1 2))
+             (assert-error (format #f "~Q" 1))
+             (assert-error (format #f "~{~A" '(1)))
+             (assert-error (format #f "~A~]" 1))))
+
+(context "format integers"
+
+         ()
+
+         (it "prints in bases"
+             (assert-eq (format #f "~D ~B ~O ~X" 10 5 8 255)
+                        "10 101 10 FF")
+             (assert-eq (format #f "~D" 123456789012345678901234567890)
+                        "123456789012345678901234567890"))
+
+         (it "pads"
+             (assert-eq (format #f "~5D|" 42)
+                        "   42|")
+             (assert-eq (format #f "~5,'0D" 42)
+                        "00042")
+             (assert-eq (format #f "~8,'0B" 5)
+                        "00000101"))
+
+         (it "prints signs"
+             (assert-eq (format #f "~@D ~@D ~D" 5 -5 -5)
+                        "+5 -5 -5"))
+
+         (it "groups digits"
+             (assert-eq (format #f "~:D" 1234567)
+                        "1,234,567")
+             (assert-eq (format #f "~,,'.:D" 1234567)
+                        "1.234.567")
+             (assert-eq (format #f "~,,' ,4:B" 255)
+                        "1111 1111")
+             (assert-eq (format #f "~:D" -1000)
+                        "-1,000"))
+
+         (it "prints other objects like ~A"
+             (assert-eq (format #f "~D" "x")
+                        "x")))
+
+(context "format floats"
+
+         ()
+
+         (it "prints fixed point"
+             (assert-eq (format #f "~F" 3.5)
+                        "3.5")
+             (assert-eq (format #f "~,2F" 3.14159)
+                        "3.14")
+             (assert-eq (format #f "~8,2F|" 3.14159)
+                        "    3.14|")
+             (assert-eq (format #f "~,2@F" 3.0)
+                        "+3.00")
+             (assert-eq (format #f "~,2F" 1/4)
+                        "0.25")
+             (assert-eq (format #f "~,1F" 5)
+                        "5.0")
+             (assert-eq (format #f "~5F" 3.14159)
+                        "3.142")
+             (assert-eq (format #f "~6F" -3.14159)
+                        "-3.142")
+             (assert-eq (format #f "~,2,2F" 0.5)
+                        "50.00")
+             (assert-eq (format #f "~3,1,,'*F" 1234.5)
+                        "***"))
+
+         (it "prints exponential"
+             (assert-eq (format #f "~E" 100.0)
+                        "1.0e+2")
+             (assert-eq (format #f "~,2E" 1234.5)
+                        "1.23e+3")
+             (assert-eq (format #f "~,2,2E" 0.001)
+                        "1.00e-03")
+             (assert-eq (format #f "~10,1E|" 1234.5)
+                        "    1.2e+3|"))
+
+         (it "prints money"
+             (assert-eq (format #f "~$" 3.14159)
+                        "3.14")
+             (assert-eq (format #f "~$" 0.5)
+                        "0.50")
+             (assert-eq (format #f "~,3$" 2)
+                        "002.00")
+             (assert-eq (format #f "~2,1,8$|" 12.5)
+                        "   12.50|")
+             (assert-eq (format #f "~@$" 12.5)
+                        "+12.50")
+             (assert-eq (format #f "~2,1,8,'0:$" -12.5)
+                        "-0012.50")))
+
+(context "format characters"
+
+         ()
+
+         (it "prints characters"
+             (assert-eq (format #f "~C" #\a)
+                        "a")
+             (assert-eq (format #f "~@C" #\a)
+                        "#\\a")
+             (assert-eq (format #f "~:C" #\space)
+                        "Space")
+             (assert-eq (format #f "~:C" #\newline)
+                        "Newline")
+             (assert-eq (format #f "~:C" #\tab)
+                        "Tab")
+             (assert-eq (format #f "~:C" #\delete)
+                        "Delete")
+             (assert-eq (format #f "~:C" #\a)
+                        "a")
+             (assert-error (format #f "~C" "a"))))
+
+(context "format control flow"
+
+         ()
+
+         (it "selects clauses"
+             (assert-eq (format #f "~[zero~;one~;two~]" 1)
+                        "one")
+             (assert-eq (format #f "~[zero~;one~]" 5)
+                        "")
+             (assert-eq (format #f "~[zero~;one~:;many~]" 5)
+                        "many")
+             (assert-eq (format #f "~1[zero~;one~]")
+                        "one")
+             (assert-eq (format #f "~:[no~;yes~]" #t)
+                        "yes")
+             (assert-eq (format #f "~:[no~;yes~]" #f)
+                        "no")
+             (assert-eq (format #f "~@[value: ~A~]" 5)
+                        "value: 5")
+             (assert-eq (format #f "~@[value: ~A~]" #f)
+                        ""))
+
+         (it "iterates"
+             (assert-eq (format #f "~{~A~^, ~}" '(1 2 3))
+                        "1, 2, 3")
+             (assert-eq (format #f "~{~A~}" '())
+                        "")
+             (assert-eq (format #f "~:{~A=~A ~}" '((a 1) (b 2)))
+                        "a=1 b=2 ")
+             (assert-eq (format #f "~@{~A~^-~}" 1 2 3)
+                        "1-2-3")
+             (assert-eq (format #f "~:@{~A~A~}" '(a 1) '(b 2))
+                        "a1b2")
+             (assert-eq (format #f "~2{~A~}" '(1 2 3))
+                        "12")
+             (assert-eq (format #f "~{x~:}" '())
+                        "x")
+             (assert-eq (format #f "~{~A ~A~^; ~}" '(a 1 b 2))
+                        "a 1; b 2")
+             (assert-error (format #f "~{~A~}" 5)))
+
+         (it "converts case"
+             (assert-eq (format #f "~(~A~)" "Hello World")
+                        "hello world")
+             (assert-eq (format #f "~:(~A~)" "hello big world")
+                        "Hello Big World")
+             (assert-eq (format #f "~@(~A~)" "hello BIG world")
+                        "Hello big world")
+             (assert-eq (format #f "~:@(~A~)" "hello")
+                        "HELLO"))
+
+         (it "moves among arguments"
+             (assert-eq (format #f "~A ~* ~A" 1 2 3)
+                        "1  3")
+             (assert-eq (format #f "~A ~:*~A" 1)
+                        "1 1")
+             (assert-eq (format #f "~A ~A ~0@*~A" 1 2)
+                        "1 2 1")
+             (assert-error (format #f "~:*~A" 1)))
+
+         (it "counts remaining arguments"
+             (assert-eq (format #f "~#[none~;one~;two~]~*~*" 1 2)
+                        "two")))
+
+(context "format layout"
+
+         ()
+
+         (it "starts fresh lines"
+             (assert-eq (format #f "~&a~&b")
+                        "a\nb")
+             (assert-eq (format #f "a~%~&b")
+                        "a\nb")
+             (assert-eq (format #f "a~2&b")
+                        "a\n\nb"))
+
+         (it "tabulates"
+             (assert-eq (format #f "ab~5Tc")
+                        "ab   c")
+             (assert-eq (format #f "abcdef~4Tc")
+                        "abcdef c")
+             (assert-eq (format #f "abcdef~4,4Tc")
+                        "abcdef  c")
+             (assert-eq (format #f "ab~3@Tc")
+                        "ab   c")
+             (assert-eq (format #f "ab~1,4@Tc")
+                        "ab  c")
+             (assert-eq (format #f "x~%ab~4Tc")
+                        "x\nab  c"))
+
+         (it "builds report lines"
+             (assert-eq (format #f "~10A|~8,2F|~:D" "total" 12.345 1234)
+                        "total     |   12.35|1,234")))
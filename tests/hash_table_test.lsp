@@ -0,0 +1,111 @@
+;;; -*- mode: Scheme -*-
+
+(define (sorted-alist table)
+  (sort (hash-table->alist table) (lambda (a b) (< (cdr a) (cdr b)))))
+
+(context "hash tables"
+
+         ()
+
+         (it "construction"
+             (assert-true (hash-table? (make-equal-hash-table)))
+             (assert-true (hash-table? (make-eqv-hash-table 10)))
+             (assert-true (hash-table? (make-string-hash-table)))
+             (assert-false (hash-table? '()))
+             (assert-eq (hash-table-count (make-equal-hash-table)) 0)
+             (assert-error (make-equal-hash-table 'a)))
+
+         (it "set and ref"
+             (let ((h (make-equal-hash-table)))
+               (assert-eq (hash-table-set! h 'a 1) 1)
+               (hash-table-set! h '(1 2) 2)
+               (hash-table-set! h "three" 3)
+               (assert-eq (hash-table-ref h 'a) 1)
+               (assert-eq (hash-table-ref h (list 1 2)) 2)
+               (assert-eq (hash-table-ref h "three") 3)
+               (assert-eq (hash-table-count h) 3)
+               (hash-table-set! h 'a 10)
+               (assert-eq (hash-table-ref h 'a) 10)
+               (assert-eq (hash-table-count h) 3)))
+
+         (it "ref with missing keys"
+             (let ((h (make-equal-hash-table)))
+               (hash-table-set! h 'a 1)
+               (assert-error (hash-table-ref h 'b))
+               (assert-eq (hash-table-ref h 'b (lambda () 'missing)) 'missing)
+               (assert-eq (hash-table-ref h 'a (lambda () 'missing) (lambda (v) (+ v 1))) 2)
+               (assert-eq (hash-table-ref/default h 'b 42) 42)
+               (assert-eq (hash-table-ref/default h 'a 42) 1)))
+
+         (it "contains and delete"
+             (let ((h (make-equal-hash-table)))
+               (hash-table-set! h 'a 1)
+               (assert-true (hash-table-contains? h 'a))
+               (assert-true (hash-table-exists? h 'a))
+               (assert-true (hash-table-delete! h 'a))
+               (assert-false (hash-table-delete! h 'a))
+               (assert-false (hash-table-contains? h 'a))
+               (assert-eq (hash-table-count h) 0)))
+
+         (it "update"
+             (let ((h (make-equal-hash-table)))
+               (hash-table-set! h 'a 1)
+               (assert-eq (hash-table-update! h 'a (lambda (v) (+ v 1))) 2)
+               (assert-eq (hash-table-ref h 'a) 2)
+               (assert-error (hash-table-update! h 'b (lambda (v) (+ v 1))))
+               (hash-table-update! h 'b (lambda (v) (+ v 1)) (lambda () 0))
+               (assert-eq (hash-table-ref h 'b) 1)
+               (hash-table-update!/default h 'c (lambda (l) (cons 'x l)) '())
+               (hash-table-update!/default h 'c (lambda (l) (cons 'y l)) '())
+               (assert-eq (hash-table-ref h 'c) '(y x))))
+
+         (it "walk and conversion"
+             (let ((h (make-equal-hash-table))
+                   (total 0))
+               (hash-table-set! h 'a 1)
+               (hash-table-set! h 'b 2)
+               (hash-table-set! h 'c 3)
+               (hash-table-walk h (lambda (k v) (set! total (+ total v))))
+               (assert-eq total 6)
+               (assert-eq (sorted-alist h) '((a . 1) (b . 2) (c . 3)))
+               (assert-eq (sort (hash-table-values h) <) '(1 2 3))
+               (assert-eq (length (hash-table-keys h)) 3)))
+
+         (it "eqv tables compare objects by identity"
+             (let ((h (make-eqv-hash-table))
+                   (key (list 1 2)))
+               (hash-table-set! h key 'list)
+               (hash-table-set! h 5 'five)
+               (hash-table-set! h 'sym 'symbol)
+               (assert-eq (hash-table-ref h key) 'list)
+               (assert-false (hash-table-contains? h (list 1 2)))
+               (assert-eq (hash-table-ref h 5) 'five)
+               (assert-eq (hash-table-ref h 'sym) 'symbol)))
+
+         (it "string tables only take strings"
+             (let ((h (make-string-hash-table)))
+               (hash-table-set! h "a" 1)
+               (assert-eq (hash-table-ref h (str "a")) 1)
+               (assert-error (hash-table-set! h 'a 1))
+               (assert-error (hash-table-ref h 1))))
+
+         (it "copy, clear and equality"
+             (let* ((h (make-equal-hash-table))
+                    (c (begin (hash-table-set! h 'a 1)
+                              (hash-table-copy h))))
+               (assert-eq h c)
+               (assert-eq (copy h) h)
+               (hash-table-set! c 'b 2)
+               (assert-neq h c)
+               (assert-eq (hash-table-count h) 1)
+               (hash-table-clear! c)
+               (assert-eq (hash-table-count c) 0)))
+
+         (it "printing"
+             (let ((h (make-string-hash-table)))
+               (hash-table-set! h "a" 1)
+               (assert-eq (str h) "<hash table (string=?): 1 entries>")))
+
+         (it "type errors"
+             (assert-error (hash-table-ref '() 'a))
+             (assert-error (hash-table-walk (make-equal-hash-table) 'a))))
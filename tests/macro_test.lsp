@@ -3,6 +3,9 @@
 (defmacro (add x y)
   `(+ ,x ,@y))
 
+(defmacro (quote-all . args)
+  `(quote ,args))
+
 
 (context "macro"
 
@@ -42,6 +45,12 @@
              (assert-eq (add 1 (2 3))
                         6))
 
+         (it defmacro-with-only-rest-parameter
+             (assert-eq (quote-all 1 2)
+                        '(1 2))
+             (assert-eq (quote-all)
+                        '()))
+
          (it expand
              (assert-eq (expand add 1 (2 3))
                         '(+ 1 2 3)))
@@ -53,4 +62,45 @@
          (it defmacro-errors
              (assert-error (defmacro "x" 1))
              (assert-error (defmacro ("x") 1)))
-)
+
+         (it "expands each call site once"
+             (define expansions 0)
+             (defmacro (counted x)
+               (begin (set! expansions (+ expansions 1))
+                      x))
+             (define (use-counted) (counted 42))
+             (assert-eq (use-counted) 42)
+             (assert-eq (use-counted) 42)
+             (assert-eq (use-counted) 42)
+             (assert-eq expansions 1))
+
+         (it "re-expands when the macro is redefined"
+             (defmacro (changing) 1)
+             (define (use-changing) (changing))
+             (assert-eq (use-changing) 1)
+             (defmacro (changing) 2)
+             (assert-eq (use-changing) 2))
+
+         (it macroexpand-1
+             (assert-eq (macroexpand-1 '(add 1 (2 3)))
+                        '(+ 1 2 3))
+             (assert-eq (macroexpand-1 '(list 1 2))
+                        '(list 1 2))
+             (assert-eq (macroexpand-1 5)
+                        5))
+
+         (it macroexpand
+             (defmacro (add-twice x) `(add ,x (,x)))
+             (assert-eq (macroexpand-1 '(add-twice 1))
+                        '(add 1 (1)))
+             (assert-eq (macroexpand '(add-twice 1))
+                        '(+ 1 1)))
+
+         (it macroexpand-all
+             (assert-eq (macroexpand-all '(list (add 1 (2)) '(add 1 (2)) `(add ,(add 3 (4)))))
+                        '(list (+ 1 2) '(add 1 (2)) `(add ,(+ 3 4))))
+             (assert-eq (macroexpand-all '(lambda (add) (add 1 (2))))
+                        '(lambda (add) (+ 1 2)))
+             (assert-eq (macroexpand-all '(let loop ((add (add 1 (2)))) add))
+                        '(let loop ((add (+ 1 2))) add))))
+
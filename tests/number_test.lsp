@@ -0,0 +1,225 @@
+;;; -*- mode: Scheme -*-
+
+(define big 123456789012345678901234567890)
+
+(context "bignums"
+
+         ()
+
+         (it promotes-on-overflow
+             (assert-eq (+ 9223372036854775807 1)
+                        9223372036854775808)
+             (assert-eq (- -9223372036854775808 1)
+                        -9223372036854775809)
+             (assert-eq (* 4294967296 4294967296)
+                        18446744073709551616)
+             (assert-eq (succ 9223372036854775807)
+                        9223372036854775808))
+
+         (it demotes-when-small
+             (assert-true (integer? (- (+ 9223372036854775807 1) 1)))
+             (assert-eq (- (+ 9223372036854775807 1) 1)
+                        9223372036854775807)
+             (assert-eq (- big big)
+                        0))
+
+         (it arithmetic
+             (assert-eq (+ big big)
+                        246913578024691357802469135780)
+             (assert-eq (* big 10)
+                        1234567890123456789012345678900)
+             (assert-eq (quotient big 1000000000000)
+                        123456789012345678)
+             (assert-eq (% big 1000)
+                        890)
+             (assert-eq (abs (- 0 big))
+                        big)
+             (assert-eq (pow 2 100)
+                        1267650600228229401496703205376))
+
+         (it predicates
+             (assert-true (integer? big))
+             (assert-true (number? big))
+             (assert-true (exact? big))
+             (assert-true (even? big))
+             (assert-false (odd? big))
+             (assert-true (positive? big))
+             (assert-true (negative? (- 0 big)))
+             (assert-eq (sign (- 0 big))
+                        -1))
+
+         (it comparisons
+             (assert-true (< 9223372036854775807 9223372036854775808))
+             (assert-true (> big 9223372036854775807))
+             (assert-true (<= big big))
+             (assert-eq (max (list 1 big 3))
+                        big)
+             (assert-eq (min (list 1 (- 0 big) 3))
+                        (- 0 big)))
+
+         (it number->string
+             (assert-eq (number->string big)
+                        "123456789012345678901234567890")
+             (assert-eq (number->string (pow 2 64) 16)
+                        "10000000000000000")
+             (assert-eq (string->number "123456789012345678901234567890")
+                        big)
+             (assert-eq (string->number "10000000000000000" 16)
+                        18446744073709551616))
+
+         (it rejected-as-counts
+             (assert-error (take 100000000000000000000 '(1 2)))
+             (assert-error (drop 100000000000000000000 '(1 2)))
+             (assert-error (interval 1 100000000000000000000))
+             (assert-error (interval (- 0 big)))))
+
+(context "rationals"
+
+         ()
+
+         (it division
+             (assert-eq (number->string (/ 1 3))
+                        "1/3")
+             (assert-eq (/ 6 4)
+                        3/2)
+             (assert-eq (/ 6 3)
+                        2)
+             (assert-true (integer? (/ 6 3)))
+             (assert-eq (/ 1 2 3)
+                        1/6)
+             (assert-eq (/ 1.0 4)
+                        0.25)
+             (assert-error (/ 1/2 0)))
+
+         ;; Integer division no longer truncates: (/ 7 2) used to be 3.
+         ;; quotient gives the truncated result.
+         (it division-of-integers-is-exact
+             (assert-eq (/ 7 2)
+                        7/2)
+             (assert-false (integer? (/ 7 2)))
+             (assert-eq (quotient 7 2)
+                        3))
+
+         (it arithmetic
+             (assert-eq (+ 1/3 1/6)
+                        1/2)
+             (assert-eq (+ 1/3 2/3)
+                        1)
+             (assert-eq (- 1/2 1)
+                        -1/2)
+             (assert-eq (* 2/3 3/4)
+                        1/2)
+             (assert-eq (+ 1/2 0.25)
+                        0.75)
+             (assert-eq (abs -1/2)
+                        1/2)
+             (assert-eq (pow 2/3 2)
+                        4/9)
+             (assert-eq (pow 2 -2)
+                        1/4))
+
+         (it quotient-stays-integral
+             (assert-eq (quotient 7 2)
+                        3)
+             (assert-eq (quotient -7 2)
+                        -3))
+
+         (it comparisons
+             (assert-true (< 1/3 1/2))
+             (assert-true (> 1/2 0.4))
+             (assert-false (< 1/2 1/2))
+             (assert-true (>= 1/2 1/2))
+             (assert-eq (min (list 1/2 1/3))
+                        1/3)
+             (assert-true (== 1/2 0.5))
+             (assert-true (== 1 1.0))
+             (assert-true (== big (exact->inexact big)))
+             (assert-false (== 1/3 0.5))
+             (assert-true (!= 1/3 0.5))
+             (assert-false (!= 1/2 0.5))
+             (assert-false (eqv? 1/2 0.5)))
+
+         (it rounding
+             (assert-eq (floor 7/2)
+                        3)
+             (assert-eq (floor -7/2)
+                        -4)
+             (assert-eq (ceiling 7/2)
+                        4)
+             (assert-eq (ceiling -7/2)
+                        -3)
+             (assert-eq (integer -7/2)
+                        -3))
+
+         (it numerator-and-denominator
+             (assert-eq (numerator 6/4)
+                        3)
+             (assert-eq (denominator 6/4)
+                        2)
+             (assert-eq (numerator 5)
+                        5)
+             (assert-eq (denominator 5)
+                        1)
+             (assert-eq (denominator 0.5)
+                        2.0)
+             (assert-error (numerator 'a)))
+
+         (it predicates
+             (assert-true (rational? 1/2))
+             (assert-true (rational? 2))
+             (assert-false (rational? 0.5))
+             (assert-false (integer? 1/2))
+             (assert-true (number? 1/2))
+             (assert-true (zero? (- 1/2 1/2)))
+             (assert-true (negative? -1/2)))
+
+         (it read-and-print
+             (assert-eq (number->string -3/4)
+                        "-3/4")
+             (assert-eq (string->number "-3/4")
+                        -3/4)
+             (assert-eq (string->number "8/4")
+                        2)
+             (assert-eq (number->string 1/2 2)
+                        "1/10"))
+
+         (it floats-round-trip
+             (assert-eq (format #f "~A" 1e30)
+                        "1e+30")
+             (assert-eq (read (open-input-string (format #f "~A" 1.5e-30)))
+                        1.5e-30)
+             (assert-eq (read (open-input-string "-2.5e-7"))
+                        -0.00000025)
+             (assert-eq (read (open-input-string "4E2"))
+                        400.0)))
+
+(context "exactness"
+
+         ()
+
+         (it exact->inexact
+             (assert-eq (exact->inexact 1/4)
+                        0.25)
+             (assert-eq (exact->inexact 3)
+                        3.0)
+             (assert-eq (exact->inexact 0.5)
+                        0.5))
+
+         (it inexact->exact
+             (assert-eq (inexact->exact 0.25)
+                        1/4)
+             (assert-eq (inexact->exact 3.0)
+                        3)
+             (assert-eq (inexact->exact 1/3)
+                        1/3)
+             (assert-error (inexact->exact nan))
+             (assert-error (inexact->exact +inf)))
+
+         (it exact?-and-inexact?
+             (assert-true (exact? 1))
+             (assert-true (exact? 1/2))
+             (assert-false (exact? 1.5))
+             (assert-true (inexact? 1.5))
+             (assert-false (inexact? 1/2))
+             (assert-error (exact? 'a))
+             (assert-error (inexact? "1"))))
@@ -0,0 +1,88 @@
+;;; -*- mode: Scheme -*-
+
+(context "reading characters and lines"
+
+         ()
+
+         (it "reads lines"
+             (let ((p (open-input-string "one\ntwo\n\nthree")))
+               (assert-eq (read-line p) "one")
+               (assert-eq (read-line p) "two")
+               (assert-eq (read-line p) "")
+               (assert-eq (read-line p) "three")
+               (assert-true (eof-object? (read-line p)))))
+
+         (it "reads and peeks characters"
+             (let ((p (open-input-string "aλ")))
+               (assert-eq (peek-char p) #\a)
+               (assert-eq (read-char p) #\a)
+               (assert-eq (peek-char p) #\λ)
+               (assert-eq (read-char p) #\λ)
+               (assert-true (eof-object? (peek-char p)))
+               (assert-true (eof-object? (read-char p)))))
+
+         (it "reads strings"
+             (let ((p (open-input-string "abcdefg")))
+               (assert-eq (read-string 0 p) "")
+               (assert-eq (read-string 3 p) "abc")
+               (assert-eq (read-string 10 p) "defg")
+               (assert-true (eof-object? (read-string 1 p)))))
+
+         (it "reads bytes"
+             (let ((p (open-input-string "abcλ")))
+               (assert-eq (read-bytes 2 p) [97 98])
+               (assert-eq (read-char p) #\c)
+               (assert-eq (read-bytes 10 p) [206 187])
+               (assert-true (eof-object? (read-bytes 1 p)))))
+
+         (it "mixes reading objects with reading characters"
+             (let ((p (open-input-string "(a b) rest of line\n42 x")))
+               (assert-eq (read p) '(a b))
+               (assert-eq (read-line p) " rest of line")
+               (assert-eq (read p) 42)
+               (assert-eq (read-char p) #\space)
+               (assert-eq (read p) 'x)
+               (assert-true (eof-object? (read-char p)))))
+
+         (it "is always ready on string ports"
+             (assert-true (char-ready? (open-input-string "a")))
+             (assert-true (char-ready? (open-input-string ""))))
+
+         (it "reports bad arguments"
+             (assert-error (read-line 1))
+             (assert-error (read-char (open-output-string)))
+             (assert-error (read-string -1 (open-input-string "a")))
+             (assert-error (read-bytes 'a (open-input-string "a")))))
+
+(context "file ports"
+
+         ()
+
+         (it "redirects output to a file and input from it"
+             (with-output-to-file "/tmp/golisp-port-reading-test.txt"
+               (lambda ()
+                 (write-string "first line")
+                 (newline)
+                 (write '(1 2))))
+             (assert-eq (with-input-from-file "/tmp/golisp-port-reading-test.txt"
+                          (lambda ()
+                            (list (read-line) (read) (eof-object? (read-char)))))
+                        '("first line" (1 2) #t))
+             (let ((p (open-input-file "/tmp/golisp-port-reading-test.txt")))
+               (assert-eq (read-char p) #\f)
+               (assert-eq (read-line p) "irst line")
+               (close-port p)))
+
+         (it "restores the current ports"
+             (let ((in (current-input-port))
+                   (out (current-output-port)))
+               (with-input-from-file "/tmp/golisp-port-reading-test.txt" (lambda () (read-char)))
+               (assert-true (eq? (current-input-port) in))
+               (assert-true (eq? (current-output-port) out))
+               (with-output-to-string (lambda () (write-string "x")))
+               (assert-true (eq? (current-output-port) out))
+               (assert-error (with-output-to-string (lambda () (write-string "x") (error "boom"))))
+               (assert-true (eq? (current-output-port) out))))
+
+         (it "calls primitives with the file as the current port"
+             (assert-eq (with-input-from-file "/tmp/golisp-port-reading-test.txt" read-line) "first line")))
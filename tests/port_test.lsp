@@ -0,0 +1,84 @@
+;;; -*- mode: Scheme -*-
+
+(context "string ports"
+
+         ()
+
+         (it "writes to an output string port"
+             (let ((p (open-output-string)))
+               (write-string "abc" p)
+               (write 42 p)
+               (newline p)
+               (write "d" p)
+               (assert-eq (get-output-string p) "abc42\n\"d\"")))
+
+         (it "formats onto a port"
+             (let ((p (open-output-string)))
+               (format p "~A and ~S" "x" "y")
+               (format p "!")
+               (assert-eq (get-output-string p) "x and \"y\"!")))
+
+         (it "reads from an input string port"
+             (let ((p (open-input-string "(1 2) foo \"bar\" 3.5")))
+               (assert-eq (read p) '(1 2))
+               (assert-eq (read p) 'foo)
+               (assert-eq (read p) "bar")
+               (assert-eq (read p) 3.5)
+               (assert-true (eof-object? (read p)))
+               (assert-true (eof-object? (read p)))))
+
+         (it "captures output with with-output-to-string"
+             (assert-eq (with-output-to-string (lambda ()
+                                                 (write-string "a")
+                                                 (write 'b)
+                                                 (newline)
+                                                 (format #t "~D" 3)))
+                        "ab\n3")
+             (assert-eq (with-output-to-string (lambda () 1)) ""))
+
+         (it "nests with-output-to-string"
+             (assert-eq (with-output-to-string
+                         (lambda ()
+                           (write-string "outer ")
+                           (write-string (with-output-to-string (lambda () (write-string "inner"))))))
+                        "outer inner"))
+
+         (it "restores the current output port"
+             (assert-eq (with-output-to-string
+                         (lambda ()
+                           (with-output-to-string (lambda () (write-string "x")))
+                           (on-error (with-output-to-string (lambda () (write-string "x") (error "boom")))
+                                     (lambda (err) nil))
+                           (write-string "y")))
+                        "y"))
+
+         (it "sends every output primitive to the current output port"
+             (assert-eq (with-output-to-string (lambda () (write-string "a")))
+                        "a")
+             (assert-eq (with-output-to-string (lambda () (write '(1 "b"))))
+                        "(1 \"b\")")
+             (assert-eq (with-output-to-string (lambda () (newline)))
+                        "\n")
+             (assert-eq (with-output-to-string (lambda () (format #t "~A-~S" 1 "c")))
+                        "1-\"c\"")
+             (assert-eq (with-output-to-string (lambda () (write-line "d" 2)))
+                        "d2\n")
+             (assert-eq (with-output-to-string (lambda () (write-log "e" 3)))
+                        (list->string (list #\e #\3 #\return #\newline))))
+
+         (it "only redirects output for the extent of the call"
+             (let ((later nil))
+               (with-output-to-string (lambda () (set! later (lambda () (write-string "late")))))
+               (assert-eq (with-output-to-string (lambda () (write-string "x") (later)))
+                          "xlate")))
+
+         (it "captures output from a primitive"
+             (assert-eq (with-output-to-string newline) "\n"))
+
+         (it "reports bad ports"
+             (assert-error (get-output-string (open-input-string "a")))
+             (assert-error (get-output-string 1))
+             (assert-error (open-input-string 1))
+             (assert-error (write-string "a" (open-input-string "")))
+             (assert-error (read (open-output-string)))
+             (assert-error (with-output-to-string 1))))
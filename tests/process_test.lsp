@@ -0,0 +1,63 @@
+;;; -*- mode: Scheme -*-
+
+(context "running commands"
+
+         ()
+
+         (it "captures output and exit status"
+             (let ((r (run-shell-command "echo out; echo err 1>&2; exit 3")))
+               (assert-eq (get-slot r exit-code:) 3)
+               (assert-eq (get-slot r stdout:) "out\n")
+               (assert-eq (get-slot r stderr:) "err\n")
+               (assert-false (get-slot r timed-out:))))
+
+         (it "passes arguments with exec-wait"
+             (let ((r (exec-wait "echo" "a" 'b 42)))
+               (assert-eq (get-slot r exit-code:) 0)
+               (assert-eq (get-slot r stdout:) "a b 42\n")))
+
+         (it "feeds stdin"
+             (assert-eq (get-slot (exec-wait "cat" {stdin: "some input"}) stdout:) "some input")
+             (assert-eq (get-slot (run-shell-command "wc -c" {stdin: [1 2 3]}) stdout:) "3\n"))
+
+         (it "sets the environment and working directory"
+             (assert-eq (get-slot (run-shell-command "echo $GOLISP_TEST" {env: '(("GOLISP_TEST" . "set"))}) stdout:) "set\n")
+             (assert-eq (get-slot (run-shell-command "pwd" {dir: "/"}) stdout:) "/\n"))
+
+         (it "times out"
+             (let ((r (run-shell-command "sleep 5" {timeout: 50})))
+               (assert-true (get-slot r timed-out:))
+               (assert-eq (get-slot r exit-code:) -1)))
+
+         (it "reports bad commands"
+             (assert-error (exec 1))
+             (assert-error (exec-wait "/no/such/command"))
+             (assert-error (run-shell-command 'ls))
+             (assert-error (run-shell-command "ls" 1))
+             (assert-error (run-shell-command "ls" {timeout: "a"}))
+             (assert-error (run-shell-command "ls" {env: '(1 2)}))))
+
+(context "process handles"
+
+         ()
+
+         (it "waits for a process"
+             (let ((p (exec "sh" "-c" "echo started" {capture: #t})))
+               (assert-eq (get-slot (process-wait p) stdout:) "started\n")
+               (assert-eq (get-slot (process-wait p) exit-code:) 0)))
+
+         (it "discards output unless asked to capture it"
+             (let ((p (exec "sh" "-c" "echo started; exit 2")))
+               (assert-nil (get-slot (process-wait p) stdout:))
+               (assert-nil (get-slot (process-wait p) stderr:))
+               (assert-eq (get-slot (process-wait p) exit-code:) 2)))
+
+         (it "kills a process"
+             (let ((p (exec "sleep" "5")))
+               (process-kill p)
+               (assert-eq (get-slot (process-wait p) exit-code:) -1)
+               (process-kill p)))
+
+         (it "rejects other objects"
+             (assert-error (process-wait 1))
+             (assert-error (process-kill "a"))))
@@ -0,0 +1,105 @@
+;;; -*- mode: Scheme -*-
+
+(define-record-type <point>
+  (make-point x y)
+  point?
+  (x point-x set-point-x!)
+  (y point-y set-point-y!)
+  (label point-label set-point-label!))
+
+(define-record-type pair-of-things
+  make-pair-of-things
+  pair-of-things?
+  (left pair-left)
+  (right pair-right))
+
+(context "records"
+
+         ()
+
+         (it "constructs and accesses records"
+             (let ((p (make-point 1 2)))
+               (assert-true (point? p))
+               (assert-eq (point-x p) 1)
+               (assert-eq (point-y p) 2)
+               (assert-nil (point-label p))
+               (assert-error (make-point 1))
+               (assert-error (make-point 1 2 3))))
+
+         (it "modifies records"
+             (let ((p (make-point 1 2)))
+               (set-point-x! p 10)
+               (set-point-label! p "origin")
+               (assert-eq (point-x p) 10)
+               (assert-eq (point-label p) "origin")))
+
+         (it "takes every field with a symbol constructor"
+             (let ((p (make-pair-of-things 'a 'b)))
+               (assert-eq (pair-left p) 'a)
+               (assert-eq (pair-right p) 'b)))
+
+         (it "keeps types distinct"
+             (let ((p (make-point 1 2))
+                   (q (make-pair-of-things 1 2)))
+               (assert-false (point? q))
+               (assert-false (pair-of-things? p))
+               (assert-false (point? '(1 2)))
+               (assert-false (point? {x: 1 y: 2}))
+               (assert-error (point-x q))
+               (assert-error (set-point-x! q 1))
+               (assert-error (point-x {x: 1}))))
+
+         (it "are recognized by type predicates"
+             (assert-true (record? (make-point 1 2)))
+             (assert-false (record? #(1 2)))
+             (assert-false (record? {x: 1}))
+             (assert-true (record-type? <point>))
+             (assert-false (record-type? (make-point 1 2))))
+
+         (it "describes record types"
+             (let ((p (make-point 1 2)))
+               (assert-eq (record-type-descriptor p) <point>)
+               (assert-eq (record-type-name <point>) 'point)
+               (assert-eq (record-type-field-names <point>) '(x y label))
+               (assert-error (record-type-name p))))
+
+         (it "prints records"
+             (assert-eq (format #f "~A" (make-point 1 "a")) "<point x: 1 y: \"a\" label: ()>")
+             (assert-eq (format #f "~A" <point>) "<record type: point>"))
+
+         (it "compares records"
+             (assert-true (equal? (make-point 1 '(2)) (make-point 1 '(2))))
+             (assert-false (equal? (make-point 1 2) (make-point 1 3)))
+             (assert-false (equal? (make-point 1 2) (make-pair-of-things 1 2))))
+
+         (it "copies records"
+             (let* ((p (make-point 1 (list 2 3)))
+                    (c (copy p)))
+               (assert-true (equal? p c))
+               (set-point-x! c 5)
+               (set-car! (point-y c) 9)
+               (assert-eq (point-x p) 1)
+               (assert-eq (point-y p) '(2 3))))
+
+         (it "works as hash table keys"
+             (let ((table (make-equal-hash-table)))
+               (hash-table-set! table (make-point 1 2) 'found)
+               (assert-eq (hash-table-ref/default table (make-point 1 2) #f) 'found)
+               (assert-eq (hash-table-ref/default table (make-point 2 1) #f) #f)))
+
+         (it "can be defined inside functions"
+             (define (make-counter)
+               (define-record-type counter
+                 (new-counter count)
+                 counter?
+                 (count counter-count set-counter-count!))
+               (let ((c (new-counter 0)))
+                 (set-counter-count! c (+ (counter-count c) 1))
+                 (list (counter? c) (counter-count c))))
+             (assert-eq (make-counter) '(#t 1)))
+
+         (it "rejects malformed definitions"
+             (assert-error (define-record-type "bad" (make-bad) bad?))
+             (assert-error (define-record-type bad (make-bad z) bad? (x bad-x)))
+             (assert-error (define-record-type bad (make-bad) bad? (x bad-x) (x bad-x2)))
+             (assert-error (define-record-type bad (make-bad) bad? x))))
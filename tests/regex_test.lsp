@@ -0,0 +1,55 @@
+;;; -*- mode: Scheme -*-
+
+(context "regular expressions"
+
+         ()
+
+         (it "compiles regexes"
+             (assert-true (regex? (make-regex "a+")))
+             (assert-false (regex? "a+"))
+             (assert-error (make-regex "("))
+             (assert-error (make-regex 1)))
+
+         (it "matches"
+             (assert-eq (regex-match "b+" "abbbc") '("bbb"))
+             (assert-eq (regex-match (make-regex "(\\d+)-(\\d+)") "from 10-20") '("10-20" "10" "20"))
+             (assert-eq (regex-match "(a)|(b)" "b") '("b" #f "b"))
+             (assert-false (regex-match "x" "abc"))
+             (assert-error (regex-match "(" "abc"))
+             (assert-error (regex-match "a" 'abc)))
+
+         (it "matches all"
+             (assert-eq (regex-match-all "\\d+" "1 22 333") '(("1") ("22") ("333")))
+             (assert-eq (regex-match-all "(\\w)=(\\d)" "a=1 b=2") '(("a=1" "a" "1") ("b=2" "b" "2")))
+             (assert-eq (regex-match-all "\\d+" "1 22 333" 2) '(("1") ("22")))
+             (assert-eq (regex-match-all "x" "abc") '()))
+
+         (it "matches named groups"
+             (let ((m (regex-match-named "(?P<level>[A-Z]+): (?P<message>.*)" "ERROR: disk full")))
+               (assert-eq (get-slot m level:) "ERROR")
+               (assert-eq (get-slot m message:) "disk full"))
+             (assert-false (get-slot (regex-match-named "(?P<a>a)|(?P<b>b)" "b") a:))
+             (assert-false (regex-match-named "(?P<a>a)" "b")))
+
+         (it "searches"
+             (assert-eq (regex-search "b+" "abbbc") '((1 . 4)))
+             (assert-eq (regex-search "(\\d+)-(\\d+)" "from 10-20") '((5 . 10) (5 . 7) (8 . 10)))
+             (assert-eq (regex-search "é+" "café!") '((3 . 4)))
+             (assert-eq (regex-search "a" "banana" 2) '((3 . 4)))
+             (assert-false (regex-search "a" "banana" 6))
+             (assert-false (regex-search "x" "abc"))
+             (assert-error (regex-search "a" "abc" 4)))
+
+         (it "replaces"
+             (assert-eq (regex-replace "a" "banana" "o") "bonono")
+             (assert-eq (regex-replace "(\\w+)@(\\w+)" "joe@example" "$2 at ${1}") "example at joe")
+             (assert-eq (regex-replace "(?P<n>\\d)" "a1b2" "<${n}>") "a<1>b<2>")
+             (assert-eq (regex-replace "a" "banana" "o" 2) "bonona")
+             (assert-eq (regex-replace "(a)" "banana" "[$1]" 1) "b[a]nana")
+             (assert-eq (regex-replace "a" "banana" "o" 0) "banana"))
+
+         (it "splits"
+             (assert-eq (regex-split ",\\s*" "a, b,c,   d") '("a" "b" "c" "d"))
+             (assert-eq (regex-split "," "a,b,c" 2) '("a" "b,c"))
+             (assert-eq (regex-split "," "abc") '("abc"))
+             (assert-error (regex-split "," "a,b" 'x))))
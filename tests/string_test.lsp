@@ -151,6 +151,10 @@
                         1)
              (assert-eq (string-length "12345")
                         5)
+             (assert-eq (string-length "héllo wörld")
+                        11)
+             (assert-eq (string-length "日本語")
+                        3)
              (assert-error (string-length 5)))
 
 
@@ -164,6 +168,12 @@
                         "")
              (assert-eq (substring "arduous" 2 5)
                         "duo")
+             (assert-eq (substring "crème brûlée" 2 5)
+                        "ème")
+             (assert-eq (substring "日本語" 1 3)
+                        "本語")
+             (assert-error (substring "日本語" 1 4))
+             (assert-error (substring "hello" -1 2))
              (assert-error (substring 5 1 2))
              (assert-error (substring "hello" "a" 5))
              (assert-error (substring "hello" 1 "5"))
@@ -227,4 +237,62 @@
              (assert-false (string>=? "a" "b"))
              (assert-true (string>=? "a" "a"))
              (assert-true (string>=? "a" "A"))
-             (assert-true (string-ci>=? "a" "A"))))
+             (assert-true (string-ci>=? "a" "A")))
+
+         (it string-search-forward
+             (assert-eq (string-search-forward "na" "banana" 0) 2)
+             (assert-eq (string-search-forward "na" "banana" 3) 4)
+             (assert-eq (string-search-forward "é" "crème brûlée" 4) 10)
+             (assert-false (string-search-forward "x" "banana" 0))
+             (assert-eq (string-search-forward "" "abc" 3) 3)
+             (assert-error (string-search-forward "a" "banana" 7))
+             (assert-error (string-search-forward 'a "banana" 0)))
+
+         (it string-search-backward
+             (assert-eq (string-search-backward "na" "banana" 6) 6)
+             (assert-eq (string-search-backward "na" "banana" 5) 4)
+             (assert-eq (string-search-backward "本" "日本語" 3) 2)
+             (assert-false (string-search-backward "ba" "banana" 1)))
+
+         (it string-search-all
+             (assert-eq (string-search-all "a" "banana") '(1 3 5))
+             (assert-eq (string-search-all "ana" "banana") '(1 3))
+             (assert-eq (string-search-all "ü" "über süß") '(0 6))
+             (assert-eq (string-search-all "x" "banana") '()))
+
+         (it string-index
+             (assert-eq (string-index "日本語" #\語) 2)
+             (assert-eq (string-index "abc1" char-numeric?) 3)
+             (assert-false (string-index "abc" #\z))
+             (assert-error (string-index "abc" "a")))
+
+         (it string-pad
+             (assert-eq (string-pad-left "42" 5) "   42")
+             (assert-eq (string-pad-left "42" 5 #\0) "00042")
+             (assert-eq (string-pad-left "12345" 3) "345")
+             (assert-eq (string-pad-right "ü" 3 #\.) "ü..")
+             (assert-eq (string-pad-right "12345" 3) "123")
+             (assert-error (string-pad-left "a" -1))
+             (assert-error (string-pad-right "a" 3 "b")))
+
+         (it string-reverse
+             (assert-eq (string-reverse "héllo") "olléh")
+             (assert-eq (string-reverse "") "")
+             (assert-error (string-reverse 'abc)))
+
+         (it string-normalize
+             (let ((composed (list->string (list (integer->char 233))))
+                   (decomposed (list->string (list #\e (integer->char 769)))))
+               (assert-eq (string-length (string-normalize-nfc decomposed)) 1)
+               (assert-eq (string-normalize-nfc decomposed) composed)
+               (assert-eq (string-normalize-nfd composed) decomposed)
+               (assert-eq (string-length (string-normalize-nfd composed)) 2))
+             (assert-eq (string-normalize-nfkc "ﬁ") "fi")
+             (assert-eq (string-normalize-nfkd "①") "1")
+             (assert-error (string-normalize-nfc 1)))
+
+         (it utf8-conversion
+             (assert-eq (string->utf8 "aé") [97 195 169])
+             (assert-eq (utf8->string [97 195 169]) "aé")
+             (assert-error (utf8->string [255]))
+             (assert-error (string->utf8 1))))
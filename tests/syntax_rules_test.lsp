@@ -0,0 +1,186 @@
+;;; -*- mode: Scheme -*-
+
+(define-syntax swap!
+  (syntax-rules ()
+    ((_ a b)
+     (let ((tmp a))
+       (set! a b)
+       (set! b tmp)))))
+
+(define-syntax my-or
+  (syntax-rules ()
+    ((_) #f)
+    ((_ e) e)
+    ((_ e r ...)
+     (let ((t e))
+       (if t t (my-or r ...))))))
+
+(define-syntax my-let
+  (syntax-rules ()
+    ((_ ((name val) ...) body1 body2 ...)
+     ((lambda (name ...) body1 body2 ...) val ...))))
+
+(define-syntax my-cond
+  (syntax-rules (else)
+    ((_ (else e ...)) (begin e ...))
+    ((_ (c e ...) clause ...) (if c (begin e ...) (my-cond clause ...)))))
+
+(define-syntax while
+  (syntax-rules ()
+    ((_ test body ...)
+     (let loop ()
+       (when test
+         body ...
+         (loop))))))
+
+(define-syntax nested
+  (syntax-rules ()
+    ((_ (a b ...) ...) '((a . (b ...)) ...))))
+
+(define-syntax flatten-args
+  (syntax-rules ()
+    ((_ (a ...) ...) '(a ... ...))))
+
+(define-syntax last-two
+  (syntax-rules ()
+    ((_ x ... y z) '(y z))))
+
+(define-syntax dotted
+  (syntax-rules ()
+    ((_ a . rest) '(a rest))))
+
+(define-syntax custom-ellipsis
+  (syntax-rules ::: ()
+    ((_ x :::) '(x ::: ...))))
+
+(define-syntax escaped-ellipsis
+  (syntax-rules ()
+    ((_ x) '(x (... ...)))))
+
+(define-syntax literal-number
+  (syntax-rules ()
+    ((_ 1) 'one)
+    ((_ x) 'other)))
+
+(define-syntax vector-sum
+  (syntax-rules ()
+    ((_ #(a ...)) (+ a ...))))
+
+(define-syntax vector-swap
+  (syntax-rules ()
+    ((_ #(a b)) #(b a tmp))))
+
+(define-syntax groups->vector
+  (syntax-rules ()
+    ((_ (a b ...) ...) #((a #(b ...)) ...))))
+
+(define-syntax empty-vector?
+  (syntax-rules ()
+    ((_ #()) #t)
+    ((_ x) #f)))
+
+(define-syntax my-list
+  (syntax-rules ()
+    ((_ x) (list x))))
+
+(define-syntax choose
+  (syntax-rules ()
+    ((_ c a b) (if c a b))))
+
+(define macro-counter 1)
+
+(define-syntax get-macro-counter
+  (syntax-rules ()
+    ((_) macro-counter)))
+
+(context "syntax-rules"
+
+         ()
+
+         (it "expands simple patterns"
+             (let ((x 1)
+                   (y 2))
+               (swap! x y)
+               (assert-eq (list x y) '(2 1))))
+
+         (it "does not capture user variables named like introduced ones"
+             (let ((tmp 1)
+                   (other 2))
+               (swap! tmp other)
+               (assert-eq (list tmp other) '(2 1)))
+             (let ((t 5))
+               (assert-eq (my-or #f t) 5))
+             (let ((loop 0)
+                   (n 0))
+               (while (< n 3)
+                 (set! loop (+ loop 1))
+                 (set! n (+ n 1)))
+               (assert-eq loop 3)))
+
+         (it "resolves free identifiers where the macro is defined"
+             (assert-eq (let ((list vector)) (my-list 1)) '(1))
+             (assert-eq (let ((if list)) (choose #f 1 2)) 2)
+             (assert-eq (let ((macro-counter 99)) (get-macro-counter)) 1)
+             (set! macro-counter 5)
+             (assert-eq (let ((macro-counter 99)) (get-macro-counter)) 5)
+             (assert-eq (let ((x 1))
+                          (let-syntax ((m (syntax-rules () ((_) x))))
+                            (let ((x 2)) (m))))
+                        1))
+
+         (it "supports recursion and multiple rules"
+             (assert-eq (my-or) #f)
+             (assert-eq (my-or 1) 1)
+             (assert-eq (my-or #f #f 3) 3))
+
+         (it "supports ellipsis patterns"
+             (assert-eq (my-let ((a 1) (b 2)) (+ a b)) 3)
+             (assert-eq (nested (1 2 3) (4) (5 6)) '((1 2 3) (4) (5 6)))
+             (assert-eq (flatten-args (1 2) () (3)) '(1 2 3))
+             (assert-eq (last-two 1 2 3 4) '(3 4))
+             (assert-eq (last-two 1 2) '(1 2)))
+
+         (it "supports dotted patterns"
+             (assert-eq (dotted 1 2 3) '(1 (2 3)))
+             (assert-eq (dotted 1) '(1 ())))
+
+         (it "matches literals"
+             (assert-eq (my-cond (#f 1) (else 2)) 2)
+             (assert-eq (my-cond (#t 1) (else 2)) 1)
+             (assert-eq (literal-number 1) 'one)
+             (assert-eq (literal-number 2) 'other))
+
+         (it "supports custom and escaped ellipses"
+             (assert-eq (custom-ellipsis 1 2) '(1 2 ...))
+             (assert-eq (escaped-ellipsis 1) '(1 ...)))
+
+         (it "supports vector patterns and templates"
+             (assert-eq (vector-sum #(1 2 3)) 6)
+             (assert-eq (vector-swap #(1 2)) #(2 1 tmp))
+             (assert-eq (groups->vector (1 2 3) (4)) #((1 #(2 3)) (4 #())))
+             (assert-true (empty-vector? #()))
+             (assert-false (empty-vector? #(1)))
+             (assert-false (empty-vector? ()))
+             (assert-error (vector-sum (1 2 3))))
+
+         (it "reports uses that match no rule"
+             (assert-error (swap! 1))
+             (assert-eq (condition/type (ignore-errors (swap! 1))) 'syntax-error)))
+
+(context "let-syntax"
+
+         ()
+
+         (it "binds macros locally"
+             (assert-eq (let-syntax ((double (syntax-rules () ((_ x) (* 2 x)))))
+                          (double 21))
+                        42)
+             (assert-error (double 1)))
+
+         (it "lets letrec-syntax macros refer to each other"
+             (assert-eq (letrec-syntax ((my-and (syntax-rules ()
+                                                  ((_) #t)
+                                                  ((_ e) e)
+                                                  ((_ e r ...) (if e (my-and r ...) #f)))))
+                          (my-and 1 2 3))
+                        3)))
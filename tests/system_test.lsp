@@ -18,3 +18,26 @@
              (assert-eq (+ 1 2) 3)
              (assert-error (5 1 2))
              (assert-error ('list 1 2))))
+
+(define (bt-inner x)
+  (car x 1))
+
+(define (bt-outer y)
+  (+ 1 (bt-inner (* y 2))))
+
+(context "on-error backtraces"
+
+         ()
+
+         (it "passes only the condition to one argument handlers"
+             (assert-true (on-error (bt-outer 1) (lambda (err) (condition? err)))))
+
+         (it "passes a backtrace to two argument handlers"
+             (let ((bt (on-error (bt-outer 21) (lambda (err backtrace) backtrace))))
+               (assert-eq (length bt) 2)
+               (assert-eq (function: (car bt)) "bt-inner")
+               (assert-eq (args: (car bt)) '(42))
+               (assert-eq (expr: (car bt)) '(bt-inner (* y 2)))
+               (assert-true (string-suffix? ":26:8" (location: (car bt))))
+               (assert-eq (function: (cadr bt)) "bt-outer")
+               (assert-eq (args: (cadr bt)) '(21)))))
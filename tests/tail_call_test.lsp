@@ -0,0 +1,110 @@
+;;; -*- mode: Scheme -*-
+
+(define (count-down n)
+  (if (zero? n)
+      'done
+      (count-down (- n 1))))
+
+(define (count-with-cond n acc)
+  (cond ((zero? n) acc)
+        (else (count-with-cond (- n 1) (+ acc 1)))))
+
+(define (count-with-case n)
+  (case (zero? n)
+    ((#t) 'done)
+    (else (count-with-case (- n 1)))))
+
+(define (count-with-when n)
+  (when (> n 0)
+    (count-with-when (- n 1))))
+
+(define (count-with-unless n)
+  (unless (zero? n)
+    (count-with-unless (- n 1))))
+
+(define (count-with-begin n)
+  (begin
+    (if (zero? n)
+        'done
+        (count-with-begin (- n 1)))))
+
+(define (count-with-let n)
+  (let ((m (- n 1)))
+    (if (< m 0)
+        'done
+        (count-with-let m))))
+
+(define (count-with-let* n)
+  (let* ((m n)
+         (k (- m 1)))
+    (if (< k 0)
+        'done
+        (count-with-let* k))))
+
+(define (count-with-and n)
+  (and #t
+       (or (zero? n)
+           (count-with-and (- n 1)))))
+
+(define (count-with-do n)
+  (do ((i 0 (+ i 1)))
+      ((== i 1) (if (zero? n)
+                   'done
+                   (count-with-do (- n 1))))))
+
+(define (my-even? n)
+  (if (zero? n)
+      #t
+      (my-odd? (- n 1))))
+
+(define (my-odd? n)
+  (if (zero? n)
+      #f
+      (my-even? (- n 1))))
+
+(context "tail calls"
+
+         ()
+
+         (it "if"
+             (assert-eq (count-down 1000000) 'done))
+
+         (it "cond"
+             (assert-eq (count-with-cond 1000000 0) 1000000))
+
+         (it "case"
+             (assert-eq (count-with-case 1000000) 'done))
+
+         (it "when and unless"
+             (assert-nil (count-with-when 1000000))
+             (assert-nil (count-with-unless 1000000)))
+
+         (it "begin"
+             (assert-eq (count-with-begin 1000000) 'done))
+
+         (it "let family"
+             (assert-eq (count-with-let 1000000) 'done)
+             (assert-eq (count-with-let* 1000000) 'done)
+             (assert-eq (let loop ((i 0))
+                          (if (== i 1000000)
+                              i
+                              (loop (+ i 1))))
+                        1000000))
+
+         (it "do"
+             (assert-eq (count-with-do 1000000) 'done)
+             (assert-eq (do ((i 0 (+ i 1))) ((== i 1000000) i)) 1000000))
+
+         (it "and and or"
+             (assert-true (count-with-and 1000000)))
+
+         (it "mutual recursion"
+             (assert-true (my-even? 1000000))
+             (assert-false (my-odd? 1000000)))
+
+         (it "lambda called from a primitive"
+             (assert-eq (map (lambda (n) (count-down n)) '(10 1000000))
+                        '(done done)))
+
+         (it "errors in tail position"
+             (assert-error (count-down 'a))))
@@ -0,0 +1,79 @@
+;;; -*- mode: Scheme -*-
+
+(context "vectors"
+
+         ()
+
+         (it "literals"
+             (assert-true (vector? #(1 2 3)))
+             (assert-eq (vector-length #()) 0)
+             (assert-eq (length #(1 2 3)) 3)
+             (assert-eq (length #()) 0)
+             (assert-eq (vector-ref #(a (b c) "d") 1) '(b c))
+             (assert-eq (vector-ref '#(a b) 0) 'a)
+             (assert-false (vector? '(1 2))))
+
+         (it "construction"
+             (assert-eq (vector 1 2 3) #(1 2 3))
+             (assert-eq (make-vector 3 'x) #(x x x))
+             (assert-eq (vector-length (make-vector 4)) 4)
+             (assert-error (make-vector -1))
+             (assert-eq (list->vector '(1 2)) #(1 2))
+             (assert-error (list->vector 1)))
+
+         (it "quasiquote"
+             (let ((x 2))
+               (assert-eq `#(1 ,x ,@(list 3 4)) #(1 2 3 4))))
+
+         (it "ref and set"
+             (let ((v (make-vector 2 0)))
+               (assert-eq (vector-set! v 1 'a) 'a)
+               (assert-eq (vector-ref v 1) 'a)
+               (assert-eq v #(0 a))
+               (assert-error (vector-ref v 2))
+               (assert-error (vector-ref v -1))
+               (assert-error (vector-set! v 2 'a))
+               (assert-error (vector-ref '(1 2) 0))
+               (assert-error (vector-ref v 'a))))
+
+         (it "map and for-each"
+             (assert-eq (vector-map (lambda (x) (* x x)) #(1 2 3)) #(1 4 9))
+             (assert-eq (vector-map + #(1 2 3) #(10 20)) #(11 22))
+             (let ((total 0))
+               (vector-for-each (lambda (x) (set! total (+ total x))) #(1 2 3))
+               (assert-eq total 6))
+             (assert-error (vector-map 1 #(1)))
+             (assert-error (vector-map car '(1))))
+
+         (it "fill"
+             (let ((v (make-vector 4 0)))
+               (vector-fill! v 1)
+               (assert-eq v #(1 1 1 1))
+               (vector-fill! v 2 1 3)
+               (assert-eq v #(1 2 2 1))
+               (assert-error (vector-fill! v 0 3 1))))
+
+         (it "subvector and grow"
+             (assert-eq (subvector #(a b c d) 1 3) #(b c))
+             (assert-eq (subvector #(a b c d) 0 0) #())
+             (assert-error (subvector #(a b) 1 3))
+             (let ((g (vector-grow #(a b) 4)))
+               (assert-eq (vector-length g) 4)
+               (assert-eq (vector-ref g 1) 'b))
+             (assert-error (vector-grow #(a b) 1)))
+
+         (it "conversion to lists"
+             (assert-eq (vector->list #(1 2 3)) '(1 2 3))
+             (assert-eq (vector->list #(1 2 3) 1) '(2 3))
+             (assert-eq (vector->list #(1 2 3) 1 2) '(2))
+             (assert-eq (vector->list #()) '()))
+
+         (it "equality, copying and printing"
+             (assert-true (equal? #(1 (2 3)) (vector 1 (list 2 3))))
+             (assert-false (equal? #(1 2) #(1 2 3)))
+             (let* ((v (vector (list 1 2)))
+                    (c (copy v)))
+               (assert-eq c v)
+               (set-car! (vector-ref c 0) 'x)
+               (assert-eq (vector-ref v 0) '(1 2)))
+             (assert-eq (str #(1 "a" #(b))) "#(1 \"a\" #(b))")))
@@ -23,6 +23,7 @@ const (
 	HEXNUMBER
 	BINARYNUMBER
 	FLOAT
+	RATIONAL
 	STRING
 	QUOTE
 	BACKQUOTE
@@ -34,6 +35,8 @@ const (
 	RBRACKET
 	LBRACE
 	RBRACE
+	HASHLPAREN
+	CHARACTER
 	PERIOD
 	TRUE
 	FALSE
@@ -44,32 +47,52 @@ const (
 type Tokenizer struct {
 	LookaheadToken int
 	LookaheadLit   string
-	Source         *bufrr.Reader
+	Source         bufrr.RunePeeker
 	CurrentCh      rune
 	NextCh         rune
 	Eof            bool
 	AlmostEof      bool
+	File           string
+	Line           int
+	Column         int
+	TokenLine      int
+	TokenColumn    int
+	lazy           bool
+	pending        bool
 }
 
 var mostRecentFileTokenizer *Tokenizer
 var mostRecentlyUsedFile *os.File
 
 func NewTokenizer(scanner *bufrr.Reader) *Tokenizer {
-	t := &Tokenizer{Source: scanner}
+	return NewTokenizerFromReader(scanner, "")
+}
+
+func NewTokenizerFromString(src string) *Tokenizer {
+	return NewTokenizer(bufrr.NewReader(strings.NewReader(src)))
+}
+
+func NewTokenizerFromReader(scanner bufrr.RunePeeker, file string) *Tokenizer {
+	t := &Tokenizer{Source: scanner, Line: 1, File: file}
 	t.Advance()
 	t.ConsumeToken()
 	return t
 }
 
-func NewTokenizerFromString(src string) *Tokenizer {
-	return NewTokenizer(bufrr.NewReader(strings.NewReader(src)))
+// A tokenizer that doesn't read the token following an expression until it
+// is asked for, so that reading an expression from a port leaves the rest of
+// the input in the port.
+func newLazyTokenizer(scanner bufrr.RunePeeker, file string) *Tokenizer {
+	t := NewTokenizerFromReader(scanner, file)
+	t.lazy = true
+	return t
 }
 
 func NewTokenizerFromFile(src *os.File) *Tokenizer {
 	if mostRecentlyUsedFile == src {
 		return mostRecentFileTokenizer
 	} else {
-		t := NewTokenizer(bufrr.NewReader(src))
+		t := NewTokenizerFromReader(bufrr.NewReader(src), src.Name())
 		mostRecentFileTokenizer = t
 		mostRecentlyUsedFile = src
 		return t
@@ -77,6 +100,13 @@ func NewTokenizerFromFile(src *os.File) *Tokenizer {
 }
 
 func (self *Tokenizer) Advance() {
+	if self.CurrentCh == '\n' {
+		self.Line++
+		self.Column = 1
+	} else {
+		self.Column++
+	}
+
 	var err error
 	self.CurrentCh, _, err = self.Source.ReadRune()
 	if err == io.EOF || self.CurrentCh == -1 {
@@ -93,9 +123,18 @@ func (self *Tokenizer) Advance() {
 }
 
 func (self *Tokenizer) NextToken() (token int, lit string) {
+	if self.pending {
+		self.pending = false
+		self.readLookahead()
+	}
 	return self.LookaheadToken, self.LookaheadLit
 }
 
+// The position of the first character of the lookahead token.
+func (self *Tokenizer) Location() *SourceLocation {
+	return &SourceLocation{File: self.File, Line: self.TokenLine, Column: self.TokenColumn}
+}
+
 func (self *Tokenizer) isSymbolCharacter(ch rune) bool {
 	return unicode.IsGraphic(ch) && !unicode.IsSpace(ch) && !strings.ContainsRune("();\"'`|[]{}#,", ch)
 }
@@ -109,6 +148,21 @@ func (self *Tokenizer) readSymbol() (token int, lit string) {
 	return SYMBOL, string(buffer)
 }
 
+// Reads the name of a character after #\. The first character is taken
+// whatever it is, so that #\( and #\space both read.
+func (self *Tokenizer) readCharacter() (token int, lit string) {
+	if self.isEof() {
+		return ILLEGAL, "#\\"
+	}
+	buffer := []rune{self.CurrentCh}
+	self.Advance()
+	for !self.isEof() && self.isSymbolCharacter(self.CurrentCh) {
+		buffer = append(buffer, self.CurrentCh)
+		self.Advance()
+	}
+	return CHARACTER, string(buffer)
+}
+
 func isHexChar(ch rune) bool {
 	switch ch {
 	case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
@@ -162,15 +216,32 @@ func (self *Tokenizer) readBinaryNumber() (token int, lit string) {
 func (self *Tokenizer) readNumber() (token int, lit string) {
 	buffer := make([]rune, 0, 1)
 	isFloat := false
+	isRational := false
 	sawDecimal := false
+	sawExponent := false
 	firstChar := true
 	for !self.isEof() {
 		ch := rune(self.CurrentCh)
-		if ch == '.' && !sawDecimal {
+		if ch == '/' && !sawDecimal && !isRational && !firstChar && unicode.IsNumber(self.NextCh) {
+			isRational = true
+			buffer = append(buffer, self.CurrentCh)
+			self.Advance()
+		} else if ch == '.' && !sawDecimal && !isRational {
+			isFloat = true
+			sawDecimal = true
+			buffer = append(buffer, self.CurrentCh)
+			self.Advance()
+		} else if (ch == 'e' || ch == 'E') && !sawExponent && !isRational && !firstChar && (unicode.IsNumber(self.NextCh) || self.NextCh == '+' || self.NextCh == '-') {
+			// an exponent, which may be signed, as floats are printed
 			isFloat = true
 			sawDecimal = true
+			sawExponent = true
 			buffer = append(buffer, self.CurrentCh)
 			self.Advance()
+			if self.CurrentCh == '+' || self.CurrentCh == '-' {
+				buffer = append(buffer, self.CurrentCh)
+				self.Advance()
+			}
 		} else if firstChar && ch == '-' {
 			buffer = append(buffer, self.CurrentCh)
 			self.Advance()
@@ -186,6 +257,8 @@ func (self *Tokenizer) readNumber() (token int, lit string) {
 	lit = string(buffer)
 	if isFloat {
 		token = FLOAT
+	} else if isRational {
+		token = RATIONAL
 	} else {
 		token = NUMBER
 	}
@@ -235,6 +308,7 @@ func (self *Tokenizer) readNextToken() (token int, lit string) {
 		}
 	}
 
+	self.TokenLine, self.TokenColumn = self.Line, self.Column
 	if self.CurrentCh == '0' && self.NextCh == 'x' {
 		self.Advance()
 		self.Advance()
@@ -295,6 +369,12 @@ func (self *Tokenizer) readNextToken() (token int, lit string) {
 		} else if self.CurrentCh == 'b' {
 			self.Advance()
 			return self.readBinaryNumber()
+		} else if self.CurrentCh == '(' {
+			self.Advance()
+			return HASHLPAREN, "#("
+		} else if self.CurrentCh == '\\' {
+			self.Advance()
+			return self.readCharacter()
 		} else {
 			return ILLEGAL, fmt.Sprintf("#%c", self.NextCh)
 		}
@@ -316,8 +396,16 @@ func (self *Tokenizer) readNextToken() (token int, lit string) {
 }
 
 func (self *Tokenizer) ConsumeToken() {
+	if self.lazy {
+		self.pending = true
+		return
+	}
+	self.readLookahead()
+}
+
+func (self *Tokenizer) readLookahead() {
 	self.LookaheadToken, self.LookaheadLit = self.readNextToken()
 	if self.LookaheadToken == COMMENT { // skip comments
-		self.ConsumeToken()
+		self.readLookahead()
 	}
 }
@@ -142,6 +142,20 @@ func (s *TokenizerSuite) TestFloat(c *C) {
 	c.Assert(lit, Equals, "12.345")
 }
 
+func (s *TokenizerSuite) TestRational(c *C) {
+	t := NewTokenizerFromString("-1/3 a")
+	tok, lit := t.NextToken()
+	c.Assert(tok, Equals, RATIONAL)
+	c.Assert(lit, Equals, "-1/3")
+}
+
+func (s *TokenizerSuite) TestIntegerFollowedBySlash(c *C) {
+	t := NewTokenizerFromString("1/a")
+	tok, lit := t.NextToken()
+	c.Assert(tok, Equals, NUMBER)
+	c.Assert(lit, Equals, "1")
+}
+
 func (s *TokenizerSuite) TestNegativeFloat(c *C) {
 	t := NewTokenizerFromString("-12.345 a")
 	tok, lit := t.NextToken()
@@ -149,6 +163,21 @@ func (s *TokenizerSuite) TestNegativeFloat(c *C) {
 	c.Assert(lit, Equals, "-12.345")
 }
 
+func (s *TokenizerSuite) TestFloatWithExponent(c *C) {
+	t := NewTokenizerFromString("1e+30 -2.5E-3 4e2 a")
+	tok, lit := t.NextToken()
+	c.Assert(tok, Equals, FLOAT)
+	c.Assert(lit, Equals, "1e+30")
+	t.ConsumeToken()
+	tok, lit = t.NextToken()
+	c.Assert(tok, Equals, FLOAT)
+	c.Assert(lit, Equals, "-2.5E-3")
+	t.ConsumeToken()
+	tok, lit = t.NextToken()
+	c.Assert(tok, Equals, FLOAT)
+	c.Assert(lit, Equals, "4e2")
+}
+
 func (s *TokenizerSuite) TestString(c *C) {
 	t := NewTokenizerFromString(`"hi" a`)
 	tok, lit := t.NextToken()
@@ -337,3 +366,21 @@ func (s *TokenizerSuite) TestTrue(c *C) {
 	c.Assert(tok, Equals, TRUE)
 	c.Assert(lit, Equals, `#t`)
 }
+
+func (s *TokenizerSuite) TestVectorStart(c *C) {
+	t := NewTokenizerFromString(`#(a)`)
+	tok, lit := t.NextToken()
+	c.Assert(tok, Equals, HASHLPAREN)
+	c.Assert(lit, Equals, `#(`)
+}
+
+func (s *TokenizerSuite) TestTokenLocation(c *C) {
+	t := NewTokenizerFromString("(a\n  bc)")
+	c.Assert(t.Location().String(), Equals, "1:1")
+	t.ConsumeToken()
+	c.Assert(t.Location().String(), Equals, "1:2")
+	t.ConsumeToken()
+	_, lit := t.NextToken()
+	c.Assert(lit, Equals, "bc")
+	c.Assert(t.Location().String(), Equals, "2:3")
+}